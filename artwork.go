@@ -0,0 +1,104 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// An ArtworkProvider finds the cover art of a recording, for instance from the Cover Art Archive or from
+// a folder.jpg next to the file. Artwork is given the fields of the tag and returns the picture with
+// its MIMEType set if known; the Type is taken as FrontCover if it is OtherPicture.
+type ArtworkProvider interface {
+	Artwork(md Metadata) (Picture, error)
+}
+
+// ArtworkProviderFunc lets an ordinary function serve as an ArtworkProvider.
+type ArtworkProviderFunc func(md Metadata) (Picture, error)
+
+// Artwork calls fn(md).
+func (fn ArtworkProviderFunc) Artwork(md Metadata) (Picture, error) {
+	return fn(md)
+}
+
+// An ArtworkOption sets the policy EnsureArtwork holds fetched pictures to.
+type ArtworkOption func(*artwork_config)
+
+type artwork_config struct {
+	max_bytes  int
+	max_width  int
+	max_height int
+	mime_types []string
+}
+
+// MaxArtworkBytes rejects pictures of more than n bytes.
+func MaxArtworkBytes(n int) ArtworkOption {
+	return func(cfg *artwork_config) {
+		cfg.max_bytes = n
+	}
+}
+
+// MaxArtworkDimensions rejects pictures wider than width or higher than height pixels.
+func MaxArtworkDimensions(width, height int) ArtworkOption {
+	return func(cfg *artwork_config) {
+		cfg.max_width, cfg.max_height = width, height
+	}
+}
+
+// ArtworkTypes sets the MIME types of the pictures accepted, which are image/jpeg and image/png by default.
+func ArtworkTypes(mime_types ...string) ArtworkOption {
+	return func(cfg *artwork_config) {
+		cfg.mime_types = append(make([]string, 0, len(mime_types)), mime_types...)
+	}
+}
+
+// EnsureArtwork returns the tag as it is if it holds a picture, and otherwise a copy with the picture
+// provider finds embedded as an APIC frame. The picture must keep to the policy set by opts; its format
+// is worked out from the data when its MIMEType is not set. The tag is returned unchanged along with the
+// error if the provider fails or the picture is rejected.
+func EnsureArtwork(id3tag ID3Tag, provider ArtworkProvider, opts ...ArtworkOption) (ID3Tag, error) {
+	if _, err := id3tag.pictures(); err == nil {
+		return id3tag, nil
+	}
+	cfg := artwork_config{mime_types: []string{"image/jpeg", "image/png"}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	pic, err := provider.Artwork(id3tag.GetMetadata())
+	if err != nil {
+		return id3tag, err
+	}
+	if len(pic.Data) == 0 {
+		return id3tag, errors.New("Artwork provider returned an empty picture")
+	}
+	if cfg.max_bytes > 0 && len(pic.Data) > cfg.max_bytes {
+		return id3tag, errors.New(fmt.Sprintf("Picture of %v bytes exceeds the limit of %v bytes", len(pic.Data), cfg.max_bytes))
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(pic.Data))
+	if pic.MIMEType == "" && err == nil {
+		pic.MIMEType = "image/" + format
+	}
+	accepted := false
+	for _, mime_type := range cfg.mime_types {
+		accepted = accepted || mime_type == pic.MIMEType
+	}
+	if !accepted {
+		return id3tag, errors.New(fmt.Sprintf("Picture of type %q is not accepted", pic.MIMEType))
+	}
+	if cfg.max_width > 0 || cfg.max_height > 0 {
+		if err != nil {
+			return id3tag, errors.New(fmt.Sprintf("Could not determine the size of a %v picture: %v", pic.MIMEType, err))
+		}
+		if cfg.max_width > 0 && config.Width > cfg.max_width || cfg.max_height > 0 && config.Height > cfg.max_height {
+			return id3tag, errors.New(fmt.Sprintf("Picture of %vx%v pixels exceeds the limit of %vx%v", config.Width, config.Height, cfg.max_width, cfg.max_height))
+		}
+	}
+	if pic.Type == OtherPicture {
+		pic.Type = FrontCover
+	}
+	return id3tag.WithFrame(encode_picture(id3tag.Version, pic)), nil
+}
@@ -0,0 +1,47 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestEnsureArtwork(t *testing.T) {
+	var img bytes.Buffer
+	png.Encode(&img, image.NewGray(image.Rect(0, 0, 100, 80)))
+	calls := 0
+	provider := ArtworkProviderFunc(func(md Metadata) (Picture, error) {
+		calls++
+		if md.Album != "Album" {
+			t.Errorf("Provider was not given the album, got %+v", md)
+		}
+		return Picture{Data: img.Bytes()}, nil
+	})
+	id3tag, err := NewTagBuilder().Album("Album").Build(Version24)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := EnsureArtwork(id3tag, provider, MaxArtworkDimensions(64, 64)); err == nil {
+		t.Errorf("Expected a picture larger than the limit to be rejected")
+	}
+	if _, err := EnsureArtwork(id3tag, provider, ArtworkTypes("image/jpeg")); err == nil {
+		t.Errorf("Expected a PNG picture to be rejected")
+	}
+	with_art, err := EnsureArtwork(id3tag, provider, MaxArtworkDimensions(500, 500), MaxArtworkBytes(1<<20))
+	if err != nil {
+		t.Fatalf("Error in embedding artwork: %v", err)
+	}
+	pics, err := with_art.GetPictures()
+	if err != nil || len(pics) != 1 || pics[0].MIMEType != "image/png" || pics[0].Type != FrontCover || !bytes.Equal(pics[0].Data, img.Bytes()) {
+		t.Errorf("Unexpected pictures %v, %v", len(pics), err)
+	}
+
+	calls = 0
+	if again, err := EnsureArtwork(with_art, provider); err != nil || len(again.Frames) != len(with_art.Frames) || calls != 0 {
+		t.Errorf("Expected a tag with a picture to be left alone, got %v calls, %v", calls, err)
+	}
+}
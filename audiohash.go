@@ -0,0 +1,111 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+)
+
+// AudioHash writes the audio of r into h, leaving out the metadata around it: ID3v2 tags at the start,
+// and ID3v1 tags (including the extended TAG+ block), APEv2 tags and ID3v2 tags with a footer at the end.
+// Files with the same audio therefore hash alike however their tags were edited. r is read from the
+// start whatever its current position.
+func AudioHash(r io.ReadSeeker, h hash.Hash) error {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	start := int64(0)
+	header := make([]byte, 10)
+	for end-start >= 10 {
+		if err := read_at(r, start, header); err != nil {
+			return err
+		}
+		length, ok := stream_tag_length(header)
+		if !ok || start+10+length > end {
+			break
+		}
+		start += 10 + length
+	}
+
+	for {
+		trailer, err := trailing_tag_length(r, start, end)
+		if err != nil {
+			return err
+		}
+		if trailer == 0 {
+			break
+		}
+		end -= trailer
+	}
+
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.CopyN(h, r, end-start)
+	return err
+}
+
+// trailing_tag_length returns the length of the tag ending at end, or 0 if there is none after start
+func trailing_tag_length(r io.ReadSeeker, start, end int64) (int64, error) {
+	if end-start >= 128 {
+		buf := make([]byte, 4)
+		if err := read_at(r, end-128, buf[0:3]); err != nil {
+			return 0, err
+		}
+		if string(buf[0:3]) == "TAG" {
+			//an extended TAG+ block of 227 bytes may precede the ID3v1 tag
+			if end-start >= 128+227 {
+				if err := read_at(r, end-128-227, buf); err != nil {
+					return 0, err
+				}
+				if string(buf) == "TAG+" {
+					return 128 + 227, nil
+				}
+			}
+			return 128, nil
+		}
+	}
+
+	if end-start >= 32 {
+		footer := make([]byte, 32)
+		if err := read_at(r, end-32, footer); err != nil {
+			return 0, err
+		}
+		if string(footer[0:8]) == "APETAGEX" {
+			//the size counts the items and the footer; a header of another 32 bytes is flagged in bit 31
+			length := int64(binary.LittleEndian.Uint32(footer[12:16]))
+			if binary.LittleEndian.Uint32(footer[20:24])&(1<<31) != 0 {
+				length += 32
+			}
+			if length >= 32 && length <= end-start {
+				return length, nil
+			}
+		}
+	}
+
+	if end-start >= 20 {
+		footer := make([]byte, 10)
+		if err := read_at(r, end-10, footer); err != nil {
+			return 0, err
+		}
+		if string(footer[0:3]) == "3DI" {
+			footer[0], footer[1], footer[2] = 'I', 'D', '3'
+			if length, ok := stream_tag_length(footer); ok && length+10 <= end-start {
+				return length + 10, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+func read_at(r io.ReadSeeker, offset int64, buf []byte) error {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(r, buf)
+	return err
+}
@@ -0,0 +1,56 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+func TestAudioHash(t *testing.T) {
+	v23, err := os.ReadFile("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v24, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, _ := ReadID3Bytes(v23)
+	audio := v23[10+id3tag.Size : len(v23)]
+
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	extended := append([]byte("TAG+"), make([]byte, 223)...)
+	ape := append([]byte("APETAGEX\xd0\x07\x00\x00"), make([]byte, 20)...)
+	binary.LittleEndian.PutUint32(ape[12:16], 32+5)
+	binary.LittleEndian.PutUint32(ape[20:24], 1<<31)
+	ape = append(append(append([]byte("APETAGEX"), make([]byte, 24)...), "items"...), ape...)
+	footer_tag := append(make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Footer"))), "3DI\x04\x00\x10"...)
+	footer_tag[5] = 0x10
+	footer_tag = append(footer_tag, footer_tag[6:10]...)
+
+	want := sha256.Sum256(audio)
+	cases := map[string][]byte{
+		"v2.3":           v23,
+		"v2.4":           v24,
+		"untagged":       audio,
+		"two tags":       append(append([]byte(nil), v23[0:10+id3tag.Size]...), v24...),
+		"id3v1":          append(append([]byte(nil), v23...), id3v1...),
+		"extended id3v1": append(append(append([]byte(nil), v23...), extended...), id3v1...),
+		"ape and id3v1":  append(append(append([]byte(nil), v24...), ape...), id3v1...),
+		"appended id3v2": append(append([]byte(nil), audio...), footer_tag...),
+	}
+	for name, data := range cases {
+		h := sha256.New()
+		if err := AudioHash(bytes.NewReader(data), h); err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(h.Sum(nil), want[:]) {
+			t.Errorf("%v: audio hash differs from that of the bare audio", name)
+		}
+	}
+}
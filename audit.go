@@ -0,0 +1,69 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Finding is a frame that Audit thinks may hold personal data. Index is the position of the frame in
+// the tag's Frames.
+type Finding struct {
+	FrameID string
+	Index   int
+	Reason  string
+}
+
+var email_pattern = &lazy_regexp{expr: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`}
+var account_pattern = &lazy_regexp{expr: `(?i)\b(account|customer|purchased by|bought by|user ?id|order (no|number|id))\b`}
+
+// Audit lists the frames of the tag likely to contain personal data, for review before a file is
+// published: POPM frames and UFID owners with email addresses, OWNE purchase records, PRIV payloads, which
+// stores use to mark purchases, TOWN file owners, and comments and TXXX values mentioning email addresses
+// or accounts. It errs on the side of reporting too much; Sanitize with UploadPolicy drops most of these.
+func (id3tag ID3Tag) Audit() []Finding {
+	ret := make([]Finding, 0)
+	add := func(j int, frame ID3Frame, reason string, args ...interface{}) {
+		ret = append(ret, Finding{frame.FrameID, j, fmt.Sprintf(reason, args...)})
+	}
+	for j, frame := range id3tag.Frames {
+		if frame.Compression || frame.Encryption || frame.Unsynchronisation {
+			continue
+		}
+		frameid := v23_frameid(id3tag.Version, frame.FrameID)
+		switch frameid {
+		case "POPM":
+			if email, _ := split_text(0, frame.Data); email_pattern.get().Match(email) {
+				add(j, frame, "Rating by email address %q", decodeISO88591(email))
+			}
+		case "UFID":
+			if owner, _ := split_text(0, frame.Data); email_pattern.get().Match(owner) {
+				add(j, frame, "Identifier owned by email address %q", decodeISO88591(owner))
+			}
+		case "OWNE":
+			add(j, frame, "Purchase record with price, date and seller")
+		case "PRIV":
+			owner, _ := split_text(0, frame.Data)
+			add(j, frame, "Private data owned by %q", decodeISO88591(owner))
+		case "TOWN":
+			owner, _ := id3tag.GetTextFrameData("TOWN")
+			add(j, frame, "File owner %q", owner)
+		case "COMM", "TXXX":
+			var txt string
+			if frameid == "COMM" && len(frame.Data) >= 4 {
+				_, text := split_text(frame.Data[0], frame.Data[4:len(frame.Data)])
+				txt, _ = id3tag.decodetext(frame.Data[0], text)
+			} else if frameid == "TXXX" && len(frame.Data) > 0 {
+				values, _ := id3tag.decodetext_values(frame.Data[0], frame.Data[1:len(frame.Data)])
+				txt = strings.Join(values, " ")
+			}
+			if email := email_pattern.get().FindString(txt); email != "" {
+				add(j, frame, "Text mentions email address %q", email)
+			} else if hint := account_pattern.get().FindString(txt); hint != "" {
+				add(j, frame, "Text mentions %q", hint)
+			}
+		}
+	}
+	return ret
+}
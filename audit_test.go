@@ -0,0 +1,58 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "POPM", 0, []byte("someone@example.com\x00\x80")),
+		make_frame(4, "POPM", 0, []byte("Windows Media Player 9 Series\x00\x80")),
+		make_frame(4, "UFID", 0, []byte("http://musicbrainz.org\x00id")),
+		make_frame(4, "PRIV", 0, []byte("www.amazon.com\x00\x01\x02")),
+		make_frame(4, "OWNE", 0, []byte("\x00USD0.99\x0020130101Store")),
+		make_frame(4, "COMM", 0, []byte("\x03eng\x00Purchased by Jane Doe")),
+		make_frame(4, "COMM", 0, []byte("\x03eng\x00Great song")),
+		make_frame(4, "TXXX", 0, []byte("\x03contact\x00jane@example.org")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := []Finding{
+		{"POPM", 1, `Rating by email address "someone@example.com"`},
+		{"PRIV", 4, `Private data owned by "www.amazon.com"`},
+		{"OWNE", 5, "Purchase record with price, date and seller"},
+		{"COMM", 6, `Text mentions "Purchased by"`},
+		{"TXXX", 8, `Text mentions email address "jane@example.org"`},
+	}
+	if got := id3tag.Audit(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := Sanitize(id3tag, UploadPolicy).Audit(); len(got) != 1 || got[0].FrameID != "COMM" {
+		t.Errorf("Expected only the comment to remain after sanitizing, got %v", got)
+	}
+}
+
+func TestAuditV22(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Title")),
+		make_frame(2, "POP", 0, []byte("someone@example.com\x00\x80")),
+		make_frame(2, "COM", 0, []byte("\x00eng\x00Purchased by Jane Doe")),
+		make_frame(2, "TXX", 0, []byte("\x00contact\x00jane@example.org")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := []Finding{
+		{"POP", 1, `Rating by email address "someone@example.com"`},
+		{"COM", 2, `Text mentions "Purchased by"`},
+		{"TXX", 3, `Text mentions email address "jane@example.org"`},
+	}
+	if got := id3tag.Audit(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// BackupSuffix is appended to the path of a file to name the sidecar file WithBackup saves its tag to.
+const BackupSuffix = ".id3bak"
+
+// WithBackup makes WriteFile, Compact and IncrementPlayCount save the raw bytes of the tag the file has,
+// padding included, to a sidecar file named by appending BackupSuffix to its path before changing it, so
+// RestoreFromSidecar can undo the write. Files without a tag get an empty sidecar. A sidecar that already exists is kept as it is, so it
+// holds the tag the file had before the first backed up write however many writes follow.
+func WithBackup() WriteOption {
+	return func(cfg *write_config) {
+		cfg.backup = true
+	}
+}
+
+// RestoreFromSidecar puts back the tag saved by WriteFile with WithBackup in place of the tag the file at
+// path has, removing it if the file had none, and then removes the sidecar file. The audio of the file is
+// left as it is.
+func RestoreFromSidecar(path string) error {
+	sidecar := path + BackupSuffix
+	buf, err := os.ReadFile(sidecar)
+	if err != nil {
+		return err
+	}
+	if len(buf) > 0 {
+		if skip, ok := stream_tag_length(buf); !ok || 10+skip != int64(len(buf)) {
+			return errors.New("Sidecar " + sidecar + " does not hold an ID3v2 tag")
+		}
+	}
+
+	if err := write_raw_tag(path, buf, false); err != nil {
+		return err
+	}
+	return os.Remove(sidecar)
+}
+
+// backup_tag saves the first length bytes of fil, the tag of the file at path, to its sidecar file unless
+// there already is one. The sidecar is written to a temporary file renamed into place once complete, so it
+// is never left holding part of a tag.
+func backup_tag(path string, fil *os.File, length int64) error {
+	sidecar := path + BackupSuffix
+	if _, err := os.Stat(sidecar); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	buf := make([]byte, length)
+	if _, err := fil.ReadAt(buf, 0); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(sidecar), "."+filepath.Base(sidecar)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //fails harmlessly once the rename is done
+	defer tmp.Close()
+	if _, err := tmp.Write(buf); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), sidecar)
+}
@@ -0,0 +1,97 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "song.mp3")
+	original := append(make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00Title"))), make([]byte, 20)...)
+	original[9] += 20
+	file := append(append([]byte(nil), original...), "audio"...)
+	if err := os.WriteFile(path, file, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	id3tag, err := ReadID3Bytes(file)
+	if err != nil {
+		t.Fatalf("Error in reading the tag: %v", err)
+	}
+	//the first write fits in place, the second needs the file rewritten
+	if err := WriteFile(path, id3tag.SetText("TPE1", "Artist"), WithBackup()); err != nil {
+		t.Fatalf("Error in writing: %v", err)
+	}
+	if err := WriteFile(path, id3tag.SetText("TPE1", "An artist with a name too long to fit"), WithBackup()); err != nil {
+		t.Fatalf("Error in writing again: %v", err)
+	}
+	saved, err := os.ReadFile(path + BackupSuffix)
+	if err != nil || !bytes.Equal(saved, original) {
+		t.Errorf("Sidecar holds % X, expected the original tag % X, %v", saved, original, err)
+	}
+
+	if err := RestoreFromSidecar(path); err != nil {
+		t.Fatalf("Error in restoring: %v", err)
+	}
+	if restored, _ := os.ReadFile(path); !bytes.Equal(restored, file) {
+		t.Errorf("Restored file is % X, expected % X", restored, file)
+	}
+	if _, err := os.Stat(path + BackupSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Sidecar was not removed: %v", err)
+	}
+	if err := RestoreFromSidecar(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Restoring without a sidecar gave %v", err)
+	}
+
+	//a file without a tag gets it removed again
+	if err := os.WriteFile(path, []byte("audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, id3tag, WithBackup()); err != nil {
+		t.Fatalf("Error in writing to untagged file: %v", err)
+	}
+	if err := RestoreFromSidecar(path); err != nil {
+		t.Fatalf("Error in restoring untagged file: %v", err)
+	}
+	if restored, _ := os.ReadFile(path); string(restored) != "audio" {
+		t.Errorf("Restored untagged file is %q", restored)
+	}
+
+	if err := os.WriteFile(path+BackupSuffix, []byte("not a tag"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreFromSidecar(path); err == nil {
+		t.Errorf("Restored from an invalid sidecar")
+	}
+}
+
+func TestBackupCompactAndPlayCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.mp3")
+	original := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")), make([]byte, 100))
+	file := append(append([]byte(nil), original...), "audio"...)
+	if err := os.WriteFile(path, file, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if reclaimed, err := Compact(path, 0, WithBackup()); err != nil || reclaimed != 100 {
+		t.Fatalf("Unexpected compaction %v, %v", reclaimed, err)
+	}
+	if err := IncrementPlayCount(path, WithBackup()); err != nil {
+		t.Fatalf("Error in incrementing play count: %v", err)
+	}
+	if saved, err := os.ReadFile(path + BackupSuffix); err != nil || !bytes.Equal(saved, original) {
+		t.Errorf("Sidecar holds % X, expected the original tag % X, %v", saved, original, err)
+	}
+	if err := RestoreFromSidecar(path); err != nil {
+		t.Fatalf("Error in restoring: %v", err)
+	}
+	if restored, _ := os.ReadFile(path); !bytes.Equal(restored, file) {
+		t.Errorf("Restored file is % X, expected % X", restored, file)
+	}
+}
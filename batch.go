@@ -0,0 +1,170 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// A Result is the outcome of extracting the tag of one file. Tag and Metadata are only set if Err is nil.
+// Files read as Scan reads them only have a Tag if they start with an ID3v2 tag.
+type Result struct {
+	Path     string
+	Tag      ID3Tag
+	Metadata Metadata
+	Err      error
+}
+
+// BatchError is returned by ExtractBatch when some of the files failed. The Results of the failed files
+// carry the individual errors.
+type BatchError struct {
+	Failed int
+	Total  int
+}
+
+func (err *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d files failed", err.Failed, err.Total)
+}
+
+// ExtractBatch reads the tags of the files at paths using up to workers goroutines, or one if workers is
+// less than one. One Result is returned for each path, in the order of paths, whether or not the file
+// could be read. The error is a *BatchError if any file failed and nil otherwise.
+func ExtractBatch(paths []string, workers int, opts ...Option) ([]Result, error) {
+	return extract_batch(paths, workers, func(path string) (ID3Tag, Metadata, error) {
+		fil, err := os.Open(path)
+		if err != nil {
+			return ID3Tag{}, Metadata{}, err
+		}
+		defer fil.Close()
+		return with_metadata(ReadID3(fil, opts...))
+	})
+}
+
+// ExtractBatchFS is like ExtractBatch but opens the files in fsys
+func ExtractBatchFS(fsys fs.FS, paths []string, workers int, opts ...Option) ([]Result, error) {
+	return extract_batch(paths, workers, func(path string) (ID3Tag, Metadata, error) {
+		return with_metadata(ReadFileFS(fsys, path, opts...))
+	})
+}
+
+// with_metadata adds the Metadata of id3tag to what reading it returned
+func with_metadata(id3tag ID3Tag, err error) (ID3Tag, Metadata, error) {
+	if err != nil {
+		return ID3Tag{}, Metadata{}, err
+	}
+	return id3tag, id3tag.GetMetadata(), nil
+}
+
+// ReadFileFS reads the tag at the start of the file name in fsys
+func ReadFileFS(fsys fs.FS, name string, opts ...Option) (ID3Tag, error) {
+	fil, err := fsys.Open(name)
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	defer fil.Close()
+	return ReadID3(fil, opts...)
+}
+
+// Scan extracts the metadata of all files with the extension of a registered format, as
+// HasFormatExtension tells, in the tree rooted at root in fsys, using up to workers goroutines as
+// ExtractBatchFS does. Files starting with an ID3v2 tag are read with ReadID3 and opts, and other files
+// with ReadMetadata. Results are in lexical order of path. Errors walking the tree, such as a directory
+// that cannot be read, are returned as they are along with no results.
+func Scan(fsys fs.FS, root string, workers int, opts ...Option) ([]Result, error) {
+	paths := make([]string, 0)
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() && HasFormatExtension(name) {
+			paths = append(paths, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ExtractMediaFS(fsys, paths, workers, opts...)
+}
+
+// ExtractMediaFS is like ExtractBatchFS but reads the files as Scan does, so that files of every
+// registered format yield their Metadata
+func ExtractMediaFS(fsys fs.FS, paths []string, workers int, opts ...Option) ([]Result, error) {
+	return extract_batch(paths, workers, func(name string) (ID3Tag, Metadata, error) {
+		return read_media_fs(fsys, name, opts...)
+	})
+}
+
+// read_media_fs reads the file name in fsys as Scan does
+func read_media_fs(fsys fs.FS, name string, opts ...Option) (ID3Tag, Metadata, error) {
+	fil, err := fsys.Open(name)
+	if err != nil {
+		return ID3Tag{}, Metadata{}, err
+	}
+	defer fil.Close()
+	r, ok := fil.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(fil)
+		if err != nil {
+			return ID3Tag{}, Metadata{}, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	head := make([]byte, 3)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return ID3Tag{}, Metadata{}, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return ID3Tag{}, Metadata{}, err
+	}
+	if string(head[0:n]) == "ID3" {
+		return with_metadata(ReadID3(r, opts...))
+	}
+	md, _, err := ReadMetadata(r)
+	if err != nil {
+		return ID3Tag{}, Metadata{}, err
+	}
+	return ID3Tag{}, md, nil
+}
+
+func extract_batch(paths []string, workers int, read func(path string) (ID3Tag, Metadata, error)) ([]Result, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]Result, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range indexes {
+				results[j].Path = paths[j]
+				results[j].Tag, results[j].Metadata, results[j].Err = read(paths[j])
+			}
+		}()
+	}
+	for j := range paths {
+		indexes <- j
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, &BatchError{failed, len(paths)}
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractBatch(t *testing.T) {
+	paths := []string{"testdata/test-v23.mp3", "testdata/missing.mp3", "testdata/test-v24.mp3", "README.md"}
+	results, err := ExtractBatch(paths, 3)
+	batcherr, ok := err.(*BatchError)
+	if !ok || batcherr.Failed != 2 || batcherr.Total != 4 {
+		t.Fatalf("Expected 2 of 4 files to fail, got %v", err)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("Expected %d results, got %d", len(paths), len(results))
+	}
+	for j, result := range results {
+		if result.Path != paths[j] {
+			t.Errorf("Result %d is for %v, expected %v", j, result.Path, paths[j])
+		}
+		failed := j%2 == 1
+		if (result.Err != nil) != failed {
+			t.Errorf("%v: unexpected error %v", result.Path, result.Err)
+		}
+		if !failed && (result.Metadata.Album != "ID3 Tag Test" || result.Metadata.RecordingDate.Year() != 2013 || len(result.Metadata.Genres) != 1) {
+			t.Errorf("%v: unexpected metadata %+v", result.Path, result.Metadata)
+		}
+	}
+
+	if _, err := ExtractBatch(paths[0:1], 0); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	v23, err := os.ReadFile("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"music/b/song.MP3":   {Data: v23},
+		"music/a/song.mp3":   {Data: v23},
+		"music/a/broken.mp3": {Data: []byte("not a tag")},
+		"music/a/notes.txt":  {Data: v23},
+		"other/song.mp3":     {Data: v23},
+	}
+	results, err := Scan(fsys, "music", 2)
+	if batcherr, ok := err.(*BatchError); !ok || batcherr.Failed != 1 {
+		t.Errorf("Expected one failed file, got %v", err)
+	}
+	paths := make([]string, len(results))
+	for j, result := range results {
+		paths[j] = result.Path
+	}
+	if want := []string{"music/a/broken.mp3", "music/a/song.mp3", "music/b/song.MP3"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected %v, got %v", want, paths)
+	}
+	if results[2].Metadata.Title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" {
+		t.Errorf("Unexpected title %q", results[2].Metadata.Title)
+	}
+
+	if _, err := Scan(fsys, "missing", 1); err == nil {
+		t.Errorf("Expected an error for a missing root")
+	}
+
+	cache := NewCache(nil)
+	if id3tag, err := cache.ReadFileFS(fsys, "other/song.mp3"); err != nil || len(id3tag.Frames) != 11 {
+		t.Errorf("Unexpected cached read: %v", err)
+	}
+}
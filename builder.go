@@ -0,0 +1,167 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A TagBuilder assembles a new tag for code generating tags from a database or similar source. Its methods
+// record a field and return the builder so that calls chain, and Build encodes the fields for the chosen
+// version, as in
+//
+//	id3tag, err := NewTagBuilder().Title("Title").Artist("Artist").Picture(cover).Build(Version24)
+//
+// Setting a text field again replaces its value.
+type TagBuilder struct {
+	steps []func(ID3Tag) ID3Tag
+}
+
+// NewTagBuilder returns a builder for an empty tag
+func NewTagBuilder() *TagBuilder {
+	return &TagBuilder{}
+}
+
+func (b *TagBuilder) add(step func(ID3Tag) ID3Tag) *TagBuilder {
+	b.steps = append(b.steps, step)
+	return b
+}
+
+// Text sets the text frame frameid to values as ID3Tag.SetText does
+func (b *TagBuilder) Text(frameid string, values ...string) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		return id3tag.SetText(frameid, values...)
+	})
+}
+
+func (b *TagBuilder) Title(title string) *TagBuilder {
+	return b.Text("TIT2", title)
+}
+
+func (b *TagBuilder) Artist(artists ...string) *TagBuilder {
+	return b.Text("TPE1", artists...)
+}
+
+func (b *TagBuilder) Album(album string) *TagBuilder {
+	return b.Text("TALB", album)
+}
+
+func (b *TagBuilder) AlbumArtist(artist string) *TagBuilder {
+	return b.Text("TPE2", artist)
+}
+
+func (b *TagBuilder) Composer(composers ...string) *TagBuilder {
+	return b.Text("TCOM", composers...)
+}
+
+// Year sets the recording year, in TDRC for v2.4 tags and TYER for v2.3 tags
+func (b *TagBuilder) Year(year int) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		if id3tag.Version == Version23 {
+			return id3tag.SetText("TYER", strconv.Itoa(year))
+		}
+		return id3tag.SetText("TDRC", strconv.Itoa(year))
+	})
+}
+
+// Track sets the track number and, unless total is zero, the number of tracks
+func (b *TagBuilder) Track(track, total int) *TagBuilder {
+	value := strconv.Itoa(track)
+	if total > 0 {
+		value += "/" + strconv.Itoa(total)
+	}
+	return b.Text("TRCK", value)
+}
+
+// Genres sets the genres as ID3Tag.SetGenres does
+func (b *TagBuilder) Genres(genres ...string) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		return id3tag.SetGenres(genres)
+	})
+}
+
+// UserText sets a TXXX frame as ID3Tag.SetUserText does
+func (b *TagBuilder) UserText(description string, values ...string) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		return id3tag.SetUserText(description, values...)
+	})
+}
+
+// Comment adds a COMM frame
+func (b *TagBuilder) Comment(comment LocalizedText) *TagBuilder {
+	return b.localized_text("COMM", comment)
+}
+
+// Lyrics adds a USLT frame
+func (b *TagBuilder) Lyrics(lyrics LocalizedText) *TagBuilder {
+	return b.localized_text("USLT", lyrics)
+}
+
+func (b *TagBuilder) localized_text(frameid string, text LocalizedText) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		encoding := text_encoding(id3tag.Version, text.Description, text.Text)
+		lang := (text.Language + "XXX")[0:3]
+		data := append([]byte{encoding}, lang...)
+		data = append(append(data, encode_string(encoding, text.Description)...), string_terminator(encoding)...)
+		data = append(data, encode_string(encoding, text.Text)...)
+		return id3tag.WithFrame(ID3Frame{FrameID: frameid, Length: uint32(len(data)), Data: data})
+	})
+}
+
+// Picture adds an APIC frame
+func (b *TagBuilder) Picture(pic Picture) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		return id3tag.WithFrame(encode_picture(id3tag.Version, pic))
+	})
+}
+
+// encode_picture returns an APIC frame holding pic for a tag of version ver
+func encode_picture(ver Version, pic Picture) ID3Frame {
+	encoding := text_encoding(ver, pic.Description)
+	data := append([]byte{encoding}, pic.MIMEType...)
+	data = append(data, 0, byte(pic.Type))
+	data = append(append(data, encode_string(encoding, pic.Description)...), string_terminator(encoding)...)
+	data = append(data, pic.Data...)
+	return ID3Frame{FrameID: "APIC", Length: uint32(len(data)), Data: data}
+}
+
+// Frame adds frame as it is, for frames the builder has no method for
+func (b *TagBuilder) Frame(frame ID3Frame) *TagBuilder {
+	return b.add(func(id3tag ID3Tag) ID3Tag {
+		return id3tag.WithFrame(frame)
+	})
+}
+
+// Build returns the tag as a tag of version ver, which must be Version23 or Version24. It fails if the
+// tag holds a frame ID that is malformed or not defined for ver, other than the experimental IDs starting
+// with X, Y or Z, or holds more than one of a frame that may appear only once.
+func (b *TagBuilder) Build(ver Version) (ID3Tag, error) {
+	if ver != Version23 && ver != Version24 {
+		return ID3Tag{}, errors.New(fmt.Sprintf("Cannot build ID3v%v tags", ver))
+	}
+	id3tag := ID3Tag{Version: ver}
+	for _, step := range b.steps {
+		id3tag = step(id3tag)
+	}
+
+	seen := make(map[string]bool)
+	for _, frame := range id3tag.Frames {
+		if !valid_frameid(frame.FrameID) {
+			return ID3Tag{}, errors.New(fmt.Sprintf("Invalid frame ID %q", frame.FrameID))
+		}
+		info, known := LookupFrame(frame.FrameID)
+		if !known && frame.FrameID[0] != 'X' && frame.FrameID[0] != 'Y' && frame.FrameID[0] != 'Z' {
+			return ID3Tag{}, errors.New(fmt.Sprintf("Frame %v is not defined", frame.FrameID))
+		}
+		if known && !info.AllowedIn(ver) {
+			return ID3Tag{}, errors.New(fmt.Sprintf("Frame %v is not defined in ID3v%v", frame.FrameID, ver))
+		}
+		if known && !info.Repeatable && seen[frame.FrameID] {
+			return ID3Tag{}, errors.New(fmt.Sprintf("Frame %v may appear only once", frame.FrameID))
+		}
+		seen[frame.FrameID] = true
+	}
+	return id3tag, nil
+}
@@ -0,0 +1,63 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTagBuilder(t *testing.T) {
+	builder := NewTagBuilder().
+		Title("Title").
+		Artist("One", "Two").
+		Album("Album").
+		Year(2001).
+		Track(3, 12).
+		Genres("Rock").
+		Comment(LocalizedText{"eng", "", "Comment"}).
+		Picture(Picture{"image/png", FrontCover, "Cover", []byte("\x89PNG")})
+
+	for _, ver := range []Version{Version23, Version24} {
+		built, err := builder.Build(ver)
+		if err != nil {
+			t.Fatalf("v%v: error in building tag: %v", ver, err)
+		}
+		var buf bytes.Buffer
+		if err := WriteID3(&buf, built); err != nil {
+			t.Fatalf("v%v: error in writing tag: %v", ver, err)
+		}
+		id3tag, err := ReadID3Bytes(buf.Bytes())
+		if err != nil {
+			t.Fatalf("v%v: error in reading tag: %v", ver, err)
+		}
+		md := id3tag.GetMetadata()
+		if md.Title != "Title" || md.Album != "Album" || md.RecordingDate.Year() != 2001 || !reflect.DeepEqual(md.Genres, []string{"Rock"}) {
+			t.Errorf("v%v: unexpected metadata %+v", ver, md)
+		}
+		if artists := id3tag.GetArtists("/"); !reflect.DeepEqual(artists, []string{"One", "Two"}) {
+			t.Errorf("v%v: unexpected artists %q", ver, artists)
+		}
+		if comment, _ := id3tag.GetCommentByLanguage("eng"); comment != "Comment" {
+			t.Errorf("v%v: unexpected comment %q", ver, comment)
+		}
+		if pics, _ := id3tag.GetPictures(); len(pics) != 1 || pics[0].Description != "Cover" || string(pics[0].Data) != "\x89PNG" {
+			t.Errorf("v%v: unexpected pictures %+v", ver, pics)
+		}
+	}
+
+	if _, err := NewTagBuilder().Text("TDRC", "2001").Build(Version23); err == nil {
+		t.Errorf("Expected TDRC to be refused in a v2.3 tag")
+	}
+	if _, err := NewTagBuilder().Frame(ID3Frame{FrameID: "ABCD"}).Build(Version24); err == nil {
+		t.Errorf("Expected an undefined frame to be refused")
+	}
+	if _, err := NewTagBuilder().Frame(ID3Frame{FrameID: "XABC"}).Build(Version24); err != nil {
+		t.Errorf("Expected an experimental frame to be accepted: %v", err)
+	}
+	pcnt := ID3Frame{FrameID: "PCNT", Data: []byte{0, 0, 0, 1}}
+	if _, err := NewTagBuilder().Frame(pcnt).Frame(pcnt).Build(Version24); err == nil {
+		t.Errorf("Expected a repeated PCNT frame to be refused")
+	}
+}
@@ -0,0 +1,108 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// A CacheKey identifies one version of a file on disk. A file whose modification time or size has
+// changed since it was cached gets a different key and so is parsed again.
+type CacheKey struct {
+	Path    string
+	ModTime int64 //modification time in nanoseconds since the Unix epoch
+	Size    int64
+}
+
+// A CacheStore holds parsed tags for a Cache. Implementations must be safe for concurrent use since
+// a single Cache is typically shared by all the goroutines of a library scan.
+type CacheStore interface {
+	Get(key CacheKey) (ID3Tag, bool)
+	Put(key CacheKey, id3tag ID3Tag)
+}
+
+// MemoryCacheStore is a CacheStore keeping tags in memory. Only the latest version of each path is kept.
+type MemoryCacheStore struct {
+	mu      sync.RWMutex
+	entries map[string]memory_cache_entry
+}
+
+type memory_cache_entry struct {
+	key    CacheKey
+	id3tag ID3Tag
+}
+
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{entries: make(map[string]memory_cache_entry)}
+}
+
+func (store *MemoryCacheStore) Get(key CacheKey) (ID3Tag, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if entry, ok := store.entries[key.Path]; ok && entry.key == key {
+		return entry.id3tag, true
+	}
+	return ID3Tag{}, false
+}
+
+func (store *MemoryCacheStore) Put(key CacheKey, id3tag ID3Tag) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.entries[key.Path] = memory_cache_entry{key, id3tag}
+}
+
+// A Cache avoids re-parsing files that have not changed since they were last read. It is safe for
+// concurrent use as long as its Store is.
+type Cache struct {
+	Store CacheStore
+}
+
+// NewCache returns a Cache backed by store, or by a new MemoryCacheStore if store is nil.
+func NewCache(store CacheStore) *Cache {
+	if store == nil {
+		store = NewMemoryCacheStore()
+	}
+	return &Cache{store}
+}
+
+// ReadFile returns the tag of the file at path, parsing the file only if the cache holds no tag for
+// its current modification time and size. Files that fail to parse are not cached.
+func (cache *Cache) ReadFile(path string) (ID3Tag, error) {
+	fil, err := os.Open(path)
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	defer fil.Close()
+	return cache.read(fil, path)
+}
+
+// ReadFileFS is like ReadFile but opens name in fsys. Tags are cached by name, so a Cache shared between
+// several file systems should only be used with names that are unique across them.
+func (cache *Cache) ReadFileFS(fsys fs.FS, name string) (ID3Tag, error) {
+	fil, err := fsys.Open(name)
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	defer fil.Close()
+	return cache.read(fil, name)
+}
+
+func (cache *Cache) read(fil fs.File, path string) (ID3Tag, error) {
+	info, err := fil.Stat()
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	key := CacheKey{path, info.ModTime().UnixNano(), info.Size()}
+	if id3tag, ok := cache.Store.Get(key); ok {
+		return id3tag, nil
+	}
+
+	id3tag, err := ReadID3(fil)
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	cache.Store.Put(key, id3tag)
+	return id3tag, nil
+}
@@ -0,0 +1,73 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type counting_store struct {
+	CacheStore
+	mu   sync.Mutex
+	hits int
+}
+
+func (store *counting_store) Get(key CacheKey) (ID3Tag, bool) {
+	id3tag, ok := store.CacheStore.Get(key)
+	if ok {
+		store.mu.Lock()
+		store.hits++
+		store.mu.Unlock()
+	}
+	return id3tag, ok
+}
+
+func TestCache(t *testing.T) {
+	data, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filname := filepath.Join(t.TempDir(), "test.mp3")
+	if err := os.WriteFile(filname, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &counting_store{CacheStore: NewMemoryCacheStore()}
+	cache := NewCache(store)
+
+	var wg sync.WaitGroup
+	for j := 0; j < 4; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.ReadFile(filname); err != nil {
+				t.Errorf("Error in reading tag: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := cache.ReadFile(filname); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if store.hits == 0 {
+		t.Errorf("Expected repeated reads to hit the cache")
+	}
+
+	hits := store.hits
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filname, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if id3tag, err := cache.ReadFile(filname); err != nil {
+		t.Errorf("Error in reading tag: %v", err)
+	} else if title, _ := id3tag.GetTitle(); title != "Sine Wave at 440 Hz ßÄÜ" {
+		t.Errorf("Unexpected title %q", title)
+	}
+	if store.hits != hits {
+		t.Errorf("Expected a modified file to miss the cache")
+	}
+}
@@ -0,0 +1,103 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"crypto/sha256"
+)
+
+// A ChangeKind tells what a Change did to a tag.
+type ChangeKind int
+
+const (
+	FrameAdded ChangeKind = iota
+	FrameRemoved
+	FrameModified
+	VersionChanged
+)
+
+var change_kind_names = map[ChangeKind]string{
+	FrameAdded:     "added",
+	FrameRemoved:   "removed",
+	FrameModified:  "modified",
+	VersionChanged: "version changed",
+}
+
+func (kind ChangeKind) String() string {
+	if name, ok := change_kind_names[kind]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// A Change is a difference between a tag and the tag it was read as. Old is the frame as read and New the
+// frame now in the tag, either being the zero ID3Frame where there is none. FrameID is that of New, or of
+// Old for removed frames, and "" for version changes.
+type Change struct {
+	Kind    ChangeKind
+	FrameID string
+	Old     ID3Frame
+	New     ID3Frame
+}
+
+// Changed reports whether the tag differs from the tag it was read as, so batch editors can skip
+// writing files whose tags were edited back to what they were. See Changes.
+func (id3tag ID3Tag) Changed() bool {
+	return len(id3tag.Changes()) > 0
+}
+
+// Changes returns the differences between the tag and the tag it was read as: frames removed or modified,
+// in the order they were read, frames added, in tag order, and a change of version. Frames are compared
+// by Hash, so re-encoding their text or reordering them is no change, and a frame replaced by one that
+// describes the same thing, such as the TXXX frame with the same description, is modified rather than
+// removed and added. Every frame of a tag that was not read, such as one made with NewTagBuilder, is added.
+func (id3tag ID3Tag) Changes() []Change {
+	ret := make([]Change, 0)
+	current := make(map[[sha256.Size]byte]int)
+	for _, frame := range id3tag.Frames {
+		current[frame.Hash()]++
+	}
+	removed := make([]ID3Frame, 0)
+	for _, frame := range id3tag.original {
+		if sum := frame.Hash(); current[sum] > 0 {
+			current[sum]--
+		} else {
+			removed = append(removed, frame)
+		}
+	}
+	original := make(map[[sha256.Size]byte]int)
+	for _, frame := range id3tag.original {
+		original[frame.Hash()]++
+	}
+	added := make([]ID3Frame, 0)
+	for _, frame := range id3tag.Frames {
+		if sum := frame.Hash(); original[sum] > 0 {
+			original[sum]--
+		} else {
+			added = append(added, frame)
+		}
+	}
+
+	paired := make([]bool, len(added))
+	for _, old := range removed {
+		change := Change{Kind: FrameRemoved, FrameID: old.FrameID, Old: old}
+		key := frame_key(old)
+		for j, frame := range added {
+			if !paired[j] && frame_key(frame) == key {
+				paired[j] = true
+				change = Change{Kind: FrameModified, FrameID: frame.FrameID, Old: old, New: frame}
+				break
+			}
+		}
+		ret = append(ret, change)
+	}
+	for j, frame := range added {
+		if !paired[j] {
+			ret = append(ret, Change{Kind: FrameAdded, FrameID: frame.FrameID, New: frame})
+		}
+	}
+	if id3tag.original != nil && id3tag.Version != id3tag.original_version {
+		ret = append(ret, Change{Kind: VersionChanged})
+	}
+	return ret
+}
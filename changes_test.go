@@ -0,0 +1,84 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	buf := make_tag(3,
+		make_frame(3, "TIT2", 0, []byte("\x00Title")),
+		make_frame(3, "TPE1", 0, []byte("\x00Artist")),
+		make_frame(3, "TXXX", 0, []byte("\x00mood\x00calm")),
+		make_frame(3, "PRIV", 0, []byte("owner\x00data")),
+	)
+	id3tag, err := ReadID3Bytes(buf)
+	if err != nil {
+		t.Fatalf("Error in reading the tag: %v", err)
+	}
+	if id3tag.Changed() {
+		t.Errorf("Tag as read has changes %v", id3tag.Changes())
+	}
+
+	//setting values back to what they were is no change, even when re-encoded or moved
+	same := id3tag.SetText("TIT2", "Other").SetText("TIT2", "Title").Reorder([]string{"PRIV"})
+	same = same.SetUserText("mood", "calm")
+	if same.Changed() {
+		t.Errorf("Tag with the same values has changes %v", same.Changes())
+	}
+
+	edited := id3tag.SetText("TPE1", "Someone").WithoutFrames("PRIV").SetText("TALB", "Album").SetUserText("mood", "upbeat")
+	changes := edited.Changes()
+	want := []struct {
+		kind    ChangeKind
+		frameid string
+	}{
+		{FrameModified, "TPE1"},
+		{FrameModified, "TXXX"},
+		{FrameRemoved, "PRIV"},
+		{FrameAdded, "TALB"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Got %v changes, expected %v: %v", len(changes), len(want), changes)
+	}
+	for j, change := range changes {
+		if change.Kind != want[j].kind || change.FrameID != want[j].frameid {
+			t.Errorf("Change %v is %v %v, expected %v %v", j, change.Kind, change.FrameID, want[j].kind, want[j].frameid)
+		}
+	}
+	if txt, _ := id3tag.decodetext(0, changes[0].Old.Data[1:len(changes[0].Old.Data)]); txt != "Artist" {
+		t.Errorf("Old TPE1 is %q", txt)
+	}
+	if changes[2].New.FrameID != "" {
+		t.Errorf("Removed frame has a new frame %v", changes[2].New)
+	}
+
+	converted, _, err := id3tag.Convert(Version24)
+	if err != nil {
+		t.Fatalf("Error in converting: %v", err)
+	}
+	if changes := converted.Changes(); len(changes) != 1 || changes[0].Kind != VersionChanged {
+		t.Errorf("Converted tag has changes %v", changes)
+	}
+
+	built, err := NewTagBuilder().Title("Title").Build(Version24)
+	if err != nil {
+		t.Fatalf("Error in building: %v", err)
+	}
+	if changes := built.Changes(); len(changes) != 1 || changes[0].Kind != FrameAdded {
+		t.Errorf("Built tag has changes %v", changes)
+	}
+
+	data, err := edited.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ID3Tag
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Changes()) != len(changes) {
+		t.Errorf("Decoded tag has changes %v, expected %v", decoded.Changes(), changes)
+	}
+}
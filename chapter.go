@@ -0,0 +1,328 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Chapter is a section of the audio as described by a CHAP frame. Start and End are offsets from the
+// start of the audio, kept to the millisecond precision the frame stores.
+type Chapter struct {
+	ElementID string
+	Start     time.Duration
+	End       time.Duration
+	Title     string
+	URL       string
+}
+
+// ChapterOption sets optional fields of a chapter added with AddChapter
+type ChapterOption func(*Chapter)
+
+// ChapterID gives the chapter the element ID id instead of the next free one of "chp0", "chp1" and so on
+func ChapterID(id string) ChapterOption {
+	return func(chapter *Chapter) {
+		chapter.ElementID = id
+	}
+}
+
+// ChapterURL links the chapter to url with a WXXX sub-frame
+func ChapterURL(url string) ChapterOption {
+	return func(chapter *Chapter) {
+		chapter.URL = url
+	}
+}
+
+// AddChapter returns a copy of the tag with a CHAP frame for the chapter from start to end titled title.
+// A chapter with the same element ID is replaced. The top level CTOC frame, with element ID "toc" if the
+// tag had none, is rebuilt to list every chapter in the tag ordered by start time, so players show the
+// chapters in playing order, except chapters that nested CTOC frames list. Its entries naming nested
+// CTOC frames follow the chapters, and its sub-frames, such as a TIT2 title, are kept. Other CTOC frames
+// are kept as they are. The entry count of a CTOC frame is a single byte, so an error is returned if the
+// rebuilt frame would list more than 255 entries.
+func (id3tag ID3Tag) AddChapter(start, end time.Duration, title string, opts ...ChapterOption) (ID3Tag, error) {
+	chapter := Chapter{Start: start, End: end, Title: title}
+	for _, opt := range opts {
+		opt(&chapter)
+	}
+
+	ids := make(map[string]bool)
+	nested := make(map[string]bool) //entries of the CTOC frames other than the top level one
+	toc := table_of_contents{id: "toc", flags: 0x03}
+	for _, frame := range id3tag.Frames {
+		switch {
+		case frame.FrameID == "CHAP":
+			ids[chap_element_id(frame.Data)] = true
+		case is_top_level_toc(frame):
+			toc = parse_toc(frame.Data)
+		case frame.FrameID == "CTOC":
+			for _, entry := range parse_toc(frame.Data).entries {
+				nested[entry] = true
+			}
+		}
+	}
+	if chapter.ElementID == "" {
+		for j := 0; ; j++ {
+			if id := "chp" + strconv.Itoa(j); !ids[id] {
+				chapter.ElementID = id
+				break
+			}
+		}
+	}
+
+	frames := make([]ID3Frame, 0, len(id3tag.Frames)+1)
+	for _, frame := range id3tag.Frames {
+		if is_top_level_toc(frame) || frame.FrameID == "CHAP" && chap_element_id(frame.Data) == chapter.ElementID {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	data := id3tag.encode_chapter(chapter)
+	frames = append(frames, ID3Frame{FrameID: "CHAP", Length: uint32(len(data)), Data: data})
+	ids[chapter.ElementID] = true
+
+	entries := chapters_by_start(frames, nested)
+	for _, entry := range toc.entries {
+		if !ids[entry] {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > 255 {
+		return id3tag, errors.New(fmt.Sprintf("CTOC frame %v cannot list %v entries, only 255", toc.id, len(entries)))
+	}
+	toc.flags |= 0x03
+	toc.entries = entries
+	data = toc.encode()
+	id3tag.Frames = append(frames, ID3Frame{FrameID: "CTOC", Length: uint32(len(data)), Data: data})
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag, nil
+}
+
+// chap_element_id returns the element ID at the start of a CHAP or CTOC frame body
+func chap_element_id(data []byte) string {
+	first, _ := split_text(0, data)
+	return decodeISO88591(first)
+}
+
+func is_top_level_toc(frame ID3Frame) bool {
+	if frame.FrameID != "CTOC" {
+		return false
+	}
+	id := chap_element_id(frame.Data)
+	return len(frame.Data) > len(id)+1 && frame.Data[len(id)+1]&0x02 != 0
+}
+
+// encode_chapter returns the CHAP frame body for chapter. Byte offsets are marked unused as the tag cannot
+// know where the audio frames lie.
+func (id3tag ID3Tag) encode_chapter(chapter Chapter) []byte {
+	ver := id3tag.Version
+	if ver == 0 {
+		ver = Version24
+	}
+	buf := append([]byte(chapter.ElementID), 0)
+	buf = append(buf, encode_regular_int(uint32(chapter.Start/time.Millisecond))...)
+	buf = append(buf, encode_regular_int(uint32(chapter.End/time.Millisecond))...)
+	buf = append(buf, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+	if chapter.Title != "" {
+		subframe, _ := encode_frame(ver, ID3Frame{FrameID: "TIT2", Data: encodetext(ver, chapter.Title)})
+		buf = append(buf, subframe...)
+	}
+	if chapter.URL != "" {
+		subframe, _ := encode_frame(ver, ID3Frame{FrameID: "WXXX", Data: append([]byte{0, 0}, chapter.URL...)})
+		buf = append(buf, subframe...)
+	}
+	return buf
+}
+
+// chapters_by_start returns the element IDs of the CHAP frames among frames ordered by start time, less
+// those in skip
+func chapters_by_start(frames []ID3Frame, skip map[string]bool) []string {
+	type entry struct {
+		id    string
+		start uint32
+	}
+	entries := make([]entry, 0)
+	for _, frame := range frames {
+		if frame.FrameID != "CHAP" {
+			continue
+		}
+		id := chap_element_id(frame.Data)
+		if skip[id] {
+			continue
+		}
+		var start uint32
+		if len(frame.Data) >= len(id)+5 {
+			start, _ = convert_regular_int(frame.Data[len(id)+1 : len(id)+5])
+		}
+		entries = append(entries, entry{id, start})
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].start < entries[b].start
+	})
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.id)
+	}
+	return ids
+}
+
+// A table_of_contents is the body of a CTOC frame: its element ID, flags, the element IDs of its entries
+// and the encoded sub-frames following them
+type table_of_contents struct {
+	id        string
+	flags     byte
+	entries   []string
+	subframes []byte
+}
+
+// parse_toc splits a CTOC frame body, taking as many entries as the body holds of those it declares
+func parse_toc(data []byte) table_of_contents {
+	toc := table_of_contents{id: chap_element_id(data)}
+	pos := len(toc.id) + 1
+	if len(data) < pos+2 {
+		return toc
+	}
+	toc.flags = data[pos]
+	count := int(data[pos+1])
+	rest := data[pos+2 : len(data)]
+	for j := 0; j < count && len(rest) > 0; j++ {
+		entry, remainder := split_text(0, rest)
+		toc.entries = append(toc.entries, decodeISO88591(entry))
+		rest = remainder
+	}
+	toc.subframes = rest
+	return toc
+}
+
+// encode returns the CTOC frame body of toc, which must have at most 255 entries
+func (toc table_of_contents) encode() []byte {
+	buf := append([]byte(toc.id), 0, toc.flags, byte(len(toc.entries)))
+	for _, entry := range toc.entries {
+		buf = append(append(buf, entry...), 0)
+	}
+	return append(buf, toc.subframes...)
+}
+
+// GetChapters returns the chapters of the CHAP frames in the tag ordered by start time. Chapter titles
+// come from TIT2 sub-frames and URLs from WXXX sub-frames. Malformed CHAP frames are left out.
+func (id3tag ID3Tag) GetChapters() ([]Chapter, error) {
+	ret := make([]Chapter, 0)
+	for _, framedata := range id3tag.tag_data("CHAP") {
+		id := chap_element_id(framedata)
+		pos := len(id) + 1
+		if len(framedata) < pos+16 {
+			continue
+		}
+		start, _ := convert_regular_int(framedata[pos : pos+4])
+		end, _ := convert_regular_int(framedata[pos+4 : pos+8])
+		chapter := Chapter{ElementID: id, Start: time.Duration(start) * time.Millisecond, End: time.Duration(end) * time.Millisecond}
+		for _, subframe := range parse_subframes(id3tag.Version, framedata[pos+16:len(framedata)]) {
+			switch {
+			case subframe.FrameID == "TIT2" && len(subframe.Data) > 0 && chapter.Title == "":
+				chapter.Title, _ = id3tag.decodetext(subframe.Data[0], subframe.Data[1:len(subframe.Data)])
+			case subframe.FrameID == "WXXX" && len(subframe.Data) > 0 && chapter.URL == "":
+				_, url := split_text(subframe.Data[0], subframe.Data[1:len(subframe.Data)])
+				chapter.URL = decodeISO88591(url)
+			}
+		}
+		ret = append(ret, chapter)
+	}
+	if len(ret) == 0 {
+		return ret, errors.New("No such frame CHAP found in the taglist")
+	}
+	sort.SliceStable(ret, func(a, b int) bool {
+		return ret[a].Start < ret[b].Start
+	})
+	return ret, nil
+}
+
+// parse_subframes returns the frames embedded in a CHAP or CTOC frame. Sub-frames with format flags that
+// call for prefix fields, compression or encryption are left out.
+func parse_subframes(ver Version, data []byte) []ID3Frame {
+	ret := make([]ID3Frame, 0)
+	for len(data) >= 10 && data[0] != 0 {
+		var length uint32
+		if ver == Version23 {
+			length, _ = convert_regular_int(data[4:8])
+		} else {
+			length, _ = convert_synchsafe_int(data[4:8])
+		}
+		if uint64(len(data)-10) < uint64(length) {
+			break
+		}
+		if data[9] == 0 {
+			ret = append(ret, ID3Frame{FrameID: string(data[0:4]), Length: length, StatusFlags: data[8], Data: data[10 : 10+length]})
+		}
+		data = data[10+length : len(data)]
+	}
+	return ret
+}
+
+type podlove_chapter struct {
+	Start string `json:"start"`
+	Title string `json:"title"`
+	Href  string `json:"href,omitempty"`
+}
+
+// PodloveJSON returns chapters in the JSON form of the Podlove Simple Chapters format, in which start
+// times are written as HH:MM:SS.mmm
+func PodloveJSON(chapters []Chapter) ([]byte, error) {
+	ret := make([]podlove_chapter, len(chapters))
+	for j, chapter := range chapters {
+		ms := chapter.Start / time.Millisecond
+		ret[j] = podlove_chapter{
+			Start: fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000),
+			Title: chapter.Title,
+			Href:  chapter.URL,
+		}
+	}
+	return json.Marshal(ret)
+}
+
+type podcast_chapter struct {
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime,omitempty"`
+	Title     string  `json:"title,omitempty"`
+	URL       string  `json:"url,omitempty"`
+}
+
+// PodcastJSON returns chapters in the JSON chapters format of the podcast namespace, version 1.2.0, in
+// which times are written in seconds
+func PodcastJSON(chapters []Chapter) ([]byte, error) {
+	ret := struct {
+		Version  string            `json:"version"`
+		Chapters []podcast_chapter `json:"chapters"`
+	}{"1.2.0", make([]podcast_chapter, len(chapters))}
+	for j, chapter := range chapters {
+		ret.Chapters[j] = podcast_chapter{chapter.Start.Seconds(), chapter.End.Seconds(), chapter.Title, chapter.URL}
+	}
+	return json.Marshal(ret)
+}
+
+// CueSheet returns chapters as a cue sheet with one track per chapter for the MP3 file named file. Cue
+// sheet times are in CD frames of 1/75 second, and double quotes, which cue sheets cannot escape, are
+// replaced with single quotes.
+func CueSheet(chapters []Chapter, file string) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "FILE %s MP3\n", quote(file))
+	for j, chapter := range chapters {
+		cdframes := chapter.Start * 75 / time.Second
+		fmt.Fprintf(&buf, "  TRACK %02d AUDIO\n", j+1)
+		if chapter.Title != "" {
+			fmt.Fprintf(&buf, "    TITLE %s\n", quote(chapter.Title))
+		}
+		fmt.Fprintf(&buf, "    INDEX 01 %02d:%02d:%02d\n", cdframes/75/60, cdframes/75%60, cdframes%75)
+	}
+	return buf.String()
+}
@@ -0,0 +1,134 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddChapter(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Episode"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(90*time.Second, 150*time.Second, "Second", ChapterURL("http://example.com")); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(0, 90*time.Second, "First"); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(90*time.Second, 160*time.Second, "Second again", ChapterID("chp0")); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+
+	if got := id3tag.Order(); !reflect.DeepEqual(got, []string{"TIT2", "CHAP", "CHAP", "CTOC"}) {
+		t.Fatalf("Unexpected frames %v", got)
+	}
+	chaps := id3tag.GetTagData("CHAP")
+	first := []byte("chp1\x00\x00\x00\x00\x00\x00\x01\x5f\x90\xff\xff\xff\xff\xff\xff\xff\xffTIT2\x00\x00\x00\x06\x00\x00\x03First")
+	if !bytes.Equal(chaps[0], first) {
+		t.Errorf("Unexpected CHAP frame %q", chaps[0])
+	}
+	if !bytes.HasPrefix(chaps[1], []byte("chp0\x00\x00\x01\x5f\x90\x00\x02\x71\x00")) || bytes.Contains(chaps[1], []byte("WXXX")) {
+		t.Errorf("Replaced CHAP frame not rewritten: %q", chaps[1])
+	}
+	if toc := id3tag.GetTagData("CTOC")[0]; !bytes.Equal(toc, []byte("toc\x00\x03\x02chp1\x00chp0\x00")) {
+		t.Errorf("Unexpected CTOC frame %q", toc)
+	}
+
+	//the rebuilt table of contents keeps an existing element ID and nested tables are left alone
+	id3tag, err = ReadID3Bytes(make_tag(4,
+		make_frame(4, "CTOC", 0, []byte("root\x00\x03\x00")),
+		make_frame(4, "CTOC", 0, []byte("part\x00\x01\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(0, time.Second, "", ChapterURL("http://example.com")); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+	tocs := id3tag.GetTagData("CTOC")
+	if len(tocs) != 2 || !bytes.HasPrefix(tocs[0], []byte("part\x00")) || !bytes.Equal(tocs[1], []byte("root\x00\x03\x01chp0\x00")) {
+		t.Errorf("Unexpected CTOC frames %q", tocs)
+	}
+	if chap := id3tag.GetTagData("CHAP")[0]; !bytes.HasSuffix(chap, []byte("WXXX\x00\x00\x00\x14\x00\x00\x00\x00http://example.com")) {
+		t.Errorf("Unexpected CHAP frame %q", chap)
+	}
+
+	//entries naming nested tables of contents and sub-frames are kept, and the chapters nested tables list
+	//are not repeated at the top level
+	id3tag, err = ReadID3Bytes(make_tag(4,
+		make_frame(4, "CTOC", 0, []byte("root\x00\x03\x02chp0\x00part\x00TIT2\x00\x00\x00\x05\x00\x00\x03Book")),
+		make_frame(4, "CTOC", 0, []byte("part\x00\x01\x01chp1\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	for _, start := range []time.Duration{time.Minute, 0, 2 * time.Minute} {
+		if id3tag, err = id3tag.AddChapter(start, start+time.Minute, ""); err != nil {
+			t.Fatalf("Error in adding chapter: %v", err)
+		}
+	}
+	tocs = id3tag.GetTagData("CTOC")
+	if want := []byte("root\x00\x03\x03chp0\x00chp2\x00part\x00TIT2\x00\x00\x00\x05\x00\x00\x03Book"); len(tocs) != 2 || !bytes.Equal(tocs[1], want) {
+		t.Errorf("Unexpected CTOC frames %q", tocs)
+	}
+
+	id3tag, err = ReadID3Bytes(make_tag(4))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	for j := 0; j < 255; j++ {
+		if id3tag, err = id3tag.AddChapter(time.Duration(j)*time.Second, time.Duration(j+1)*time.Second, ""); err != nil {
+			t.Fatalf("Error in adding chapter %v: %v", j, err)
+		}
+	}
+	if _, err := id3tag.AddChapter(time.Hour, time.Hour+time.Second, ""); err == nil {
+		t.Errorf("Expected an error for a 256th chapter")
+	}
+}
+
+func TestChapterExport(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(3))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(61*time.Second+500*time.Millisecond, 3723*time.Second, `The "End"`, ChapterURL("http://example.com/end")); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+	if id3tag, err = id3tag.AddChapter(0, 61*time.Second+500*time.Millisecond, "Intro"); err != nil {
+		t.Fatalf("Error in adding chapter: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, id3tag); err != nil {
+		t.Fatalf("Error in writing tag: %v", err)
+	}
+	if id3tag, err = ReadID3(&buf); err != nil {
+		t.Fatalf("Error in reading written tag: %v", err)
+	}
+	chapters, err := id3tag.GetChapters()
+	want := []Chapter{
+		{"chp1", 0, 61500 * time.Millisecond, "Intro", ""},
+		{"chp0", 61500 * time.Millisecond, 3723 * time.Second, `The "End"`, "http://example.com/end"},
+	}
+	if err != nil || !reflect.DeepEqual(chapters, want) {
+		t.Fatalf("Expected %v, got %v, %v", want, chapters, err)
+	}
+
+	if got, err := PodloveJSON(chapters); err != nil || string(got) != `[{"start":"00:00:00.000","title":"Intro"},{"start":"00:01:01.500","title":"The \"End\"","href":"http://example.com/end"}]` {
+		t.Errorf("Unexpected Podlove JSON %s, %v", got, err)
+	}
+	if got, err := PodcastJSON(chapters); err != nil || string(got) != `{"version":"1.2.0","chapters":[{"startTime":0,"endTime":61.5,"title":"Intro"},{"startTime":61.5,"endTime":3723,"title":"The \"End\"","url":"http://example.com/end"}]}` {
+		t.Errorf("Unexpected podcast JSON %s, %v", got, err)
+	}
+	cue := "FILE \"episode.mp3\" MP3\n" +
+		"  TRACK 01 AUDIO\n    TITLE \"Intro\"\n    INDEX 01 00:00:00\n" +
+		"  TRACK 02 AUDIO\n    TITLE \"The 'End'\"\n    INDEX 01 01:01:37\n"
+	if got := CueSheet(chapters, "episode.mp3"); got != cue {
+		t.Errorf("Unexpected cue sheet\n%s", got)
+	}
+}
@@ -0,0 +1,121 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteWAV copies the RIFF WAVE file r to w with id3tag, encoded as WriteID3 encodes it, in an "id3 "
+// chunk. An existing "id3 " or "ID3 " chunk is replaced where it stands and otherwise the chunk is added
+// after the last one. The RIFF size is fixed up and chunks are padded to an even length as RIFF requires.
+// r is read twice, first to find the chunks and then to copy them, so it must be able to seek.
+func WriteWAV(w io.Writer, r io.ReadSeeker, id3tag ID3Tag, opts ...WriteOption) error {
+	return write_chunked(w, r, id3tag, new_write_config(opts), binary.LittleEndian, "RIFF", "id3 ", "ID3 ")
+}
+
+// WriteAIFF copies the AIFF or AIFF-C file r to w with id3tag in an "ID3 " chunk, as WriteWAV does for
+// WAV files.
+func WriteAIFF(w io.Writer, r io.ReadSeeker, id3tag ID3Tag, opts ...WriteOption) error {
+	return write_chunked(w, r, id3tag, new_write_config(opts), binary.BigEndian, "FORM", "ID3 ", "id3 ")
+}
+
+type chunk struct {
+	id     string
+	offset int64 //of the chunk header
+	size   uint32
+}
+
+// write_chunked writes the container r with its tag chunk, whose ID is the first of ids, replaced by id3tag
+func write_chunked(w io.Writer, r io.ReadSeeker, id3tag ID3Tag, cfg write_config, order binary.ByteOrder, form string, ids ...string) error {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[0:4]) != form {
+		return errors.New(fmt.Sprintf("Not a %v file", form))
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	chunks := make([]chunk, 0)
+	chunk_header := make([]byte, 8)
+	for pos := int64(12); pos+8 <= end; {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, chunk_header); err != nil {
+			return err
+		}
+		c := chunk{string(chunk_header[0:4]), pos, order.Uint32(chunk_header[4:8])}
+		if pos+8+int64(c.size) > end {
+			return errors.New(fmt.Sprintf("Chunk %q runs past the end of the file", c.id))
+		}
+		chunks = append(chunks, c)
+		pos += 8 + int64(c.size) + int64(c.size%2)
+	}
+
+	tag, err := encode_tag(id3tag, cfg)
+	if err != nil {
+		return err
+	}
+	tag_chunk := append([]byte(ids[0]), make([]byte, 4)...)
+	order.PutUint32(tag_chunk[4:8], uint32(len(tag)))
+	tag_chunk = append(tag_chunk, tag...)
+	if len(tag)%2 != 0 {
+		tag_chunk = append(tag_chunk, 0)
+	}
+
+	is_tag := func(c chunk) bool {
+		for _, id := range ids {
+			if c.id == id {
+				return true
+			}
+		}
+		return false
+	}
+	size := int64(4 + len(tag_chunk))
+	for _, c := range chunks {
+		if !is_tag(c) {
+			size += 8 + int64(c.size) + int64(c.size%2)
+		}
+	}
+	if size > 0xFFFFFFFF {
+		return errors.New(fmt.Sprintf("%v size %v exceeds 4GB", form, size))
+	}
+
+	order.PutUint32(header[4:8], uint32(size))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	written := false
+	for _, c := range chunks {
+		if is_tag(c) {
+			if !written {
+				if _, err := w.Write(tag_chunk); err != nil {
+					return err
+				}
+				written = true
+			}
+			continue
+		}
+		if _, err := r.Seek(c.offset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(w, r, 8+int64(c.size)); err != nil {
+			return err
+		}
+		if c.size%2 != 0 {
+			//the pad byte is written even where the original file left it out
+			if _, err := w.Write([]byte{0}); err != nil {
+				return err
+			}
+		}
+	}
+	if !written {
+		_, err = w.Write(tag_chunk)
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteChunked(t *testing.T) {
+	id3tag := ID3Tag{Version: Version24}.SetText("TIT2", "Written")
+	old_tag := make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00Old")))
+
+	cases := []struct {
+		name  string
+		order binary.ByteOrder
+		data  []byte
+		write func(*bytes.Buffer, *bytes.Reader) error
+	}{
+		{"wav", binary.LittleEndian, []byte("RIFF\x00\x00\x00\x00WAVEfmt \x03\x00\x00\x00abc\x00data\x02\x00\x00\x00xy"),
+			func(w *bytes.Buffer, r *bytes.Reader) error { return WriteWAV(w, r, id3tag) }},
+		{"aiff", binary.BigEndian, []byte("FORM\x00\x00\x00\x00AIFFCOMM\x00\x00\x00\x03abc\x00SSND\x00\x00\x00\x02xy"),
+			func(w *bytes.Buffer, r *bytes.Reader) error { return WriteAIFF(w, r, id3tag) }},
+	}
+	for _, c := range cases {
+		with_tag := append([]byte(nil), c.data...)
+		size := make([]byte, 4)
+		c.order.PutUint32(size, uint32(len(old_tag)))
+		with_tag = append(append(append(with_tag, "ID3 "...), size...), old_tag...)
+		if c.name == "wav" {
+			copy(with_tag[len(c.data):], "id3 ")
+		}
+
+		for _, input := range [][]byte{c.data, with_tag} {
+			var buf bytes.Buffer
+			if err := c.write(&buf, bytes.NewReader(input)); err != nil {
+				t.Fatalf("%v: error in writing: %v", c.name, err)
+			}
+			out := buf.Bytes()
+			if size := c.order.Uint32(out[4:8]); int(size) != len(out)-8 {
+				t.Errorf("%v: container size %v, expected %v", c.name, size, len(out)-8)
+			}
+			if !bytes.HasPrefix(out[12:], c.data[12:]) {
+				t.Errorf("%v: audio chunks were not copied unchanged", c.name)
+			}
+			md, format, err := ReadMetadata(bytes.NewReader(out))
+			if err != nil || format != c.name || md.Title != "Written" {
+				t.Errorf("%v: got %v %q, %v", c.name, format, md.Title, err)
+			}
+			if bytes.Count(out, []byte("ID3\x04")) != 1 || bytes.Contains(out, []byte("ID3\x03")) {
+				t.Errorf("%v: expected exactly one tag in the output", c.name)
+			}
+		}
+	}
+
+	if err := WriteWAV(&bytes.Buffer{}, bytes.NewReader([]byte("FORM\x00\x00\x00\x00AIFF")), id3tag); err == nil {
+		t.Errorf("Expected an AIFF file to be refused by WriteWAV")
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Credit names a person and their role, an instrument for musicians, from a TMCL, TIPL or IPLS frame.
+type Credit struct {
+	Role string
+	Name string
+}
+
+// Classical bundles the fields classical releases are catalogued by, which Title and Artist alone describe
+// poorly. Fields missing from the tag are left at their zero values.
+type Classical struct {
+	Work           string   //TIT1 content group
+	Movement       string   //MVNM movement name as iTunes writes it, or else the TIT3 subtitle
+	MovementNumber int      //from MVIN, 0 if missing
+	MovementCount  int      //from MVIN, 0 if missing or not given
+	Composer       string   //TCOM
+	ComposerSort   string   //TSOC
+	Conductor      string   //TPE3
+	Performers     []Credit //TMCL musician credits
+	Involved       []Credit //TIPL involved people, or IPLS in v2.3 tags
+}
+
+// Classical returns the classical music fields of the tag.
+func (id3tag ID3Tag) Classical() Classical {
+	var cl Classical
+	cl.Work, _ = id3tag.GetContentGroup()
+	if cl.Movement, _ = id3tag.GetTextFrameData("MVNM"); cl.Movement == "" {
+		cl.Movement, _ = id3tag.GetSubtitle()
+	}
+	cl.MovementNumber, cl.MovementCount, _ = id3tag.get_position("MVIN")
+	cl.Composer, _ = id3tag.GetComposer()
+	cl.ComposerSort, _ = id3tag.GetComposerSort()
+	cl.Conductor, _ = id3tag.GetConductor()
+	cl.Performers, _ = id3tag.GetMusicianCredits()
+	cl.Involved, _ = id3tag.GetInvolvedPeople()
+	return cl
+}
+
+// GetComposerSort returns the TSOC composer sort order, such as "Beethoven, Ludwig van".
+func (id3tag ID3Tag) GetComposerSort() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TSOC")
+	return txt, err
+}
+
+// GetConductor returns the TPE3 conductor.
+func (id3tag ID3Tag) GetConductor() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TPE3")
+	return txt, err
+}
+
+// GetMusicianCredits returns the musicians and their instruments from the v2.4 TMCL frame.
+func (id3tag ID3Tag) GetMusicianCredits() ([]Credit, error) {
+	return id3tag.get_credits("TMCL")
+}
+
+// GetInvolvedPeople returns the people involved and their roles, such as producer or engineer, from
+// the v2.4 TIPL frame or the v2.3 IPLS frame.
+func (id3tag ID3Tag) GetInvolvedPeople() ([]Credit, error) {
+	if credits, err := id3tag.get_credits("TIPL"); err == nil {
+		return credits, nil
+	}
+	return id3tag.get_credits("IPLS")
+}
+
+// get_credits decodes the alternating roles and names of the first frameid frame. An unpaired role at
+// the end is given with no name.
+func (id3tag ID3Tag) get_credits(frameid string) ([]Credit, error) {
+	values, err := id3tag.get_text_values(frameid)
+	if err != nil {
+		return nil, err
+	}
+	credits := make([]Credit, 0, (len(values)+1)/2)
+	for j := 0; j < len(values); j += 2 {
+		credit := Credit{Role: strings.TrimSpace(values[j])}
+		if j+1 < len(values) {
+			credit.Name = strings.TrimSpace(values[j+1])
+		}
+		if credit.Role != "" || credit.Name != "" {
+			credits = append(credits, credit)
+		}
+	}
+	if len(credits) == 0 {
+		return nil, errors.New(fmt.Sprintf("Frame %v holds no credits", frameid))
+	}
+	return credits, nil
+}
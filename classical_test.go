@@ -0,0 +1,47 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassical(t *testing.T) {
+	raw := make_tag(4,
+		make_frame(4, "TIT1", 0, []byte("\x03Symphony No. 5")),
+		make_frame(4, "TIT3", 0, []byte("\x03Allegro con brio")),
+		make_frame(4, "MVNM", 0, []byte("\x03I. Allegro con brio")),
+		make_frame(4, "MVIN", 0, []byte("\x031/4")),
+		make_frame(4, "TCOM", 0, []byte("\x03Ludwig van Beethoven")),
+		make_frame(4, "TSOC", 0, []byte("\x03Beethoven, Ludwig van")),
+		make_frame(4, "TPE3", 0, []byte("\x03Carlos Kleiber")),
+		make_frame(4, "TMCL", 0, []byte("\x03violin\x00Anne\x00piano\x00Bob")),
+		make_frame(4, "TIPL", 0, []byte("\x03producer\x00Carol\x00engineer")),
+	)
+	id3tag, err := ReadID3Bytes(raw)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := Classical{
+		Work: "Symphony No. 5", Movement: "I. Allegro con brio", MovementNumber: 1, MovementCount: 4,
+		Composer: "Ludwig van Beethoven", ComposerSort: "Beethoven, Ludwig van", Conductor: "Carlos Kleiber",
+		Performers: []Credit{{"violin", "Anne"}, {"piano", "Bob"}},
+		Involved:   []Credit{{"producer", "Carol"}, {"engineer", ""}},
+	}
+	if got := id3tag.Classical(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected classical fields %+v", got)
+	}
+
+	v23, err := ReadID3Bytes(make_tag(3,
+		make_frame(3, "TIT3", 0, []byte("\x00Adagio")),
+		make_frame(3, "IPLS", 0, []byte("\x00arranger\x00Dave\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	cl := v23.Classical()
+	if cl.Movement != "Adagio" || !reflect.DeepEqual(cl.Involved, []Credit{{"arranger", "Dave"}}) || cl.Performers != nil {
+		t.Errorf("Unexpected v2.3 classical fields %+v", cl)
+	}
+}
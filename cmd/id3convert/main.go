@@ -0,0 +1,275 @@
+// Command id3convert converts the ID3v2 tags of audio files in bulk to one tag version, for libraries
+// tagged over the years by tools writing different versions.
+//
+// Usage:
+//
+//	id3convert [-to 2.4] [-utf8=true] [-strip-v1=true] [-force] [-json | -ndjson] path...
+//
+// Each path is a file or a directory searched for files with an .mp3 extension. Every tag is converted
+// with ID3Tag.Convert, ISO-8859-1 text is re-encoded as UTF-8 when converting to v2.4, and ID3v1 tags are
+// removed. A summary of the changes made to each file is printed.
+//
+// Tags that could only be read in part, with bytes that could not be parsed or problems worked around,
+// are left alone with a "parse_error" outcome and the problems listed in place of the changes, since
+// writing back the frames that were read would delete the rest. -force converts them anyway.
+//
+// With -json the summary is printed as a single JSON object whose "files" member lists a record per file,
+// and with -ndjson as one record per line as each file is done. Records always hold the same members:
+//
+//	{"path": "a.mp3", "outcome": "ok", "changes": ["Converted ID3v2.3 to ID3v2.4"], "error": ""}
+//
+// "outcome" is one of "ok", "no_tag", "parse_error", "write_error" and "error", as for the exit status
+// below. "changes" is empty for files left unchanged and "error" is empty for files that were converted,
+// otherwise describing what went wrong in place of the message printed on standard error.
+//
+// The exit status tells scripts how the run went:
+//
+//	0  every file was converted or needed no change
+//	1  a file could not be read, or the files failed in different ways
+//	2  the command line is invalid
+//	3  no file had an ID3v2 tag
+//	4  the tags of the files could not be parsed
+//	5  the converted tags could not be written
+//	6  some files were converted and others failed
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+type config struct {
+	to       id3v2reader.Version
+	utf8     bool
+	strip_v1 bool
+	force    bool
+}
+
+// exit statuses, which double as the outcomes of single files
+const (
+	exit_ok = iota
+	exit_failure
+	exit_usage
+	exit_no_tag
+	exit_parse_error
+	exit_write_error
+	exit_partial
+)
+
+var outcome_names = map[int]string{
+	exit_ok:          "ok",
+	exit_failure:     "error",
+	exit_no_tag:      "no_tag",
+	exit_parse_error: "parse_error",
+	exit_write_error: "write_error",
+}
+
+// A file_result is the record printed for each file in the JSON output modes
+type file_result struct {
+	Path    string   `json:"path"`
+	Outcome string   `json:"outcome"`
+	Changes []string `json:"changes"`
+	Error   string   `json:"error"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:len(os.Args)], os.Stdout, os.Stderr))
+}
+
+// run converts the files args name and returns the exit status
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("id3convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	to := flags.String("to", "2.4", "ID3v2 `version` to convert tags to, 2.3 or 2.4")
+	var cfg config
+	flags.BoolVar(&cfg.utf8, "utf8", true, "re-encode ISO-8859-1 text as UTF-8 when converting to 2.4")
+	flags.BoolVar(&cfg.strip_v1, "strip-v1", true, "remove ID3v1 tags")
+	flags.BoolVar(&cfg.force, "force", false, "convert tags that could only be read in part, losing the rest")
+	as_json := flags.Bool("json", false, "print the summary as a JSON object")
+	as_ndjson := flags.Bool("ndjson", false, "print the summary as a JSON record per line")
+	if err := flags.Parse(args); err != nil {
+		return exit_usage
+	}
+	if *as_json && *as_ndjson {
+		fmt.Fprintln(stderr, "id3convert: -json and -ndjson cannot be combined")
+		return exit_usage
+	}
+	switch strings.TrimPrefix(*to, "2.") {
+	case "3":
+		cfg.to = id3v2reader.Version23
+	case "4":
+		cfg.to = id3v2reader.Version24
+	default:
+		fmt.Fprintf(stderr, "id3convert: cannot convert to version %q\n", *to)
+		return exit_usage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exit_usage
+	}
+
+	paths, err := find_files(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "id3convert: %v\n", err)
+		return exit_failure
+	}
+	outcomes := make(map[int]int)
+	results := make([]file_result, 0, len(paths))
+	encoder := json.NewEncoder(stdout)
+	for _, path := range paths {
+		changes, outcome, err := convert_file(path, cfg)
+		outcomes[outcome]++
+		result := file_result{Path: path, Outcome: outcome_names[outcome]}
+		result.Changes = append(make([]string, 0, len(changes)), changes...)
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		switch {
+		case *as_json:
+			results = append(results, result)
+		case *as_ndjson:
+			encoder.Encode(result)
+		case outcome == exit_no_tag:
+			fmt.Fprintf(stdout, "%v: no ID3v2 tag\n", path)
+			for _, change := range changes {
+				fmt.Fprintf(stdout, "  %v\n", change)
+			}
+		case err != nil:
+			fmt.Fprintf(stderr, "id3convert: %v: %v\n", path, err)
+			for _, change := range changes {
+				fmt.Fprintf(stderr, "  %v\n", change)
+			}
+		case len(changes) == 0:
+			fmt.Fprintf(stdout, "%v: unchanged\n", path)
+		default:
+			fmt.Fprintf(stdout, "%v:\n", path)
+			for _, change := range changes {
+				fmt.Fprintf(stdout, "  %v\n", change)
+			}
+		}
+	}
+	if *as_json {
+		encoder.SetIndent("", "  ")
+		encoder.Encode(struct {
+			Files []file_result `json:"files"`
+		}{results})
+	}
+	return exit_status(outcomes)
+}
+
+// exit_status returns the exit status of a run given the number of files with each outcome
+func exit_status(outcomes map[int]int) int {
+	if len(outcomes) == 0 || len(outcomes) == 1 && outcomes[exit_ok] > 0 {
+		return exit_ok
+	}
+	if outcomes[exit_ok] > 0 {
+		return exit_partial
+	}
+	if len(outcomes) > 1 {
+		return exit_failure
+	}
+	for outcome := range outcomes {
+		return outcome
+	}
+	return exit_ok
+}
+
+// find_files returns the files args name, with directories replaced by the .mp3 files within them
+func find_files(args []string) ([]string, error) {
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(path), ".mp3") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// convert_file converts the tag of the file at path as cfg asks and returns the changes made, the outcome
+// and the error behind it. Files without an ID3v2 tag still have their ID3v1 tag removed. Files whose tag
+// was only read in part are left unchanged unless cfg.force is set, with the problems in place of the
+// changes.
+func convert_file(path string, cfg config) ([]string, int, error) {
+	fil, err := os.Open(path)
+	if err != nil {
+		return nil, exit_failure, err
+	}
+	id3tag, err := id3v2reader.ReadID3(fil)
+	fil.Close()
+
+	outcome := exit_ok
+	changes := make([]string, 0)
+	if err == nil && !cfg.force {
+		if problems := read_problems(id3tag); len(problems) > 0 {
+			return problems, exit_parse_error, errors.New("tag could only be read in part, use -force to convert it anyway")
+		}
+	}
+	if err == nil {
+		opts := make([]id3v2reader.ConvertOption, 0, 1)
+		if cfg.utf8 {
+			opts = append(opts, id3v2reader.ToUTF8())
+		}
+		converted, convert_changes, err := id3tag.Convert(cfg.to, opts...)
+		if err != nil {
+			return nil, exit_parse_error, err
+		}
+		if len(convert_changes) > 0 {
+			if err := id3v2reader.WriteFile(path, converted); err != nil {
+				return nil, exit_write_error, err
+			}
+			changes = append(changes, convert_changes...)
+		}
+	} else if errors.Is(err, id3v2reader.ErrNoTag) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		//files too short to hold a tag header have no tag either
+		outcome, err = exit_no_tag, id3v2reader.ErrNoTag
+	} else {
+		return nil, exit_parse_error, err
+	}
+
+	if cfg.strip_v1 {
+		stripped, err := id3v2reader.StripID3v1(path)
+		if err != nil {
+			return changes, exit_write_error, err
+		}
+		if stripped {
+			changes = append(changes, "Removed the ID3v1 tag")
+		}
+	}
+	return changes, outcome, err
+}
+
+// read_problems lists the parts of id3tag that were skipped or worked around when reading it
+func read_problems(id3tag id3v2reader.ID3Tag) []string {
+	problems := make([]string, 0, len(id3tag.Warnings)+1)
+	if id3tag.UnparsedBytes > 0 {
+		problems = append(problems, fmt.Sprintf("%v bytes of the tag could not be parsed", id3tag.UnparsedBytes))
+	}
+	return append(problems, id3tag.Warnings...)
+}
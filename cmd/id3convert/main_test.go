@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	tagged := filepath.Join(dir, "tagged.mp3")
+	id3tag, err := id3v2reader.NewTagBuilder().Title("Title").Year(2004).Build(id3v2reader.Version23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := id3v2reader.WriteID3(&buf, id3tag); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("audio")
+	buf.WriteString("TAG")
+	buf.Write(make([]byte, 125))
+	if err := os.WriteFile(tagged, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untagged.MP3"), []byte("plain audio data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{dir}, &stdout, &stderr); status != exit_partial {
+		t.Fatalf("Exit status %v: %v", status, stderr.String())
+	}
+	for _, want := range []string{
+		tagged + ":\n  Converted ID3v2.3 to ID3v2.4\n",
+		"  Removed the ID3v1 tag\n",
+		"untagged.MP3: no ID3v2 tag\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output %q", want, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "notes.txt") {
+		t.Errorf("Unexpected output for a file without an .mp3 extension: %q", stdout.String())
+	}
+
+	data, err := os.ReadFile(tagged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err := id3v2reader.ReadID3Bytes(data)
+	if err != nil {
+		t.Fatalf("Error in reading converted tag: %v", err)
+	}
+	if year, _ := converted.GetTextFrameData("TDRC"); converted.Version != id3v2reader.Version24 || year != "2004" {
+		t.Errorf("Unexpected converted tag v%v %q", converted.Version, year)
+	}
+	if !bytes.HasSuffix(data, []byte("audio")) {
+		t.Errorf("ID3v1 tag not removed")
+	}
+
+	stdout.Reset()
+	if status := run([]string{tagged}, &stdout, &stderr); status != 0 || stdout.String() != tagged+": unchanged\n" {
+		t.Errorf("Converting again gave status %v, output %q", status, stdout.String())
+	}
+	if status := run([]string{filepath.Join(dir, "untagged.MP3")}, &stdout, &stderr); status != exit_no_tag {
+		t.Errorf("Expected status %v for an untagged file, got %v", exit_no_tag, status)
+	}
+	if status := run([]string{"-to", "2.2", tagged}, &stdout, &stderr); status != 2 {
+		t.Errorf("Expected status 2 for an unsupported version, got %v", status)
+	}
+	if status := run([]string{filepath.Join(dir, "missing.mp3")}, &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 for a missing file, got %v", status)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	id3tag, err := id3v2reader.NewTagBuilder().Title("Title").Build(id3v2reader.Version23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := id3v2reader.WriteID3(&buf, id3tag); err != nil {
+		t.Fatal(err)
+	}
+	tagged := filepath.Join(dir, "tagged.mp3")
+	if err := os.WriteFile(tagged, append(buf.Bytes(), "audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.mp3")
+	if err := os.WriteFile(broken, []byte("ID3\x05\x00\x00\x00\x00\x00\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-ndjson", "-to", "2.3", tagged, broken}, &stdout, &stderr); status != exit_partial {
+		t.Errorf("Expected status 6 with a broken file, got %v", status)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("Unexpected error output %q", stderr.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a record per file, got %q", stdout.String())
+	}
+	var records [2]map[string]interface{}
+	for j, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[j]); err != nil {
+			t.Fatalf("Invalid record %q: %v", line, err)
+		}
+	}
+	want := map[string]interface{}{"path": tagged, "outcome": "ok", "changes": []interface{}{}, "error": ""}
+	if !reflect.DeepEqual(records[0], want) {
+		t.Errorf("Expected %v, got %v", want, records[0])
+	}
+	if records[1]["path"] != broken || records[1]["outcome"] != "parse_error" || records[1]["error"] == "" {
+		t.Errorf("Unexpected record for a broken file %v", records[1])
+	}
+
+	stdout.Reset()
+	if status := run([]string{"-json", tagged}, &stdout, &stderr); status != 0 {
+		t.Errorf("Unexpected status %v", status)
+	}
+	var doc struct {
+		Files []file_result `json:"files"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", stdout.String(), err)
+	}
+	if len(doc.Files) != 1 || doc.Files[0].Path != tagged || len(doc.Files[0].Changes) != 2 {
+		t.Errorf("Unexpected JSON output %+v", doc)
+	}
+
+	if status := run([]string{"-json", "-ndjson", tagged}, &stdout, &stderr); status != 2 {
+		t.Errorf("Expected status 2 combining -json and -ndjson, got %v", status)
+	}
+}
+
+func TestPartialTag(t *testing.T) {
+	//a v2.3 tag holding a TIT2 frame followed by a frame with an invalid ID
+	tag := []byte("ID3\x03\x00\x00\x00\x00\x00\x1e" +
+		"TIT2\x00\x00\x00\x06\x00\x00\x00Title" +
+		"t!t!\x00\x00\x00\x04\x00\x00junk")
+	path := filepath.Join(t.TempDir(), "partial.mp3")
+	content := append(tag, "audio"...)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-ndjson", path}, &stdout, &stderr); status != exit_parse_error {
+		t.Errorf("Expected status %v for a partly read tag, got %v", exit_parse_error, status)
+	}
+	var record file_result
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid record %q: %v", stdout.String(), err)
+	}
+	if record.Outcome != "parse_error" || record.Error == "" || len(record.Changes) == 0 {
+		t.Errorf("Unexpected record for a partly read tag %+v", record)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, content) {
+		t.Errorf("Partly read tag was rewritten")
+	}
+
+	stdout.Reset()
+	if status := run([]string{"-force", path}, &stdout, &stderr); status != exit_ok {
+		t.Errorf("Expected -force to convert the tag, got status %v: %v", status, stderr.String())
+	}
+	data, _ := os.ReadFile(path)
+	if converted, err := id3v2reader.ReadID3Bytes(data); err != nil || converted.Version != id3v2reader.Version24 {
+		t.Errorf("Tag not converted with -force: %v", err)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	cases := []struct {
+		outcomes map[int]int
+		want     int
+	}{
+		{map[int]int{}, exit_ok},
+		{map[int]int{exit_ok: 3}, exit_ok},
+		{map[int]int{exit_no_tag: 2}, exit_no_tag},
+		{map[int]int{exit_parse_error: 1}, exit_parse_error},
+		{map[int]int{exit_write_error: 1}, exit_write_error},
+		{map[int]int{exit_failure: 1}, exit_failure},
+		{map[int]int{exit_ok: 1, exit_write_error: 1}, exit_partial},
+		{map[int]int{exit_ok: 1, exit_no_tag: 1}, exit_partial},
+		{map[int]int{exit_parse_error: 1, exit_write_error: 1}, exit_failure},
+	}
+	for _, c := range cases {
+		if got := exit_status(c.outcomes); got != c.want {
+			t.Errorf("%v: expected %v, got %v", c.outcomes, c.want, got)
+		}
+	}
+}
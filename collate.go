@@ -0,0 +1,40 @@
+//go:build collate && !id3v2core
+
+package id3v2reader
+
+import (
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// text_collator is a Collator from golang.org/x/text/collate, which cannot be used concurrently on its own
+type text_collator struct {
+	sync.Mutex
+	collator *collate.Collator
+	buf      collate.Buffer
+}
+
+func (c *text_collator) Key(txt string) []byte {
+	c.Lock()
+	defer c.Unlock()
+	key := append([]byte(nil), c.collator.KeyFromString(&c.buf, txt)...)
+	c.buf.Reset()
+	return key
+}
+
+func init() {
+	var collators sync.Map
+	RegisterCollation(func(locale string) (Collator, error) {
+		if c, ok := collators.Load(locale); ok {
+			return c.(Collator), nil
+		}
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, err
+		}
+		c, _ := collators.LoadOrStore(locale, &text_collator{collator: collate.New(tag)})
+		return c.(Collator), nil
+	})
+}
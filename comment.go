@@ -0,0 +1,96 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LocalizedText is the content of a COMM comment or USLT unsynchronised lyrics frame, which share a layout:
+// an ISO-639-2 language code, a short content description and the text itself.
+type LocalizedText struct {
+	Language    string
+	Description string
+	Text        string
+}
+
+// GetComments returns every comment in the tag, in tag order.
+func (id3tag ID3Tag) GetComments() ([]LocalizedText, error) {
+	return id3tag.get_localized_texts("COMM")
+}
+
+// GetLyrics returns every set of unsynchronised lyrics in the tag, in tag order.
+func (id3tag ID3Tag) GetLyrics() ([]LocalizedText, error) {
+	return id3tag.get_localized_texts("USLT")
+}
+
+// GetCommentByLanguage returns the comment in the language lang, an ISO-639-2 code such as "eng". Since
+// multilingual releases are rarely tagged consistently it falls back to a comment marked "und"
+// (undetermined), then to one with no language, then to the first comment available. Within each step,
+// comments without a description are preferred over ones carrying machine data such as iTunNORM.
+func (id3tag ID3Tag) GetCommentByLanguage(lang string) (string, error) {
+	return id3tag.get_localized_text("COMM", lang)
+}
+
+// GetLyricsByLanguage returns the unsynchronised lyrics in the language lang, with the same fallbacks as
+// GetCommentByLanguage.
+func (id3tag ID3Tag) GetLyricsByLanguage(lang string) (string, error) {
+	return id3tag.get_localized_text("USLT", lang)
+}
+
+func (id3tag ID3Tag) get_localized_texts(frameid string) ([]LocalizedText, error) {
+	ret := make([]LocalizedText, 0)
+	for _, framedata := range id3tag.tag_data(frameid) {
+		if len(framedata) < 4 {
+			continue
+		}
+		encoding := framedata[0]
+		desc, text := split_text(encoding, framedata[4:len(framedata)])
+		var lt LocalizedText
+		var err error
+		lt.Language = strings.TrimSpace(decodeISO88591(framedata[1:4]))
+		if lt.Description, err = id3tag.decodetext(encoding, desc); err != nil {
+			continue
+		}
+		if lt.Text, err = id3tag.decodetext(encoding, text); err != nil {
+			continue
+		}
+		ret = append(ret, lt)
+	}
+	if len(ret) == 0 {
+		return ret, errors.New(fmt.Sprintf("No such frame %v found in the taglist", frameid))
+	}
+	return ret, nil
+}
+
+func (id3tag ID3Tag) get_localized_text(frameid string, lang string) (string, error) {
+	texts, err := id3tag.get_localized_texts(frameid)
+	if err != nil {
+		return "", err
+	}
+
+	for _, want := range []string{lang, "und", ""} {
+		var found *LocalizedText
+		for j := range texts {
+			if strings.EqualFold(texts[j].Language, want) {
+				if texts[j].Description == "" {
+					return texts[j].Text, nil
+				}
+				if found == nil {
+					found = &texts[j]
+				}
+			}
+		}
+		if found != nil {
+			return found.Text, nil
+		}
+	}
+	for _, lt := range texts {
+		if lt.Description == "" {
+			return lt.Text, nil
+		}
+	}
+	return texts[0].Text, nil
+}
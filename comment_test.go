@@ -0,0 +1,57 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCommentByLanguage(t *testing.T) {
+	comm := func(lang, desc, text string) []byte {
+		return make_frame(4, "COMM", 0, []byte("\x03"+lang+desc+"\x00"+text))
+	}
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		comm("eng", "iTunNORM", " 00000123 00000456"),
+		comm("deu", "", "Deutsch"),
+		comm("eng", "", "English"),
+		comm("und", "", "Undetermined"),
+		make_frame(4, "USLT", 0, []byte("\x01fra\xff\xfe\x00\x00\xff\xfeB\x00o\x00n\x00j\x00o\x00u\x00r\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	cases := map[string]string{
+		"eng": "English",
+		"DEU": "Deutsch",
+		"jpn": "Undetermined",
+	}
+	for lang, want := range cases {
+		if got, err := id3tag.GetCommentByLanguage(lang); err != nil || got != want {
+			t.Errorf("GetCommentByLanguage(%q) = %q, %v; want %q", lang, got, err, want)
+		}
+	}
+	if got, err := id3tag.GetLyricsByLanguage("eng"); err != nil || got != "Bonjour" {
+		t.Errorf("Expected lyrics to fall back to the only lyrics available, got %q, %v", got, err)
+	}
+	if comments, _ := id3tag.GetComments(); len(comments) != 4 || comments[0].Description != "iTunNORM" {
+		t.Errorf("Unexpected comments %+v", comments)
+	}
+
+	fil, err := os.Open("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fil.Close()
+	id3tag, err = ReadID3(fil)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if got, err := id3tag.GetCommentByLanguage("eng"); err != nil || got == "" {
+		t.Errorf("Expected the sample comment, got %q, %v", got, err)
+	}
+	if _, err := id3tag.GetLyricsByLanguage("eng"); err == nil {
+		t.Errorf("Expected an error for a tag without lyrics")
+	}
+}
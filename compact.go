@@ -0,0 +1,104 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Compact rewrites the ID3v2 tag at the start of the file at path to take as little space as it can, and
+// returns the number of bytes reclaimed. Padding beyond keepPadding bytes is removed, and frames that
+// duplicate an earlier frame of the tag, as matched by MergeID3, are dropped: the getters only ever return
+// the first, so the values the tag yields are unchanged. Dropping duplicates alters the tag, so frames
+// flagged DiscardOnTagAlter are then dropped too. The file is left as it is when there is nothing to
+// reclaim, including when it has no tag. Tags that could not be read in full, with UnparsedBytes or
+// Warnings, are refused, since writing back the frames that were read would delete the rest. opts apply
+// as they do to WriteFile, so WithBackup saves the tag before it is compacted, except that keepPadding
+// overrides WithPadding.
+func Compact(path string, keepPadding int, opts ...WriteOption) (int64, error) {
+	if keepPadding < 0 {
+		return 0, errors.New(fmt.Sprintf("Invalid padding %v", keepPadding))
+	}
+	fil, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer fil.Close()
+
+	id3tag, err := ReadID3(fil)
+	if err == ErrNoTag {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if err := check_complete(id3tag); err != nil {
+		return 0, err
+	}
+	old_length := 10 + int64(id3tag.Size)
+	if id3tag.Footer {
+		old_length += 10
+	}
+
+	opts = append(make([]WriteOption, 0, len(opts)+2), opts...)
+	if frames := distinct_frames(id3tag.Version, id3tag.Frames); len(frames) < len(id3tag.Frames) {
+		id3tag.Frames = frames
+		opts = append(opts, TagAltered())
+	}
+	padding := id3tag.PaddingBytes
+	if uint64(keepPadding) < uint64(padding) {
+		padding = uint32(keepPadding)
+	}
+	opts = append(opts, WithPadding(padding))
+
+	cfg := new_write_config(opts)
+	buf, err := encode_tag(id3tag, cfg)
+	if err != nil {
+		return 0, err
+	}
+	reclaimed := old_length - int64(len(buf))
+	if reclaimed <= 0 {
+		return 0, nil
+	}
+	if cfg.backup {
+		if err := backup_tag(path, fil, old_length); err != nil {
+			return 0, err
+		}
+	}
+	if err := rewrite_file(path, fil, buf, old_length); err != nil {
+		return 0, err
+	}
+	return reclaimed, nil
+}
+
+// check_complete returns an error if reading the tag skipped or worked around part of it, which writing
+// the tag back would lose
+func check_complete(id3tag ID3Tag) error {
+	if id3tag.UnparsedBytes > 0 {
+		return errors.New(fmt.Sprintf("Tag has %v bytes that could not be parsed", id3tag.UnparsedBytes))
+	}
+	if len(id3tag.Warnings) > 0 {
+		return errors.New("Tag was read with problems worked around: " + strings.Join(id3tag.Warnings, "; "))
+	}
+	return nil
+}
+
+// distinct_frames returns frames of a tag of version ver less those whose frame_key, taken with their
+// v2.3 frame ID, matches an earlier frame's
+func distinct_frames(ver Version, frames []ID3Frame) []ID3Frame {
+	seen := make(map[string]bool)
+	ret := make([]ID3Frame, 0, len(frames))
+	for _, frame := range frames {
+		keyed := frame
+		keyed.FrameID = v23_frameid(ver, frame.FrameID)
+		key := frame_key(keyed)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ret = append(ret, frame)
+	}
+	return ret
+}
@@ -0,0 +1,122 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "padded.mp3")
+	tag := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "PRIV", 0, []byte("owner\x00data")),
+		make_frame(4, "TIT2", 0, []byte("\x03Stale title")),
+		make_frame(4, "PRIV", 0, []byte("owner\x00data")),
+		make_frame(4, "PRIV", 0, []byte("owner\x00other")),
+		make([]byte, 1000),
+	)
+	if err := os.WriteFile(path, append(tag, "audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reclaimed, err := Compact(path, 100)
+	if err != nil {
+		t.Fatalf("Error in compacting: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed != int64(len(tag)+5-len(data)) || reclaimed <= 900 {
+		t.Errorf("Reported %v bytes reclaimed, file shrank by %v", reclaimed, len(tag)+5-len(data))
+	}
+	id3tag, err := ReadID3Bytes(data)
+	if err != nil {
+		t.Fatalf("Error in reading compacted tag: %v", err)
+	}
+	if got := id3tag.Order(); !reflect.DeepEqual(got, []string{"TIT2", "PRIV", "PRIV"}) {
+		t.Errorf("Unexpected frames %v", got)
+	}
+	if title, _ := id3tag.GetTitle(); title != "Title" || id3tag.PaddingBytes != 100 {
+		t.Errorf("Unexpected title %q padding %v", title, id3tag.PaddingBytes)
+	}
+	if !bytes.HasSuffix(data, []byte("audio")) {
+		t.Errorf("Audio lost in compacting: %q", data)
+	}
+
+	if reclaimed, err := Compact(path, 100); err != nil || reclaimed != 0 {
+		t.Errorf("Compacting again reclaimed %v, %v", reclaimed, err)
+	}
+	if reclaimed, err := Compact(path, 1000); err != nil || reclaimed != 0 {
+		t.Errorf("Compacting with more padding reclaimed %v, %v", reclaimed, err)
+	}
+
+	untagged := filepath.Join(dir, "untagged.mp3")
+	if err := os.WriteFile(untagged, []byte("just audio data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed, err := Compact(untagged, 0); err != nil || reclaimed != 0 {
+		t.Errorf("Compacting an untagged file reclaimed %v, %v", reclaimed, err)
+	}
+	if _, err := Compact(path, -1); err == nil {
+		t.Errorf("Expected an error for negative padding")
+	}
+}
+
+func TestCompactV22(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v22.mp3")
+	tag := make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Title")),
+		make_frame(2, "TXX", 0, []byte("\x00one\x001")),
+		make_frame(2, "TXX", 0, []byte("\x00two\x002")),
+		make_frame(2, "TT2", 0, []byte("\x00Stale title")),
+		make([]byte, 500),
+	)
+	if err := os.WriteFile(path, append(tag, "audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reclaimed, err := Compact(path, 10)
+	if err != nil {
+		t.Fatalf("Error in compacting: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Title")),
+		make_frame(2, "TXX", 0, []byte("\x00one\x001")),
+		make_frame(2, "TXX", 0, []byte("\x00two\x002")),
+		make([]byte, 10),
+	)
+	if !bytes.Equal(data, append(want, "audio"...)) || reclaimed != int64(len(tag)-len(want)) {
+		t.Errorf("Unexpected compacted file, %v bytes reclaimed: %q", reclaimed, data)
+	}
+}
+
+func TestCompactIncomplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "damaged.mp3")
+	tag := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "TPE1", 0, []byte("\x03Artist")),
+		make_frame(4, "t!t!", 0, []byte("\x03Unparseable")),
+		make_frame(4, "TALB", 0, []byte("\x03Album")),
+		make([]byte, 100),
+	)
+	file := append(tag, "audio"...)
+	if err := os.WriteFile(path, file, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed, err := Compact(path, 0); err == nil {
+		t.Errorf("Expected a tag with unparsed bytes to be refused, reclaimed %v", reclaimed)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, file) {
+		t.Errorf("Refused file was changed, %v", err)
+	}
+}
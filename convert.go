@@ -0,0 +1,272 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A ConvertOption changes how Convert converts a tag.
+type ConvertOption func(*convert_config)
+
+type convert_config struct {
+	utf8 bool
+}
+
+// ToUTF8 makes Convert re-encode ISO-8859-1 text as UTF-8 in ID3v2.4 tags. v2.3 has no UTF-8, so the
+// option does nothing there.
+func ToUTF8() ConvertOption {
+	return func(cfg *convert_config) {
+		cfg.utf8 = true
+	}
+}
+
+// layout_changed lists the frames whose content differs in layout between the versions they are
+// translated between, which Convert drops as it cannot carry them over
+var layout_changed = map[string]bool{"RVAD": true, "RVA2": true, "EQUA": true, "EQU2": true, "RVA": true, "EQU": true, "LNK": true}
+
+// Convert returns a copy of the tag as a tag of version ver, which must be Version23 or Version24, along
+// with a description of each change made. Frame IDs are translated as TranslateFrameID translates them,
+// the TYER, TDAT and TIME frames of earlier versions are combined into a v2.4 TDRC timestamp and split
+// back again, and frame flags are rewritten in the layout of ver. Text of text frames, TXXX, COMM, USLT,
+// and the descriptions of WXXX and APIC frames, is re-encoded where ver cannot hold its encoding, as for
+// UTF-8 in v2.3. Frames ver has no equivalent of, whose content changed layout between the versions, or
+// that are unsynchronised on their own, which only v2.4 allows, are dropped. Compressed and encrypted
+// frames are carried over as they are.
+func (id3tag ID3Tag) Convert(ver Version, opts ...ConvertOption) (ID3Tag, []string, error) {
+	if ver != Version23 && ver != Version24 {
+		return ID3Tag{}, nil, errors.New(fmt.Sprintf("Cannot convert to ID3v%v tags", ver))
+	}
+	var cfg convert_config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	from := id3tag.Version
+	if from == 0 {
+		from = Version24
+	}
+
+	changes := make([]string, 0)
+	frames := make([]ID3Frame, 0, len(id3tag.Frames))
+	dated := false
+	for _, frame := range id3tag.Frames {
+		if (from == Version24) != (ver == Version24) && is_date_frame(from, frame.FrameID) {
+			if !dated {
+				dated = true
+				date_frames, change := id3tag.convert_dates(from, ver)
+				frames = append(frames, date_frames...)
+				changes = append(changes, change)
+			}
+			continue
+		}
+
+		frameid, ok := TranslateFrameID(frame.FrameID, from, ver)
+		if !ok || frameid != frame.FrameID && layout_changed[frame.FrameID] {
+			changes = append(changes, fmt.Sprintf("Dropped %v, which ID3v%v has no equivalent of", frame.FrameID, ver))
+			continue
+		}
+		if frame.Unsynchronisation && ver == Version23 {
+			changes = append(changes, fmt.Sprintf("Dropped %v, whose unsynchronised data ID3v2.3 cannot hold", frame.FrameID))
+			continue
+		}
+		if frameid != frame.FrameID {
+			changes = append(changes, fmt.Sprintf("Renamed %v to %v", frame.FrameID, frameid))
+		}
+
+		if from == Version22 && frame.FrameID == "PIC" {
+			pic, err := id3tag.parse_picture(frame.Data)
+			if err != nil {
+				changes = append(changes, fmt.Sprintf("Dropped %v: %v", frame.FrameID, err))
+				continue
+			}
+			frame.Data = encode_picture(ver, pic).Data
+		}
+		frame.FrameID = frameid
+		if from != ver {
+			frame = convert_flags(frame, ver)
+			frame.header = nil
+		}
+		if recoded, encoding, ok := id3tag.recode_frame(frame, ver, cfg.utf8); ok {
+			frame.Data, frame.Length, frame.header = recoded, uint32(len(recoded)), nil
+			changes = append(changes, fmt.Sprintf("Re-encoded %v text as %v", frame.FrameID, encoding_names[encoding]))
+		}
+		frames = append(frames, frame)
+	}
+
+	id3tag.Frames = frames
+	id3tag.Version = ver
+	id3tag.cache = id3tag.fresh_cache()
+	if ver != from {
+		id3tag.Revision = 0
+		id3tag.Footer = false
+		id3tag.altered = true
+		changes = append([]string{fmt.Sprintf("Converted ID3v%v to ID3v%v", from, ver)}, changes...)
+	} else if len(changes) > 0 {
+		id3tag.altered = true
+	}
+	return id3tag, changes, nil
+}
+
+// is_date_frame reports whether frameid is one of the frames of a version ver tag that Convert combines into
+// or splits from TDRC
+func is_date_frame(ver Version, frameid string) bool {
+	if ver == Version24 {
+		return frameid == "TDRC"
+	}
+	for _, row := range frame_translations {
+		if row[2] == "TDRC" && row[ver-2] == frameid {
+			return true
+		}
+	}
+	return false
+}
+
+// convert_dates returns the frames giving the recording date of the tag, of version from, in a tag of
+// version ver, and a description of the change
+func (id3tag ID3Tag) convert_dates(from, ver Version) ([]ID3Frame, string) {
+	text_frame := func(frameid, value string) ID3Frame {
+		data := encodetext(ver, value)
+		return ID3Frame{FrameID: frameid, Length: uint32(len(data)), Data: data}
+	}
+
+	if ver == Version24 {
+		ids := [4]string{}
+		for j, row := range frame_translations[0:4] {
+			ids[j] = row[from-2]
+		}
+		timestamp := ""
+		if year, err := id3tag.GetTextFrameData(ids[0]); err == nil && len(year) == 4 && is_digits(year) {
+			timestamp = year
+			if ddmm, err := id3tag.GetTextFrameData(ids[1]); err == nil && len(ddmm) == 4 && is_digits(ddmm) {
+				timestamp += "-" + ddmm[2:4] + "-" + ddmm[0:2]
+				if hhmm, err := id3tag.GetTextFrameData(ids[2]); err == nil && len(hhmm) == 4 && is_digits(hhmm) {
+					timestamp += "T" + hhmm[0:2] + ":" + hhmm[2:4]
+				}
+			}
+		} else if txt, err := id3tag.GetTextFrameData(ids[3]); err == nil {
+			timestamp = txt
+		}
+		if _, err := parse_timestamp(timestamp); err != nil {
+			return nil, "Dropped the recording date frames, which give no date ID3v2.4 can hold"
+		}
+		return []ID3Frame{text_frame("TDRC", timestamp)}, fmt.Sprintf("Combined the recording date frames into TDRC %q", timestamp)
+	}
+
+	txt, err := id3tag.GetTextFrameData("TDRC")
+	if err != nil {
+		return nil, "Dropped TDRC, which could not be decoded"
+	}
+	if _, err := parse_timestamp(txt); err != nil {
+		return []ID3Frame{text_frame("TRDA", txt)}, fmt.Sprintf("Moved TDRC %q to TRDA", txt)
+	}
+	frames := []ID3Frame{text_frame("TYER", txt[0:4])}
+	ids := []string{"TYER"}
+	if len(txt) >= 10 {
+		frames = append(frames, text_frame("TDAT", txt[8:10]+txt[5:7]))
+		ids = append(ids, "TDAT")
+	}
+	if len(txt) >= 16 {
+		frames = append(frames, text_frame("TIME", txt[11:13]+txt[14:16]))
+		ids = append(ids, "TIME")
+	}
+	return frames, fmt.Sprintf("Split TDRC %q into %v", txt, strings.Join(ids, ", "))
+}
+
+func is_digits(txt string) bool {
+	_, err := strconv.ParseUint(txt, 10, 32)
+	return err == nil
+}
+
+// convert_flags returns frame with its flag bytes laid out as version ver lays them out
+func convert_flags(frame ID3Frame, ver Version) ID3Frame {
+	bit := func(set bool, mask byte) byte {
+		if set {
+			return mask
+		}
+		return 0
+	}
+	if ver == Version23 {
+		frame.Data_Length_Indicator = false
+		frame.StatusFlags = bit(frame.DiscardOnTagAlter, 0x80) | bit(frame.DiscardOnFileAlter, 0x40) | bit(frame.ReadOnly, 0x20)
+		frame.FormatFlags = bit(frame.Compression, 0x80) | bit(frame.Encryption, 0x40) | bit(frame.Grouping, 0x20)
+	} else {
+		//v2.4 compressed frames must declare their decompressed size
+		frame.Data_Length_Indicator = frame.Data_Length_Indicator || frame.Compression
+		frame.StatusFlags = bit(frame.DiscardOnTagAlter, 0x40) | bit(frame.DiscardOnFileAlter, 0x20) | bit(frame.ReadOnly, 0x10)
+		frame.FormatFlags = bit(frame.Grouping, 0x40) | bit(frame.Compression, 0x08) | bit(frame.Encryption, 0x04) |
+			bit(frame.Unsynchronisation, 0x02) | bit(frame.Data_Length_Indicator, 0x01)
+	}
+	return frame
+}
+
+// recode_frame returns the content of frame with its text re-encoded and the encoding used, or false if
+// its text can stay as it is in a tag of version ver or the frame holds no text Convert re-encodes
+func (id3tag ID3Tag) recode_frame(frame ID3Frame, ver Version, utf8 bool) ([]byte, byte, bool) {
+	data := frame.Data
+	if len(data) == 0 || frame.Compression || frame.Encryption || frame.Unsynchronisation {
+		return nil, 0, false
+	}
+	encoding := data[0]
+	if encoding > 3 || ver == Version24 && (encoding != 0 || !utf8) || ver == Version23 && encoding < 2 {
+		return nil, 0, false
+	}
+
+	//the text runs from prefix to the end of the frame, or to the end of the first string only when
+	//binary data follows it
+	prefix, first_only := 1, false
+	switch {
+	case frame.FrameID == "COMM" || frame.FrameID == "USLT":
+		prefix = 4
+	case frame.FrameID == "WXXX":
+		first_only = true
+	case frame.FrameID == "APIC":
+		mime_end := bytes.IndexByte(data[1:len(data)], 0)
+		if mime_end == -1 || 1+mime_end+2 > len(data) {
+			return nil, 0, false
+		}
+		prefix, first_only = 1+mime_end+2, true
+	case strings.HasPrefix(frame.FrameID, "T") || frame.FrameID == "IPLS":
+	default:
+		return nil, 0, false
+	}
+	if len(data) < prefix {
+		return nil, 0, false
+	}
+
+	strs := make([]string, 0)
+	rest := data[prefix:len(data)]
+	terminated := false
+	for len(rest) > 0 {
+		var str []byte
+		str, rest = split_text(encoding, rest)
+		txt := ""
+		if len(str) > 0 {
+			var err error
+			if txt, err = id3tag.decodetext(encoding, str); err != nil {
+				return nil, 0, false
+			}
+		}
+		strs = append(strs, txt)
+		terminated = rest != nil
+		if first_only {
+			break
+		}
+	}
+
+	to := text_encoding(ver, strs...)
+	ret := append([]byte{to}, data[1:prefix]...)
+	for j, txt := range strs {
+		ret = append(ret, encode_string(to, txt)...)
+		if j < len(strs)-1 || terminated {
+			ret = append(ret, string_terminator(to)...)
+		}
+	}
+	if first_only {
+		ret = append(ret, rest...)
+	}
+	return ret, to, true
+}
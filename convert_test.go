@@ -0,0 +1,88 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	compressed := make_frame(3, "TXXX", 0, []byte("\x00\x00\x00\x10zlib data"))
+	compressed[9] = 0x80
+	id3tag, err := ReadID3Bytes(make_tag(3,
+		make_frame(3, "TIT2", 0, []byte("\x00Caf\xe9")),
+		make_frame(3, "TYER", 0, []byte("\x002004")),
+		make_frame(3, "TDAT", 0, []byte("\x000506")),
+		make_frame(3, "TIME", 0, []byte("\x001020")),
+		make_frame(3, "TSIZ", 0, []byte("\x001234")),
+		make_frame(3, "RVAD", 0, []byte{0x03, 0x10, 0, 0, 0, 0}),
+		make_frame(3, "COMM", 0, []byte("\x00engdesc\x00text")),
+		compressed,
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	v24, changes, err := id3tag.Convert(Version24, ToUTF8())
+	if err != nil {
+		t.Fatalf("Error in converting tag: %v", err)
+	}
+	if got := v24.Order(); !reflect.DeepEqual(got, []string{"TIT2", "TDRC", "COMM", "TXXX"}) || v24.Version != Version24 {
+		t.Errorf("Unexpected v%v frames %v", v24.Version, got)
+	}
+	if !bytes.Equal(v24.Frames[0].Data, []byte("\x03Caf\u00e9")) || !bytes.Equal(v24.Frames[2].Data, []byte("\x03engdesc\x00text")) {
+		t.Errorf("Text not re-encoded: %q %q", v24.Frames[0].Data, v24.Frames[2].Data)
+	}
+	if date, _ := v24.GetTextFrameData("TDRC"); date != "2004-06-05T10:20" {
+		t.Errorf("Unexpected TDRC %q", date)
+	}
+	if frame := v24.Frames[3]; frame.FormatFlags != 0x09 || frame.DataLength != 0x10 || string(frame.Data) != "zlib data" {
+		t.Errorf("Unexpected compressed frame %+v", frame)
+	}
+	want := []string{
+		"Converted ID3v2.3 to ID3v2.4",
+		"Re-encoded TIT2 text as UTF-8",
+		`Combined the recording date frames into TDRC "2004-06-05T10:20"`,
+		"Dropped TSIZ, which ID3v2.4 has no equivalent of",
+		"Dropped RVAD, which ID3v2.4 has no equivalent of",
+		"Re-encoded COMM text as UTF-8",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, v24); err != nil {
+		t.Fatalf("Error in writing converted tag: %v", err)
+	}
+	written, err := ReadID3Bytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Error in reading converted tag: %v", err)
+	}
+	if title, _ := written.GetTitle(); title != "Caf\u00e9" || !written.Frames[3].Compression {
+		t.Errorf("Unexpected written tag %q %+v", title, written.Frames)
+	}
+
+	v23, changes, err := written.Convert(Version23)
+	if err != nil {
+		t.Fatalf("Error in converting tag back: %v", err)
+	}
+	if got := v23.Order(); !reflect.DeepEqual(got, []string{"TIT2", "TYER", "TDAT", "TIME", "COMM", "TXXX"}) {
+		t.Errorf("Unexpected v2.3 frames %v", got)
+	}
+	if !bytes.Equal(v23.Frames[0].Data, []byte("\x00Caf\xe9")) || v23.Frames[5].FormatFlags != 0x80 {
+		t.Errorf("Unexpected v2.3 frames %+v", v23.Frames)
+	}
+	if date, _ := v23.GetRecordingDate(); date.Day() != 5 || date.Month() != 6 || date.Minute() != 20 {
+		t.Errorf("Unexpected recording date %v", date)
+	}
+	if len(changes) != 4 {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+
+	if _, _, err := id3tag.Convert(Version22); err == nil {
+		t.Errorf("Expected an error converting to ID3v2.2")
+	}
+}
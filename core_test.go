@@ -0,0 +1,37 @@
+package id3v2reader
+
+import (
+	"os"
+	"testing"
+)
+
+// TestCore checks the parser core, and is the one test that also runs in builds with the id3v2core tag
+func TestCore(t *testing.T) {
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		buf, err := os.ReadFile(filname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id3tag, err := ReadID3Bytes(buf)
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", filname, err)
+		}
+		if title, _ := id3tag.GetTitle(); title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" {
+			t.Errorf("%v: unexpected title %q", filname, title)
+		}
+		if artist, _ := id3tag.GetArtist(); artist != "Hariharan Srinath" {
+			t.Errorf("%v: unexpected artist %q", filname, artist)
+		}
+		if pic, err := id3tag.GetCoverPic(); err != nil || len(pic) == 0 {
+			t.Errorf("%v: no cover picture: %v", filname, err)
+		}
+		if len(id3tag.AllText()) == 0 {
+			t.Errorf("%v: no text frames", filname)
+		}
+	}
+
+	_, err := ReadID3Bytes([]byte("ID3\x04\x00\x00\x00\x00\x00\x0ATIT2\x00\x00\x00\x80\x00\x00"), WithStrictParsing(true))
+	if err == nil || err.Error() != "Frame TIT2 size 00 00 00 80 is not synchsafe" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
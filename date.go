@@ -0,0 +1,111 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timestamp_layouts are the precisions allowed for ID3v2.4 timestamps, richest first
+var timestamp_layouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parse_timestamp parses an ID3v2.4 timestamp, a subset of ISO 8601 from "yyyy" up to "yyyy-MM-ddTHH:mm:ss".
+// The standard gives timestamps in UTC.
+func parse_timestamp(txt string) (time.Time, error) {
+	for _, layout := range timestamp_layouts {
+		if t, err := time.ParseInLocation(layout, txt, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New(fmt.Sprintf("Could not parse timestamp %q", txt))
+}
+
+// find_year returns the first run of exactly four digits in txt
+func find_year(txt string) (int, bool) {
+	run := 0
+	for j := 0; j <= len(txt); j++ {
+		if j < len(txt) && txt[j] >= '0' && txt[j] <= '9' {
+			run++
+			continue
+		}
+		if run == 4 {
+			year, _ := strconv.Atoi(txt[j-4 : j])
+			return year, true
+		}
+		run = 0
+	}
+	return 0, false
+}
+
+// GetRecordingDate returns the recording date in as much detail as the tag provides. It uses the v2.4 TDRC
+// timestamp when present. Otherwise it assembles the v2.3 TYER year, TDAT day and month (DDMM) and TIME
+// hours and minutes (HHMM) frames, and failing those it looks for a date or at least a year in the free
+// form v2.3 TRDA recording dates. Parts the tag does not give are left at their zero value, i.e. January,
+// the 1st, midnight UTC.
+func (id3tag ID3Tag) GetRecordingDate() (time.Time, error) {
+	if txt, err := id3tag.GetTextFrameData("TDRC"); err == nil {
+		if t, err := parse_timestamp(txt); err == nil {
+			return t, nil
+		}
+	}
+
+	if txt, err := id3tag.GetTextFrameData("TYER"); err == nil {
+		if year, err := strconv.Atoi(txt); err == nil && len(txt) == 4 {
+			month, day, hour, minute := 1, 1, 0, 0
+			if ddmm, err := id3tag.GetTextFrameData("TDAT"); err == nil && len(ddmm) == 4 {
+				d, derr := strconv.Atoi(ddmm[0:2])
+				m, merr := strconv.Atoi(ddmm[2:4])
+				if derr == nil && merr == nil && m >= 1 && m <= 12 && d >= 1 && d <= 31 {
+					day, month = d, m
+				}
+			}
+			if hhmm, err := id3tag.GetTextFrameData("TIME"); err == nil && len(hhmm) == 4 {
+				h, herr := strconv.Atoi(hhmm[0:2])
+				m, merr := strconv.Atoi(hhmm[2:4])
+				if herr == nil && merr == nil && h < 24 && m < 60 {
+					hour, minute = h, m
+				}
+			}
+			return time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC), nil
+		}
+	}
+
+	if txt, err := id3tag.GetTextFrameData("TRDA"); err == nil {
+		if t, err := parse_timestamp(txt); err == nil {
+			return t, nil
+		}
+		if year, ok := find_year(txt); ok {
+			return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, errors.New("No recording date found in the taglist")
+}
+
+// GetTaggingTime returns the v2.4 TDTG tagging time: when the tag was last written.
+func (id3tag ID3Tag) GetTaggingTime() (time.Time, error) {
+	return id3tag.get_timestamp("TDTG")
+}
+
+// GetEncodingTime returns the v2.4 TDEN encoding time: when the audio was encoded.
+func (id3tag ID3Tag) GetEncodingTime() (time.Time, error) {
+	return id3tag.get_timestamp("TDEN")
+}
+
+func (id3tag ID3Tag) get_timestamp(frameid string) (time.Time, error) {
+	txt, err := id3tag.GetTextFrameData(frameid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parse_timestamp(txt)
+}
@@ -0,0 +1,70 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRecordingDate(t *testing.T) {
+	text := func(ver byte, frameid, txt string) []byte {
+		return make_frame(ver, frameid, 0, []byte("\x00"+txt))
+	}
+	cases := []struct {
+		name string
+		raw  []byte
+		want time.Time
+	}{
+		{"v2.4 timestamp", make_tag(4, text(4, "TDRC", "1999-06-02T14:30")), time.Date(1999, 6, 2, 14, 30, 0, 0, time.UTC)},
+		{"v2.4 year", make_tag(4, text(4, "TDRC", "1999")), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"v2.3 year", make_tag(3, text(3, "TYER", "1999")), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"v2.3 full", make_tag(3, text(3, "TYER", "1999"), text(3, "TDAT", "0206"), text(3, "TIME", "1430")), time.Date(1999, 6, 2, 14, 30, 0, 0, time.UTC)},
+		{"v2.3 bad TDAT", make_tag(3, text(3, "TYER", "1999"), text(3, "TDAT", "3113")), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"v2.3 TRDA", make_tag(3, text(3, "TRDA", "4th-7th June 1999")), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		id3tag, err := ReadID3Bytes(c.raw)
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", c.name, err)
+		}
+		if got, err := id3tag.GetRecordingDate(); err != nil || !got.Equal(c.want) {
+			t.Errorf("%v: expected %v, got %v, %v", c.name, c.want, got, err)
+		}
+	}
+
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		fil, err := os.Open(filname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id3tag, err := ReadID3(fil)
+		fil.Close()
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		if got, err := id3tag.GetRecordingDate(); err != nil || got.Year() != 2013 {
+			t.Errorf("%v: expected a 2013 recording date, got %v, %v", filname, got, err)
+		}
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TDTG", 0, []byte("\x032016-03-20T18:05:09")),
+		make_frame(4, "TDEN", 0, []byte("\x03March 2016")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if got, err := id3tag.GetTaggingTime(); err != nil || !got.Equal(time.Date(2016, 3, 20, 18, 5, 9, 0, time.UTC)) {
+		t.Errorf("Unexpected tagging time %v, %v", got, err)
+	}
+	if _, err := id3tag.GetEncodingTime(); err == nil {
+		t.Errorf("Expected an error for a malformed encoding time")
+	}
+	if _, err := (ID3Tag{}).GetTaggingTime(); err == nil {
+		t.Errorf("Expected an error for a missing tagging time")
+	}
+}
@@ -0,0 +1,63 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+// A MetadataResolver looks up a recording in an external source such as MusicBrainz or a CDDB server.
+// Resolve is given the fields the tag already holds, including the Duration when the tag has a TLEN
+// frame, and returns the fields of the best matching recording. Implementations live outside this
+// package so that it needs no HTTP client.
+type MetadataResolver interface {
+	Resolve(known Metadata) (Metadata, error)
+}
+
+// MetadataResolverFunc lets an ordinary function serve as a MetadataResolver.
+type MetadataResolverFunc func(known Metadata) (Metadata, error)
+
+// Resolve calls fn(known).
+func (fn MetadataResolverFunc) Resolve(known Metadata) (Metadata, error) {
+	return fn(known)
+}
+
+// Enrich returns a copy of the tag in which the fields of Metadata it lacks are filled in from what
+// resolver finds. Fields the tag holds are never replaced, and the tag is returned unchanged along with
+// the error if the resolver fails.
+func Enrich(id3tag ID3Tag, resolver MetadataResolver) (ID3Tag, error) {
+	known := id3tag.GetMetadata()
+	found, err := resolver.Resolve(known)
+	if err != nil {
+		return id3tag, err
+	}
+	for _, frame := range missing_fields(known, found).Tag(id3tag.Version).Frames {
+		id3tag = id3tag.ReplaceFrames(frame.FrameID, frame)
+	}
+	return id3tag, nil
+}
+
+// missing_fields returns the fields of found that are not set in known
+func missing_fields(known, found Metadata) Metadata {
+	var ret Metadata
+	pick := func(known, found string) string {
+		if known == "" {
+			return found
+		}
+		return ""
+	}
+	ret.Title, ret.Artist, ret.Album = pick(known.Title, found.Title), pick(known.Artist, found.Artist), pick(known.Album, found.Album)
+	ret.AlbumArtist, ret.Composer = pick(known.AlbumArtist, found.AlbumArtist), pick(known.Composer, found.Composer)
+	if known.Track == 0 {
+		ret.Track = found.Track
+	}
+	if known.Disc == 0 {
+		ret.Disc = found.Disc
+	}
+	if len(known.Genres) == 0 {
+		ret.Genres = found.Genres
+	}
+	if known.RecordingDate.IsZero() {
+		ret.RecordingDate = found.RecordingDate
+	}
+	if known.Duration == 0 {
+		ret.Duration = found.Duration
+	}
+	return ret
+}
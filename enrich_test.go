@@ -0,0 +1,41 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnrich(t *testing.T) {
+	id3tag, err := NewTagBuilder().Title("Title").Text("TLEN", "180000").Build(Version24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var query Metadata
+	resolver := MetadataResolverFunc(func(known Metadata) (Metadata, error) {
+		query = known
+		return Metadata{Title: "Other Title", Artist: "Artist", Album: "Album", Track: 4,
+			RecordingDate: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)}, nil
+	})
+	enriched, err := Enrich(id3tag, resolver)
+	if err != nil {
+		t.Fatalf("Error in enriching tag: %v", err)
+	}
+	if query.Title != "Title" || query.Duration != 3*time.Minute {
+		t.Errorf("Resolver was not given the known fields: %+v", query)
+	}
+	md := enriched.GetMetadata()
+	if md.Title != "Title" || md.Artist != "Artist" || md.Album != "Album" || md.Track != 4 || md.RecordingDate.Year() != 2001 {
+		t.Errorf("Unexpected enriched fields %+v", md)
+	}
+	if _, err := id3tag.GetArtist(); err == nil {
+		t.Errorf("Enrich changed the original tag")
+	}
+
+	failing := MetadataResolverFunc(func(Metadata) (Metadata, error) { return Metadata{}, errors.New("offline") })
+	if same, err := Enrich(id3tag, failing); err == nil || len(same.Frames) != len(id3tag.Frames) {
+		t.Errorf("Expected a failing resolver to leave the tag unchanged, got %v frames, %v", len(same.Frames), err)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+)
+
+// Interpolation is how the adjustment between the points of an equalisation curve is worked out
+type Interpolation byte
+
+const (
+	InterpolationBand   Interpolation = iota //no interpolation: each adjustment holds up to the next point
+	InterpolationLinear                      //adjustments change linearly between points
+)
+
+// An EqualizationPoint adjusts the volume at a frequency in Hz. The Adjustment is in dB for EQU2 frames;
+// EQUA frames leave its unit undefined, and it is given as stored.
+type EqualizationPoint struct {
+	Frequency  float64
+	Adjustment float64
+}
+
+// Equalization is an equalisation curve from an EQU2 frame, or an EQUA or v2.2 EQU frame, which have no
+// interpolation method or identification and so are read as linear and unidentified.
+type Equalization struct {
+	Interpolation  Interpolation
+	Identification string
+	Points         []EqualizationPoint
+}
+
+// GetEqualizations returns the equalisation curves of the EQU2 frames of the tag in the order they were
+// read, followed by that of its EQUA or EQU frame. Malformed frames are left out.
+func (id3tag ID3Tag) GetEqualizations() ([]Equalization, error) {
+	ret := make([]Equalization, 0)
+	for _, framedata := range id3tag.tag_data("EQU2") {
+		if eq, err := parse_equ2(framedata); err == nil {
+			ret = append(ret, eq)
+		}
+	}
+	for _, frameid := range []string{"EQUA", "EQU"} {
+		for _, framedata := range id3tag.tag_data(frameid) {
+			if eq, err := parse_equa(framedata); err == nil {
+				ret = append(ret, eq)
+			}
+		}
+	}
+	if len(ret) == 0 {
+		return ret, errors.New("No equalisation frame found in the taglist")
+	}
+	return ret, nil
+}
+
+// parse_equ2 parses an EQU2 frame body: the interpolation method, a null terminated identification and
+// pairs of a frequency in 1/2 Hz and a signed volume adjustment in 1/512 dB
+func parse_equ2(framedata []byte) (Equalization, error) {
+	var eq Equalization
+	if len(framedata) == 0 {
+		return eq, errors.New("EQU2 frame is empty")
+	}
+	eq.Interpolation = Interpolation(framedata[0])
+	id, rest := split_text(0, framedata[1:len(framedata)])
+	if rest == nil {
+		return eq, errors.New("EQU2 identification is not terminated")
+	}
+	eq.Identification = decodeISO88591(id)
+	if len(rest)%4 != 0 {
+		return eq, errors.New("EQU2 frame holds a partial adjustment point")
+	}
+	eq.Points = make([]EqualizationPoint, 0, len(rest)/4)
+	for pos := 0; pos < len(rest); pos += 4 {
+		freq := uint16(rest[pos])<<8 | uint16(rest[pos+1])
+		adj := int16(uint16(rest[pos+2])<<8 | uint16(rest[pos+3]))
+		eq.Points = append(eq.Points, EqualizationPoint{Frequency: float64(freq) / 2, Adjustment: float64(adj) / 512})
+	}
+	return eq, nil
+}
+
+// parse_equa parses an EQUA frame body: the number of bits of each adjustment, then for each point an
+// increment bit and a 15 bit frequency in Hz followed by the size of the adjustment
+func parse_equa(framedata []byte) (Equalization, error) {
+	eq := Equalization{Interpolation: InterpolationLinear}
+	if len(framedata) == 0 {
+		return eq, errors.New("EQUA frame is empty")
+	}
+	bits := int(framedata[0])
+	if bits == 0 || bits > 64 {
+		return eq, errors.New("EQUA frame has an invalid adjustment size")
+	}
+	size := (bits + 7) / 8
+	rest := framedata[1:len(framedata)]
+	if len(rest)%(2+size) != 0 {
+		return eq, errors.New("EQUA frame holds a partial adjustment point")
+	}
+	eq.Points = make([]EqualizationPoint, 0, len(rest)/(2+size))
+	for pos := 0; pos < len(rest); pos += 2 + size {
+		freq := uint16(rest[pos]&0x7F)<<8 | uint16(rest[pos+1])
+		adj := float64(decode_counter(rest[pos+2 : pos+2+size]))
+		if rest[pos]&0x80 == 0 {
+			adj = -adj
+		}
+		eq.Points = append(eq.Points, EqualizationPoint{Frequency: float64(freq), Adjustment: adj})
+	}
+	return eq, nil
+}
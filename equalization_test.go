@@ -0,0 +1,36 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetEqualizations(t *testing.T) {
+	equ2 := []byte("\x01bass\x00\x00\xc8\x06\x00\x4e\x20\xfd\x00")
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "EQU2", 0, equ2), make_frame(4, "EQU2", 0, []byte("\x00broken"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	eqs, err := id3tag.GetEqualizations()
+	want := []Equalization{{InterpolationLinear, "bass", []EqualizationPoint{{100, 3}, {10000, -1.5}}}}
+	if err != nil || !reflect.DeepEqual(eqs, want) {
+		t.Errorf("Unexpected equalisations %+v, %v", eqs, err)
+	}
+
+	equa := []byte("\x10\x80\x64\x00\x0c\x27\x10\x00\x06")
+	id3tag, err = ReadID3Bytes(make_tag(3, make_frame(3, "EQUA", 0, equa)))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	eqs, err = id3tag.GetEqualizations()
+	want = []Equalization{{InterpolationLinear, "", []EqualizationPoint{{100, 12}, {10000, -6}}}}
+	if err != nil || !reflect.DeepEqual(eqs, want) {
+		t.Errorf("Unexpected equalisations %+v, %v", eqs, err)
+	}
+
+	if _, err := (ID3Tag{}).GetEqualizations(); err == nil {
+		t.Errorf("Expected an error for a tag without equalisation")
+	}
+}
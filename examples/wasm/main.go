@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+// Command wasm exposes the tag parser to JavaScript, so that web pages can show the tags of audio files
+// before they are uploaded. It only uses the core parser, so build it with the id3v2core tag to keep the
+// file handling and HTTP parts of the package out of the binary:
+//
+//	GOOS=js GOARCH=wasm go build -tags id3v2core -o id3.wasm
+//
+// and load id3.wasm with the wasm_exec.js support script shipped in the Go distribution. Once running it
+// defines a global function id3ReadTag taking a Uint8Array with the start of the file, of which the tag
+// size given by its header is needed, and returning an object with the tag version, the values of its
+// text frames keyed by frame ID, its pictures less their data and any warnings, or an object with an
+// "error" field if the data does not start with a tag that can be read.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+func main() {
+	js.Global().Set("id3ReadTag", js.FuncOf(read_tag))
+	select {} //keep the functions available to JavaScript
+}
+
+func read_tag(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeObject {
+		return map[string]interface{}{"error": "id3ReadTag takes a Uint8Array"}
+	}
+	buf := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(buf, args[0])
+
+	id3tag, err := id3v2reader.ReadID3Bytes(buf)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	//js.ValueOf only takes maps and slices of interface{}, so the values are copied into those
+	text := make(map[string]interface{})
+	for frameid, values := range id3tag.AllText() {
+		text[frameid] = strings_to_js(values)
+	}
+	pictures := make([]interface{}, 0)
+	pics, _ := id3tag.GetPictures()
+	for _, pic := range pics {
+		pictures = append(pictures, map[string]interface{}{
+			"mime_type":   pic.MIMEType,
+			"type":        pic.Type.String(),
+			"description": pic.Description,
+			"size":        len(pic.Data),
+		})
+	}
+	return map[string]interface{}{
+		"version":  id3tag.Version.String(),
+		"text":     text,
+		"pictures": pictures,
+		"warnings": strings_to_js(id3tag.Warnings),
+	}
+}
+
+func strings_to_js(values []string) []interface{} {
+	ret := make([]interface{}, len(values))
+	for j, value := range values {
+		ret[j] = value
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fields flattens the tag into one string per key for storing in database rows. Text frames are keyed by
+// FrameID, TXXX and WXXX frames by "TXXX:" or "WXXX:" and their description, and COMM and USLT frames by
+// their FrameID, language and description joined with ":". URL frames hold their URL, pictures a summary
+// of their MIME type, picture type and size, and all other frames the number of bytes they hold. Values
+// of keys with several values, whether from one frame or several, are joined with separator.
+func (id3tag ID3Tag) Fields(separator string) map[string]string {
+	values := make(map[string][]string)
+	for key, texts := range id3tag.AllText() {
+		values[key] = texts
+	}
+	for _, frame := range id3tag.Frames {
+		if strings.HasPrefix(frame.FrameID, "T") && !(frame.Compression || frame.Encryption || frame.Unsynchronisation) {
+			continue
+		}
+		key, value := id3tag.summarize_frame(frame)
+		values[key] = append(values[key], value)
+	}
+
+	ret := make(map[string]string, len(values))
+	for key, vals := range values {
+		ret[key] = strings.Join(vals, separator)
+	}
+	return ret
+}
+
+// summarize_frame returns the Fields key and value of a frame other than a readable text frame
+func (id3tag ID3Tag) summarize_frame(frame ID3Frame) (string, string) {
+	data := frame.Data
+	summary := fmt.Sprintf("%v bytes", len(data))
+	if frame.Compression || frame.Encryption || frame.Unsynchronisation || len(data) == 0 {
+		return frame.FrameID, summary
+	}
+	switch {
+	case frame.FrameID == "WXXX":
+		desc, url := split_text(data[0], data[1:len(data)])
+		description, _ := id3tag.decodetext(data[0], desc)
+		return "WXXX:" + description, decodeISO88591(url)
+	case strings.HasPrefix(frame.FrameID, "W"):
+		return frame.FrameID, decodeISO88591(data)
+	case (frame.FrameID == "COMM" || frame.FrameID == "USLT") && len(data) > 4:
+		desc, text := split_text(data[0], data[4:len(data)])
+		description, _ := id3tag.decodetext(data[0], desc)
+		txt, _ := id3tag.decodetext(data[0], text)
+		return frame.FrameID + ":" + strings.TrimSpace(decodeISO88591(data[1:4])) + ":" + description, txt
+	case frame.FrameID == "APIC" || frame.FrameID == "PIC":
+		if pic, err := id3tag.parse_picture(data); err == nil {
+			return frame.FrameID, fmt.Sprintf("%v %v, %v bytes", pic.Type, pic.MIMEType, len(pic.Data))
+		}
+	}
+	return frame.FrameID, summary
+}
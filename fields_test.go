@@ -0,0 +1,39 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TPE1", 0, []byte("\x03One\x00Two")),
+		make_frame(4, "TXXX", 0, []byte("\x03Mood\x00Calm")),
+		make_frame(4, "COMM", 0, []byte("\x03eng\x00Nice")),
+		make_frame(4, "COMM", 0, []byte("\x03eng\x00Again")),
+		make_frame(4, "WOAR", 0, []byte("http://artist.example")),
+		make_frame(4, "APIC", 0, []byte("\x03image/png\x00\x03\x00\x89PNG")),
+		make_frame(4, "PCNT", 0, []byte{0, 0, 0, 7}),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	fields := id3tag.Fields("; ")
+	expected := map[string]string{
+		"TPE1":      "One; Two",
+		"TXXX:Mood": "Calm",
+		"COMM:eng:": "Nice; Again",
+		"WOAR":      "http://artist.example",
+		"APIC":      "Cover (front) image/png, 4 bytes",
+		"PCNT":      "4 bytes",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %v to be %q, got %q", key, value, fields[key])
+		}
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("Unexpected fields %q", fields)
+	}
+}
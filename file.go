@@ -0,0 +1,198 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile replaces the ID3v2 tag at the start of the file at path with id3tag, or adds id3tag if the file
+// has none. When the new tag fits in the space of the old one, padding is adjusted to fill it exactly and
+// only the tag region is overwritten, which leaves the audio untouched; WithPadding is then ignored.
+// Otherwise the file is rewritten to a temporary file in the same directory that is renamed over the
+// original once complete, so the file is never left half written. With WithBackup the tag the file had is
+// saved to a sidecar file before the file is changed.
+func WriteFile(path string, id3tag ID3Tag, opts ...WriteOption) error {
+	fil, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer fil.Close()
+
+	old_length, err := file_tag_length(fil)
+	if err != nil {
+		return err
+	}
+
+	cfg := new_write_config(opts)
+	cfg.padding = 0
+	buf, err := encode_tag(id3tag, cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.backup {
+		if err := backup_tag(path, fil, old_length); err != nil {
+			return err
+		}
+	}
+	if int64(len(buf)) <= old_length {
+		cfg.padding = old_length - int64(len(buf))
+		if buf, err = encode_tag(id3tag, cfg); err == nil {
+			if _, err = fil.WriteAt(buf, 0); err != nil {
+				return err
+			}
+			return fil.Sync()
+		}
+	}
+
+	if buf, err = encode_tag(id3tag, new_write_config(opts)); err != nil {
+		return err
+	}
+	return rewrite_file(path, fil, buf, old_length)
+}
+
+// file_tag_length returns the length of the ID3v2 tag at the start of fil, or 0 if there is none
+func file_tag_length(fil *os.File) (int64, error) {
+	header := make([]byte, 10)
+	n, err := fil.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if skip, ok := stream_tag_length(header[0:n]); ok {
+		return 10 + skip, nil
+	}
+	return 0, nil
+}
+
+// rewrite_file replaces the file at path with buf followed by the content of fil after the first skip bytes
+func rewrite_file(path string, fil *os.File, buf []byte, skip int64) error {
+	info, err := fil.Stat()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //fails harmlessly once the rename is done
+	defer tmp.Close()
+
+	if _, err := tmp.Write(buf); err != nil {
+		return err
+	}
+	if _, err := fil.Seek(skip, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, fil); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// CopyTag copies the tag of the file at src_path into the file at dst_path, replacing any tag dst_path
+// had, for instance after transcoding. The tag is copied byte for byte as RawTag returns it, including
+// frames this package does not know, unless it has frames flagged DiscardOnFileAlter: the standard
+// requires dropping those from a tag attached to different audio, so such tags are written without them
+// by WriteFile with opts. Of opts only WithBackup applies to tags copied byte for byte. Tags the parser
+// rejects or cannot read in full are copied byte for byte, since their frames cannot all be rewritten.
+func CopyTag(src_path, dst_path string, opts ...WriteOption) error {
+	fil, err := os.Open(src_path)
+	if err != nil {
+		return err
+	}
+	raw, err := RawTag(fil)
+	fil.Close()
+	if err != nil {
+		return err
+	}
+	id3tag, err := ReadID3Bytes(raw, WithExperimentalTags(true))
+	if err != nil || check_complete(id3tag) != nil || !has_file_discards(id3tag) {
+		return write_raw_tag(dst_path, raw, new_write_config(opts).backup)
+	}
+	return WriteFile(dst_path, id3tag, append([]WriteOption{FileAltered()}, opts...)...)
+}
+
+// has_file_discards reports whether any frame of id3tag is flagged DiscardOnFileAlter
+func has_file_discards(id3tag ID3Tag) bool {
+	for _, frame := range id3tag.Frames {
+		if frame.DiscardOnFileAlter {
+			return true
+		}
+	}
+	return false
+}
+
+// write_raw_tag replaces the ID3v2 tag at the start of the file at path with the encoded tag buf, or
+// removes it if buf is empty, first saving it to a sidecar file if backup is set
+func write_raw_tag(path string, buf []byte, backup bool) error {
+	fil, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer fil.Close()
+	old_length, err := file_tag_length(fil)
+	if err != nil {
+		return err
+	}
+	if backup {
+		if err := backup_tag(path, fil, old_length); err != nil {
+			return err
+		}
+	}
+	if int64(len(buf)) == old_length {
+		if _, err := fil.WriteAt(buf, 0); err != nil {
+			return err
+		}
+		return fil.Sync()
+	}
+	return rewrite_file(path, fil, buf, old_length)
+}
+
+// StripID3v1 removes the ID3v1 tag at the end of the file at path, along with the extended TAG+ block that
+// may precede it, and reports whether there was one.
+func StripID3v1(path string) (bool, error) {
+	fil, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return false, err
+	}
+	defer fil.Close()
+
+	end, err := fil.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	if end < 128 {
+		return false, nil
+	}
+	marker := make([]byte, 4)
+	if _, err := fil.ReadAt(marker[0:3], end-128); err != nil {
+		return false, err
+	}
+	if string(marker[0:3]) != "TAG" {
+		return false, nil
+	}
+	length := int64(128)
+	if end >= 128+227 {
+		if _, err := fil.ReadAt(marker, end-128-227); err != nil {
+			return false, err
+		}
+		if string(marker) == "TAG+" {
+			length += 227
+		}
+	}
+	if err := fil.Truncate(end - length); err != nil {
+		return false, err
+	}
+	return true, fil.Sync()
+}
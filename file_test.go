@@ -0,0 +1,113 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCopyTag(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mp3")
+	dst := filepath.Join(dir, "dst.mp3")
+
+	audiodep := make_frame(4, "MLLT", 0, []byte("lookup table"))
+	audiodep[8] = 0x20
+	tag := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "XYZW", 0, []byte("unknown frame")),
+		audiodep,
+	)
+	if err := os.WriteFile(src, append(tag, "source audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, append(make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00Old"))), "other audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CopyTag(src, dst); err != nil {
+		t.Fatalf("Error in copying tag: %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(data)
+	if err != nil {
+		t.Fatalf("Error in reading copied tag: %v", err)
+	}
+	if got := id3tag.Order(); !reflect.DeepEqual(got, []string{"TIT2", "XYZW"}) || id3tag.Version != Version24 {
+		t.Errorf("Unexpected copied tag v%v %v", id3tag.Version, got)
+	}
+	if !bytes.HasSuffix(data, []byte("other audio")) || !bytes.Equal(id3tag.Frames[1].Data, []byte("unknown frame")) {
+		t.Errorf("Unexpected content after copying %q", data)
+	}
+
+	if err := CopyTag(filepath.Join(dir, "missing.mp3"), dst); err == nil {
+		t.Errorf("Expected an error for a missing source")
+	}
+
+	//v2.2 frames have no flags, so there is nothing to drop and the tag is copied as it is
+	v22 := make_tag(2, make_frame(2, "TT2", 0, []byte("\x00Title")), make_frame(2, "XYZ", 0, []byte("unknown frame")))
+	if err := os.WriteFile(src, append(v22, "source audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyTag(src, dst, WithBackup()); err != nil {
+		t.Fatalf("Error in copying v2.2 tag: %v", err)
+	}
+	if data, err = os.ReadFile(dst); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, append(v22, "other audio"...)) {
+		t.Errorf("Unexpected content after copying a v2.2 tag %q", data)
+	}
+	if backup, err := os.ReadFile(dst + BackupSuffix); err != nil || !bytes.HasPrefix(backup, []byte("ID3\x04")) {
+		t.Errorf("Expected the replaced tag to be backed up, %v", err)
+	}
+
+	//the reader rejects unsynchronised tags, which are then copied as they are
+	unsync := make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00Title")))
+	unsync[5] |= 0x80
+	if err := os.WriteFile(src, append(unsync, "source audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyTag(src, dst); err != nil {
+		t.Fatalf("Error in copying an unsynchronised tag: %v", err)
+	}
+	if data, err = os.ReadFile(dst); err != nil || !bytes.Equal(data, append(unsync, "other audio"...)) {
+		t.Errorf("Unexpected content after copying an unsynchronised tag %q, %v", data, err)
+	}
+}
+
+func TestStripID3v1(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "v1.mp3")
+	audio := append(make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title"))), "audio"...)
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	extended := append([]byte("TAG+"), make([]byte, 223)...)
+
+	for _, c := range []struct {
+		name    string
+		trailer []byte
+		found   bool
+	}{
+		{"ID3v1", id3v1, true},
+		{"extended ID3v1", append(append([]byte{}, extended...), id3v1...), true},
+		{"no ID3v1", nil, false},
+	} {
+		if err := os.WriteFile(path, append(append([]byte{}, audio...), c.trailer...), 0644); err != nil {
+			t.Fatal(err)
+		}
+		found, err := StripID3v1(path)
+		if err != nil || found != c.found {
+			t.Errorf("%v: got %v, %v", c.name, found, err)
+		}
+		if data, _ := os.ReadFile(path); !bytes.Equal(data, audio) {
+			t.Errorf("%v: unexpected content %q", c.name, data)
+		}
+	}
+}
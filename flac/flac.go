@@ -0,0 +1,225 @@
+// Package flac reads the Vorbis comments and embedded pictures of FLAC files into the Metadata and Picture
+// types of the id3v2reader package, so libraries mixing .mp3 and .flac files can be handled through one
+// type. Importing the package registers the "flac" format with id3v2reader.ReadMetadata.
+package flac
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srinathh/id3v2reader"
+	"github.com/srinathh/id3v2reader/internal/dates"
+)
+
+// Info is what ReadInfo finds in the metadata blocks of a FLAC file. Comments maps the upper-cased field
+// names of the Vorbis comment block to their values in order.
+type Info struct {
+	Metadata id3v2reader.Metadata
+	Comments map[string][]string
+	Pictures []id3v2reader.Picture
+}
+
+func init() {
+	id3v2reader.RegisterFormat("flac", "fLaC", func(r io.ReadSeeker) (id3v2reader.Metadata, error) {
+		return ReadMetadata(r)
+	}, ".flac")
+}
+
+const (
+	block_streaminfo     = 0
+	block_vorbis_comment = 4
+	block_picture        = 6
+)
+
+// ReadMetadata reads the Metadata of the FLAC stream at the start of r
+func ReadMetadata(r io.Reader) (id3v2reader.Metadata, error) {
+	info, err := ReadInfo(r)
+	return info.Metadata, err
+}
+
+// ReadInfo reads the metadata blocks at the start of r, stopping before the audio. An ID3v2 tag in front
+// of the stream, which some taggers add though FLAC does not provide for it, is skipped.
+func ReadInfo(r io.Reader) (Info, error) {
+	info := Info{Comments: make(map[string][]string), Pictures: make([]id3v2reader.Picture, 0)}
+	marker := make([]byte, 4)
+	if _, err := io.ReadFull(r, marker); err != nil {
+		return info, errors.New("Not a FLAC file")
+	}
+	if string(marker[0:3]) == "ID3" {
+		if _, err := id3v2reader.RawTag(io.MultiReader(strings.NewReader(string(marker)), r)); err != nil {
+			return info, err
+		}
+		if _, err := io.ReadFull(r, marker); err != nil {
+			return info, errors.New("Not a FLAC file")
+		}
+	}
+	if string(marker) != "fLaC" {
+		return info, errors.New("Not a FLAC file")
+	}
+
+	header := make([]byte, 4)
+	for last := false; !last; {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return info, errors.New("FLAC metadata is truncated")
+		}
+		last = header[0]&0x80 != 0
+		kind := header[0] & 0x7F
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		if kind != block_streaminfo && kind != block_vorbis_comment && kind != block_picture {
+			if _, err := io.CopyN(io.Discard, r, length); err != nil {
+				return info, errors.New("FLAC metadata is truncated")
+			}
+			continue
+		}
+		block := make([]byte, length)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return info, errors.New("FLAC metadata is truncated")
+		}
+		switch kind {
+		case block_streaminfo:
+			info.Metadata.Duration = parse_streaminfo(block)
+		case block_vorbis_comment:
+			if err := parse_comments(block, info.Comments); err != nil {
+				return info, err
+			}
+		case block_picture:
+			if pic, err := parse_picture(block); err == nil {
+				info.Pictures = append(info.Pictures, pic)
+			}
+		}
+	}
+
+	first := func(field string) string {
+		if values := info.Comments[field]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	info.Metadata.Title = first("TITLE")
+	info.Metadata.Artist = first("ARTIST")
+	info.Metadata.Album = first("ALBUM")
+	info.Metadata.AlbumArtist = first("ALBUMARTIST")
+	info.Metadata.Composer = first("COMPOSER")
+	info.Metadata.Track = parse_number(first("TRACKNUMBER"))
+	info.Metadata.Disc = parse_number(first("DISCNUMBER"))
+	info.Metadata.Genres = info.Comments["GENRE"]
+	info.Metadata.RecordingDate, _ = dates.Parse(first("DATE"))
+	return info, nil
+}
+
+// parse_number returns the number of a TRACKNUMBER or DISCNUMBER field, which some taggers write with the
+// total as "3/12", or 0 if it holds none
+func parse_number(txt string) int {
+	if slash := strings.IndexByte(txt, '/'); slash >= 0 {
+		txt = txt[0:slash]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(txt))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parse_streaminfo returns the duration of the stream, or 0 if the total number of samples is unknown
+func parse_streaminfo(block []byte) time.Duration {
+	if len(block) < 18 {
+		return 0
+	}
+	//20 bits of sample rate, 3 of channels, 5 of bits per sample and 36 of total samples
+	bits := binary.BigEndian.Uint64(block[10:18])
+	rate := bits >> 44
+	samples := bits & (1<<36 - 1)
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(samples/rate)*time.Second + time.Duration(samples%rate*uint64(time.Second)/rate)
+}
+
+// parse_comments adds the fields of a Vorbis comment block, whose lengths are little endian, to comments
+func parse_comments(block []byte, comments map[string][]string) error {
+	malformed := errors.New("Malformed Vorbis comment block")
+	take := func() ([]byte, bool) {
+		if len(block) < 4 {
+			return nil, false
+		}
+		length := binary.LittleEndian.Uint32(block[0:4])
+		if uint64(len(block)-4) < uint64(length) {
+			return nil, false
+		}
+		field := block[4 : 4+length]
+		block = block[4+length : len(block)]
+		return field, true
+	}
+	if _, ok := take(); !ok { //vendor string
+		return malformed
+	}
+	if len(block) < 4 {
+		return malformed
+	}
+	count := binary.LittleEndian.Uint32(block[0:4])
+	block = block[4:len(block)]
+	for j := uint32(0); j < count; j++ {
+		field, ok := take()
+		if !ok {
+			return malformed
+		}
+		if eq := strings.IndexByte(string(field), '='); eq > 0 {
+			name := strings.ToUpper(string(field[0:eq]))
+			comments[name] = append(comments[name], string(field[eq+1:len(field)]))
+		}
+	}
+	return nil
+}
+
+// parse_picture parses a PICTURE block, whose fields are big endian and length prefixed
+func parse_picture(block []byte) (id3v2reader.Picture, error) {
+	var pic id3v2reader.Picture
+	malformed := errors.New("Malformed picture block")
+	u32 := func() (uint32, bool) {
+		if len(block) < 4 {
+			return 0, false
+		}
+		n := binary.BigEndian.Uint32(block[0:4])
+		block = block[4:len(block)]
+		return n, true
+	}
+	length_prefixed := func() ([]byte, bool) {
+		length, ok := u32()
+		if !ok || uint64(len(block)) < uint64(length) {
+			return nil, false
+		}
+		field := block[0:length]
+		block = block[length:len(block)]
+		return field, true
+	}
+
+	pictype, ok := u32()
+	if !ok {
+		return pic, malformed
+	}
+	pic.Type = id3v2reader.PictureType(pictype)
+	mime, ok := length_prefixed()
+	if !ok {
+		return pic, malformed
+	}
+	pic.MIMEType = string(mime)
+	desc, ok := length_prefixed()
+	if !ok {
+		return pic, malformed
+	}
+	pic.Description = string(desc)
+	//width, height, colour depth and number of colours
+	for j := 0; j < 4; j++ {
+		if _, ok := u32(); !ok {
+			return pic, malformed
+		}
+	}
+	if pic.Data, ok = length_prefixed(); !ok {
+		return pic, malformed
+	}
+	return pic, nil
+}
@@ -0,0 +1,116 @@
+package flac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+func make_block(kind byte, last bool, body []byte) []byte {
+	if last {
+		kind |= 0x80
+	}
+	return append([]byte{kind, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}, body...)
+}
+
+func le32(n int) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(n))
+	return buf
+}
+
+func be32(n int) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(n))
+	return buf
+}
+
+func TestReadInfo(t *testing.T) {
+	streaminfo := make([]byte, 34)
+	//44100 Hz, 2 channels, 16 bits, 441000*90 samples
+	binary.BigEndian.PutUint64(streaminfo[10:18], 44100<<44|1<<41|15<<36|441000*90)
+
+	comments := append(le32(6), "vendor"...)
+	comments = append(comments, le32(7)...)
+	for _, field := range []string{"TITLE=Title", "artist=Artist", "ALBUM=Album", "GENRE=Rock", "GENRE=Pop", "TRACKNUMBER=3/12", "DISCNUMBER=2"} {
+		comments = append(append(comments, le32(len(field))...), field...)
+	}
+
+	picture := bytes.Join([][]byte{be32(3), be32(9), []byte("image/png"), be32(5), []byte("Front"), make([]byte, 16), be32(4), []byte("\x89PNG")}, nil)
+
+	file := bytes.Join([][]byte{
+		[]byte("fLaC"),
+		make_block(block_streaminfo, false, streaminfo),
+		make_block(1, false, make([]byte, 100)),
+		make_block(block_vorbis_comment, false, comments),
+		make_block(block_picture, true, picture),
+		[]byte("audio frames"),
+	}, nil)
+
+	for _, data := range [][]byte{file, append([]byte("ID3\x04\x00\x00\x00\x00\x00\x05\x00\x00\x00\x00\x00"), file...)} {
+		info, err := ReadInfo(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Error in reading FLAC: %v", err)
+		}
+		md := info.Metadata
+		if md.Title != "Title" || md.Artist != "Artist" || md.Album != "Album" || md.Track != 3 || md.Disc != 2 || !reflect.DeepEqual(md.Genres, []string{"Rock", "Pop"}) || md.Duration != 900*time.Second {
+			t.Errorf("Unexpected metadata %+v", md)
+		}
+		want := []id3v2reader.Picture{{MIMEType: "image/png", Type: id3v2reader.FrontCover, Description: "Front", Data: []byte("\x89PNG")}}
+		if !reflect.DeepEqual(info.Pictures, want) {
+			t.Errorf("Expected %v, got %v", want, info.Pictures)
+		}
+	}
+
+	if md, format, err := id3v2reader.ReadMetadata(bytes.NewReader(file)); err != nil || format != "flac" || md.Title != "Title" {
+		t.Errorf("Unexpected result of the registered format %v %q, %v", format, md.Title, err)
+	}
+	if _, err := ReadMetadata(bytes.NewReader(file[0:50])); err == nil {
+		t.Errorf("Expected an error for truncated metadata")
+	}
+	if _, err := ReadMetadata(bytes.NewReader([]byte("RIFF...."))); err == nil {
+		t.Errorf("Expected an error for a file that is not FLAC")
+	}
+}
+
+func TestScan(t *testing.T) {
+	comments := append(le32(6), "vendor"...)
+	comments = append(append(append(comments, le32(1)...), le32(11)...), "TITLE=Title"...)
+	file := bytes.Join([][]byte{
+		[]byte("fLaC"),
+		make_block(block_streaminfo, false, make([]byte, 34)),
+		make_block(block_vorbis_comment, true, comments),
+	}, nil)
+	id3tag, err := id3v2reader.NewTagBuilder().Title("Tagged").Build(id3v2reader.Version24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tagged bytes.Buffer
+	if err := id3v2reader.WriteID3(&tagged, id3tag); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"music/a.FLAC":    {Data: file},
+		"music/b.mp3":     {Data: tagged.Bytes()},
+		"music/notes.txt": {Data: file},
+	}
+
+	results, err := id3v2reader.Scan(fsys, "music", 2)
+	if err != nil {
+		t.Fatalf("Error in scanning: %v", err)
+	}
+	if len(results) != 2 || results[0].Path != "music/a.FLAC" || results[1].Path != "music/b.mp3" {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+	if results[0].Metadata.Title != "Title" || results[0].Tag.Version != 0 {
+		t.Errorf("Unexpected result for the FLAC file %+v", results[0])
+	}
+	if results[1].Metadata.Title != "Tagged" || results[1].Tag.Version != id3v2reader.Version24 {
+		t.Errorf("Unexpected result for the MP3 file %+v", results[1])
+	}
+}
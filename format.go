@@ -0,0 +1,155 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"sync"
+)
+
+// A format is a file format ReadMetadata can read, recognised by the magic string at the start of files
+type format struct {
+	name  string
+	magic string
+	read  func(io.ReadSeeker) (Metadata, error)
+	exts  []string
+}
+
+var (
+	formats_mu sync.Mutex
+	formats    []format
+)
+
+// RegisterFormat makes a file format known to ReadMetadata. name is the format's name, which ReadMetadata
+// returns, and magic the bytes files of the format start with, where "?" matches any byte. read reads the
+// Metadata of a file positioned at its start, and exts the file name extensions of the format, such as
+// ".mp3", by which Scan picks files. Formats registered later are tried first. This package registers
+// "mp3" for files starting with an ID3v2 tag and the "wav", "aiff" and "dsf" containers, which hold ID3v2
+// tags in chunks; the mp4 and flac sub-packages register themselves when imported, as in
+//
+//	import _ "github.com/srinathh/id3v2reader/flac"
+func RegisterFormat(name, magic string, read func(io.ReadSeeker) (Metadata, error), exts ...string) {
+	formats_mu.Lock()
+	defer formats_mu.Unlock()
+	formats = append(formats, format{name, magic, read, exts})
+}
+
+func init() {
+	RegisterFormat("mp3", "ID3", read_id3_metadata, ".mp3")
+	RegisterFormat("wav", "RIFF????WAVE", read_riff_metadata, ".wav")
+	RegisterFormat("aiff", "FORM????AIFF", read_aiff_metadata, ".aiff", ".aif")
+	RegisterFormat("aiff", "FORM????AIFC", read_aiff_metadata, ".aifc")
+	RegisterFormat("dsf", "DSD ", read_dsf_metadata, ".dsf")
+}
+
+// HasFormatExtension reports whether the file name has, in any case, the extension of a format registered
+// with RegisterFormat
+func HasFormatExtension(name string) bool {
+	ext := path.Ext(name)
+	formats_mu.Lock()
+	defer formats_mu.Unlock()
+	for _, f := range formats {
+		for _, fext := range f.exts {
+			if strings.EqualFold(ext, fext) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMetadata recognises the format of the file r from its first bytes and reads its Metadata with the
+// reader registered for that format, returning the format's name as well
+func ReadMetadata(r io.ReadSeeker) (Metadata, string, error) {
+	formats_mu.Lock()
+	candidates := append([]format(nil), formats...)
+	formats_mu.Unlock()
+
+	head := make([]byte, 16)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return Metadata{}, "", err
+	}
+	head = head[0:n]
+	for j := len(candidates) - 1; j >= 0; j-- {
+		if !match_magic(candidates[j].magic, head) {
+			continue
+		}
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return Metadata{}, "", err
+		}
+		md, err := candidates[j].read(r)
+		return md, candidates[j].name, err
+	}
+	return Metadata{}, "", errors.New("Unknown file format")
+}
+
+func match_magic(magic string, head []byte) bool {
+	if len(head) < len(magic) {
+		return false
+	}
+	for j := 0; j < len(magic); j++ {
+		if magic[j] != '?' && magic[j] != head[j] {
+			return false
+		}
+	}
+	return true
+}
+
+func read_id3_metadata(r io.ReadSeeker) (Metadata, error) {
+	id3tag, err := ReadID3(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return id3tag.GetMetadata(), nil
+}
+
+// read_riff_metadata reads the tag in the "id3 " chunk of a RIFF file. Chunk sizes are little endian and
+// chunks are padded to an even length.
+func read_riff_metadata(r io.ReadSeeker) (Metadata, error) {
+	return read_chunked_metadata(r, 12, binary.LittleEndian, "id3 ", "ID3 ")
+}
+
+// read_aiff_metadata reads the tag in the "ID3 " chunk of an AIFF file, whose chunk sizes are big endian
+func read_aiff_metadata(r io.ReadSeeker) (Metadata, error) {
+	return read_chunked_metadata(r, 12, binary.BigEndian, "ID3 ", "id3 ")
+}
+
+func read_chunked_metadata(r io.ReadSeeker, pos int64, order binary.ByteOrder, ids ...string) (Metadata, error) {
+	header := make([]byte, 8)
+	for {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return Metadata{}, err
+		}
+		if _, err := io.ReadFull(r, header); err != nil {
+			return Metadata{}, errors.New("File has no ID3 chunk")
+		}
+		for _, id := range ids {
+			if string(header[0:4]) == id {
+				return read_id3_metadata(r)
+			}
+		}
+		size := int64(order.Uint32(header[4:8]))
+		pos += 8 + size + size%2
+	}
+}
+
+// read_dsf_metadata reads the tag a DSF file points to from its DSD chunk
+func read_dsf_metadata(r io.ReadSeeker) (Metadata, error) {
+	header := make([]byte, 28)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Metadata{}, errors.New("DSF header is truncated")
+	}
+	offset := binary.LittleEndian.Uint64(header[20:28])
+	if offset == 0 {
+		return Metadata{}, errors.New("DSF file has no metadata")
+	}
+	if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
+		return Metadata{}, err
+	}
+	return read_id3_metadata(r)
+}
@@ -0,0 +1,62 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestReadMetadata(t *testing.T) {
+	mp3, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00Chunked")))
+
+	riff := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x03\x00\x00\x00abc\x00id3 ")
+	riff = binary.LittleEndian.AppendUint32(riff, uint32(len(tag)))
+	riff = append(riff, tag...)
+
+	aiff := []byte("FORM\x00\x00\x00\x00AIFFCOMM\x00\x00\x00\x02abID3 ")
+	aiff = binary.BigEndian.AppendUint32(aiff, uint32(len(tag)))
+	aiff = append(aiff, tag...)
+
+	dsf := append([]byte("DSD \x1c\x00\x00\x00\x00\x00\x00\x00"), make([]byte, 8)...)
+	dsf = binary.LittleEndian.AppendUint64(dsf, 40)
+	dsf = append(append(dsf, "data....datadata"[0:12]...), tag...)
+
+	cases := []struct {
+		data   []byte
+		format string
+		title  string
+	}{
+		{mp3, "mp3", "Sine Wave at 440 Hz \u00df\u00c4\u00dc"},
+		{riff, "wav", "Chunked"},
+		{aiff, "aiff", "Chunked"},
+		{dsf, "dsf", "Chunked"},
+	}
+	for _, c := range cases {
+		md, format, err := ReadMetadata(bytes.NewReader(c.data))
+		if err != nil || format != c.format || md.Title != c.title {
+			t.Errorf("%v: got %v %q, %v", c.format, format, md.Title, err)
+		}
+	}
+
+	if _, _, err := ReadMetadata(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEdata\x04\x00\x00\x00abcd"))); err == nil {
+		t.Errorf("Expected an error for a WAV file without a tag")
+	}
+	if _, _, err := ReadMetadata(bytes.NewReader([]byte("unknown"))); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+
+	RegisterFormat("test", "unk?own", func(r io.ReadSeeker) (Metadata, error) {
+		return Metadata{Title: "registered"}, nil
+	})
+	if md, format, err := ReadMetadata(bytes.NewReader([]byte("unknown"))); err != nil || format != "test" || md.Title != "registered" {
+		t.Errorf("Registered format not used: %v %v, %v", format, md.Title, err)
+	}
+}
@@ -0,0 +1,381 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+// Frame IDs defined by the ID3v2.3 and ID3v2.4 standards. LookupFrame tells which of the two versions
+// allow each of them.
+const (
+	FrameAENC = "AENC"
+	FrameAPIC = "APIC"
+	FrameASPI = "ASPI"
+	FrameCOMM = "COMM"
+	FrameCOMR = "COMR"
+	FrameENCR = "ENCR"
+	FrameEQU2 = "EQU2"
+	FrameEQUA = "EQUA"
+	FrameETCO = "ETCO"
+	FrameGEOB = "GEOB"
+	FrameGRID = "GRID"
+	FrameIPLS = "IPLS"
+	FrameLINK = "LINK"
+	FrameMCDI = "MCDI"
+	FrameMLLT = "MLLT"
+	FrameOWNE = "OWNE"
+	FramePCNT = "PCNT"
+	FramePOPM = "POPM"
+	FramePOSS = "POSS"
+	FramePRIV = "PRIV"
+	FrameRBUF = "RBUF"
+	FrameRVA2 = "RVA2"
+	FrameRVAD = "RVAD"
+	FrameRVRB = "RVRB"
+	FrameSEEK = "SEEK"
+	FrameSIGN = "SIGN"
+	FrameSYLT = "SYLT"
+	FrameSYTC = "SYTC"
+	FrameTALB = "TALB"
+	FrameTBPM = "TBPM"
+	FrameTCOM = "TCOM"
+	FrameTCON = "TCON"
+	FrameTCOP = "TCOP"
+	FrameTDAT = "TDAT"
+	FrameTDEN = "TDEN"
+	FrameTDLY = "TDLY"
+	FrameTDOR = "TDOR"
+	FrameTDRC = "TDRC"
+	FrameTDRL = "TDRL"
+	FrameTDTG = "TDTG"
+	FrameTENC = "TENC"
+	FrameTEXT = "TEXT"
+	FrameTFLT = "TFLT"
+	FrameTIME = "TIME"
+	FrameTIPL = "TIPL"
+	FrameTIT1 = "TIT1"
+	FrameTIT2 = "TIT2"
+	FrameTIT3 = "TIT3"
+	FrameTKEY = "TKEY"
+	FrameTLAN = "TLAN"
+	FrameTLEN = "TLEN"
+	FrameTMCL = "TMCL"
+	FrameTMED = "TMED"
+	FrameTMOO = "TMOO"
+	FrameTOAL = "TOAL"
+	FrameTOFN = "TOFN"
+	FrameTOLY = "TOLY"
+	FrameTOPE = "TOPE"
+	FrameTORY = "TORY"
+	FrameTOWN = "TOWN"
+	FrameTPE1 = "TPE1"
+	FrameTPE2 = "TPE2"
+	FrameTPE3 = "TPE3"
+	FrameTPE4 = "TPE4"
+	FrameTPOS = "TPOS"
+	FrameTPRO = "TPRO"
+	FrameTPUB = "TPUB"
+	FrameTRCK = "TRCK"
+	FrameTRDA = "TRDA"
+	FrameTRSN = "TRSN"
+	FrameTRSO = "TRSO"
+	FrameTSIZ = "TSIZ"
+	FrameTSOA = "TSOA"
+	FrameTSOP = "TSOP"
+	FrameTSOT = "TSOT"
+	FrameTSRC = "TSRC"
+	FrameTSSE = "TSSE"
+	FrameTSST = "TSST"
+	FrameTXXX = "TXXX"
+	FrameTYER = "TYER"
+	FrameUFID = "UFID"
+	FrameUSER = "USER"
+	FrameUSLT = "USLT"
+	FrameWCOM = "WCOM"
+	FrameWCOP = "WCOP"
+	FrameWOAF = "WOAF"
+	FrameWOAR = "WOAR"
+	FrameWOAS = "WOAS"
+	FrameWORS = "WORS"
+	FrameWPAY = "WPAY"
+	FrameWPUB = "WPUB"
+	FrameWXXX = "WXXX"
+)
+
+// Frame IDs defined by the ID3v2.2 standard, which uses three character IDs
+const (
+	FrameBUF = "BUF"
+	FrameCNT = "CNT"
+	FrameCOM = "COM"
+	FrameCRA = "CRA"
+	FrameCRM = "CRM"
+	FrameEQU = "EQU"
+	FrameETC = "ETC"
+	FrameGEO = "GEO"
+	FrameIPL = "IPL"
+	FrameLNK = "LNK"
+	FrameMCI = "MCI"
+	FrameMLL = "MLL"
+	FramePIC = "PIC"
+	FramePOP = "POP"
+	FrameREV = "REV"
+	FrameRVA = "RVA"
+	FrameSLT = "SLT"
+	FrameSTC = "STC"
+	FrameTAL = "TAL"
+	FrameTBP = "TBP"
+	FrameTCM = "TCM"
+	FrameTCO = "TCO"
+	FrameTCR = "TCR"
+	FrameTDA = "TDA"
+	FrameTDY = "TDY"
+	FrameTEN = "TEN"
+	FrameTFT = "TFT"
+	FrameTIM = "TIM"
+	FrameTKE = "TKE"
+	FrameTLA = "TLA"
+	FrameTLE = "TLE"
+	FrameTMT = "TMT"
+	FrameTOA = "TOA"
+	FrameTOF = "TOF"
+	FrameTOL = "TOL"
+	FrameTOR = "TOR"
+	FrameTOT = "TOT"
+	FrameTP1 = "TP1"
+	FrameTP2 = "TP2"
+	FrameTP3 = "TP3"
+	FrameTP4 = "TP4"
+	FrameTPA = "TPA"
+	FrameTPB = "TPB"
+	FrameTRC = "TRC"
+	FrameTRD = "TRD"
+	FrameTRK = "TRK"
+	FrameTSI = "TSI"
+	FrameTSS = "TSS"
+	FrameTT1 = "TT1"
+	FrameTT2 = "TT2"
+	FrameTT3 = "TT3"
+	FrameTXT = "TXT"
+	FrameTXX = "TXX"
+	FrameTYE = "TYE"
+	FrameUFI = "UFI"
+	FrameULT = "ULT"
+	FrameWAF = "WAF"
+	FrameWAR = "WAR"
+	FrameWAS = "WAS"
+	FrameWCM = "WCM"
+	FrameWCP = "WCP"
+	FrameWPB = "WPB"
+	FrameWXX = "WXX"
+)
+
+// ValueType is the kind of value a frame holds
+type ValueType int
+
+const (
+	BinaryValue        ValueType = iota //a frame specific binary structure
+	TextValue                           //text in one of the ID3 text encodings
+	URLValue                            //an ISO-8859-1 URL
+	UserTextValue                       //a description followed by text, as in TXXX
+	UserURLValue                        //a description followed by a URL, as in WXXX
+	LocalizedTextValue                  //a language, a description and text, as in COMM
+	PictureValue                        //an attached picture, as in APIC
+)
+
+// FrameInfo describes a standard frame ID. Repeatable frames may appear more than once in a tag, though
+// usually only with distinct descriptions, languages or owners.
+type FrameInfo struct {
+	ID         string
+	Name       string
+	Type       ValueType
+	Repeatable bool
+	versions   byte
+}
+
+const (
+	for_v22 byte = 1 << iota
+	for_v23
+	for_v24
+)
+
+// AllowedIn reports whether the standard of the given version defines the frame
+func (info FrameInfo) AllowedIn(ver Version) bool {
+	switch ver {
+	case Version22:
+		return info.versions&for_v22 != 0
+	case Version23:
+		return info.versions&for_v23 != 0
+	case Version24:
+		return info.versions&for_v24 != 0
+	}
+	return false
+}
+
+// LookupFrame returns what is known about the frame ID frameid, and false if it is not a standard frame ID
+func LookupFrame(frameid string) (FrameInfo, bool) {
+	info, ok := frame_infos[frameid]
+	return info, ok
+}
+
+var frame_infos = make_frame_infos([]FrameInfo{
+	{FrameAENC, "Audio encryption", BinaryValue, true, for_v23 | for_v24},
+	{FrameAPIC, "Attached picture", PictureValue, true, for_v23 | for_v24},
+	{FrameASPI, "Audio seek point index", BinaryValue, false, for_v24},
+	{FrameCOMM, "Comments", LocalizedTextValue, true, for_v23 | for_v24},
+	{FrameCOMR, "Commercial frame", BinaryValue, true, for_v23 | for_v24},
+	{FrameENCR, "Encryption method registration", BinaryValue, true, for_v23 | for_v24},
+	{FrameEQUA, "Equalization", BinaryValue, false, for_v23},
+	{FrameEQU2, "Equalisation (2)", BinaryValue, true, for_v24},
+	{FrameETCO, "Event timing codes", BinaryValue, false, for_v23 | for_v24},
+	{FrameGEOB, "General encapsulated object", BinaryValue, true, for_v23 | for_v24},
+	{FrameGRID, "Group identification registration", BinaryValue, true, for_v23 | for_v24},
+	{FrameIPLS, "Involved people list", TextValue, false, for_v23},
+	{FrameLINK, "Linked information", BinaryValue, true, for_v23 | for_v24},
+	{FrameMCDI, "Music CD identifier", BinaryValue, false, for_v23 | for_v24},
+	{FrameMLLT, "MPEG location lookup table", BinaryValue, false, for_v23 | for_v24},
+	{FrameOWNE, "Ownership frame", BinaryValue, false, for_v23 | for_v24},
+	{FramePRIV, "Private frame", BinaryValue, true, for_v23 | for_v24},
+	{FramePCNT, "Play counter", BinaryValue, false, for_v23 | for_v24},
+	{FramePOPM, "Popularimeter", BinaryValue, true, for_v23 | for_v24},
+	{FramePOSS, "Position synchronisation frame", BinaryValue, false, for_v23 | for_v24},
+	{FrameRBUF, "Recommended buffer size", BinaryValue, false, for_v23 | for_v24},
+	{FrameRVAD, "Relative volume adjustment", BinaryValue, false, for_v23},
+	{FrameRVA2, "Relative volume adjustment (2)", BinaryValue, true, for_v24},
+	{FrameRVRB, "Reverb", BinaryValue, false, for_v23 | for_v24},
+	{FrameSEEK, "Seek frame", BinaryValue, false, for_v24},
+	{FrameSIGN, "Signature frame", BinaryValue, true, for_v24},
+	{FrameSYLT, "Synchronised lyric/text", BinaryValue, true, for_v23 | for_v24},
+	{FrameSYTC, "Synchronised tempo codes", BinaryValue, false, for_v23 | for_v24},
+	{FrameTALB, "Album/Movie/Show title", TextValue, false, for_v23 | for_v24},
+	{FrameTBPM, "BPM (beats per minute)", TextValue, false, for_v23 | for_v24},
+	{FrameTCOM, "Composer", TextValue, false, for_v23 | for_v24},
+	{FrameTCON, "Content type", TextValue, false, for_v23 | for_v24},
+	{FrameTCOP, "Copyright message", TextValue, false, for_v23 | for_v24},
+	{FrameTDAT, "Date", TextValue, false, for_v23},
+	{FrameTDEN, "Encoding time", TextValue, false, for_v24},
+	{FrameTDLY, "Playlist delay", TextValue, false, for_v23 | for_v24},
+	{FrameTDOR, "Original release time", TextValue, false, for_v24},
+	{FrameTDRC, "Recording time", TextValue, false, for_v24},
+	{FrameTDRL, "Release time", TextValue, false, for_v24},
+	{FrameTDTG, "Tagging time", TextValue, false, for_v24},
+	{FrameTENC, "Encoded by", TextValue, false, for_v23 | for_v24},
+	{FrameTEXT, "Lyricist/Text writer", TextValue, false, for_v23 | for_v24},
+	{FrameTFLT, "File type", TextValue, false, for_v23 | for_v24},
+	{FrameTIME, "Time", TextValue, false, for_v23},
+	{FrameTIPL, "Involved people list", TextValue, false, for_v24},
+	{FrameTIT1, "Content group description", TextValue, false, for_v23 | for_v24},
+	{FrameTIT2, "Title/Songname/Content description", TextValue, false, for_v23 | for_v24},
+	{FrameTIT3, "Subtitle/Description refinement", TextValue, false, for_v23 | for_v24},
+	{FrameTKEY, "Initial key", TextValue, false, for_v23 | for_v24},
+	{FrameTLAN, "Language(s)", TextValue, false, for_v23 | for_v24},
+	{FrameTLEN, "Length", TextValue, false, for_v23 | for_v24},
+	{FrameTMCL, "Musician credits list", TextValue, false, for_v24},
+	{FrameTMED, "Media type", TextValue, false, for_v23 | for_v24},
+	{FrameTMOO, "Mood", TextValue, false, for_v24},
+	{FrameTOAL, "Original album/movie/show title", TextValue, false, for_v23 | for_v24},
+	{FrameTOFN, "Original filename", TextValue, false, for_v23 | for_v24},
+	{FrameTOLY, "Original lyricist(s)/text writer(s)", TextValue, false, for_v23 | for_v24},
+	{FrameTOPE, "Original artist(s)/performer(s)", TextValue, false, for_v23 | for_v24},
+	{FrameTORY, "Original release year", TextValue, false, for_v23},
+	{FrameTOWN, "File owner/licensee", TextValue, false, for_v23 | for_v24},
+	{FrameTPE1, "Lead performer(s)/Soloist(s)", TextValue, false, for_v23 | for_v24},
+	{FrameTPE2, "Band/orchestra/accompaniment", TextValue, false, for_v23 | for_v24},
+	{FrameTPE3, "Conductor/performer refinement", TextValue, false, for_v23 | for_v24},
+	{FrameTPE4, "Interpreted, remixed, or otherwise modified by", TextValue, false, for_v23 | for_v24},
+	{FrameTPOS, "Part of a set", TextValue, false, for_v23 | for_v24},
+	{FrameTPRO, "Produced notice", TextValue, false, for_v24},
+	{FrameTPUB, "Publisher", TextValue, false, for_v23 | for_v24},
+	{FrameTRCK, "Track number/Position in set", TextValue, false, for_v23 | for_v24},
+	{FrameTRDA, "Recording dates", TextValue, false, for_v23},
+	{FrameTRSN, "Internet radio station name", TextValue, false, for_v23 | for_v24},
+	{FrameTRSO, "Internet radio station owner", TextValue, false, for_v23 | for_v24},
+	{FrameTSIZ, "Size", TextValue, false, for_v23},
+	{FrameTSOA, "Album sort order", TextValue, false, for_v24},
+	{FrameTSOP, "Performer sort order", TextValue, false, for_v24},
+	{FrameTSOT, "Title sort order", TextValue, false, for_v24},
+	{FrameTSRC, "ISRC (international standard recording code)", TextValue, false, for_v23 | for_v24},
+	{FrameTSSE, "Software/Hardware and settings used for encoding", TextValue, false, for_v23 | for_v24},
+	{FrameTSST, "Set subtitle", TextValue, false, for_v24},
+	{FrameTYER, "Year", TextValue, false, for_v23},
+	{FrameTXXX, "User defined text information frame", UserTextValue, true, for_v23 | for_v24},
+	{FrameUFID, "Unique file identifier", BinaryValue, true, for_v23 | for_v24},
+	{FrameUSER, "Terms of use", BinaryValue, true, for_v23 | for_v24},
+	{FrameUSLT, "Unsynchronised lyric/text transcription", LocalizedTextValue, true, for_v23 | for_v24},
+	{FrameWCOM, "Commercial information", URLValue, true, for_v23 | for_v24},
+	{FrameWCOP, "Copyright/Legal information", URLValue, false, for_v23 | for_v24},
+	{FrameWOAF, "Official audio file webpage", URLValue, false, for_v23 | for_v24},
+	{FrameWOAR, "Official artist/performer webpage", URLValue, true, for_v23 | for_v24},
+	{FrameWOAS, "Official audio source webpage", URLValue, false, for_v23 | for_v24},
+	{FrameWORS, "Official Internet radio station homepage", URLValue, false, for_v23 | for_v24},
+	{FrameWPAY, "Payment", URLValue, false, for_v23 | for_v24},
+	{FrameWPUB, "Publishers official webpage", URLValue, false, for_v23 | for_v24},
+	{FrameWXXX, "User defined URL link frame", UserURLValue, true, for_v23 | for_v24},
+	{FrameBUF, "Recommended buffer size", BinaryValue, false, for_v22},
+	{FrameCNT, "Play counter", BinaryValue, false, for_v22},
+	{FrameCOM, "Comments", LocalizedTextValue, true, for_v22},
+	{FrameCRA, "Audio encryption", BinaryValue, true, for_v22},
+	{FrameCRM, "Encrypted meta frame", BinaryValue, true, for_v22},
+	{FrameETC, "Event timing codes", BinaryValue, false, for_v22},
+	{FrameEQU, "Equalization", BinaryValue, false, for_v22},
+	{FrameGEO, "General encapsulated object", BinaryValue, true, for_v22},
+	{FrameIPL, "Involved people list", TextValue, false, for_v22},
+	{FrameLNK, "Linked information", BinaryValue, true, for_v22},
+	{FrameMCI, "Music CD identifier", BinaryValue, false, for_v22},
+	{FrameMLL, "MPEG location lookup table", BinaryValue, false, for_v22},
+	{FramePIC, "Attached picture", PictureValue, true, for_v22},
+	{FramePOP, "Popularimeter", BinaryValue, true, for_v22},
+	{FrameREV, "Reverb", BinaryValue, false, for_v22},
+	{FrameRVA, "Relative volume adjustment", BinaryValue, false, for_v22},
+	{FrameSLT, "Synchronized lyric/text", BinaryValue, true, for_v22},
+	{FrameSTC, "Synced tempo codes", BinaryValue, false, for_v22},
+	{FrameTAL, "Album/Movie/Show title", TextValue, false, for_v22},
+	{FrameTBP, "BPM (beats per minute)", TextValue, false, for_v22},
+	{FrameTCM, "Composer", TextValue, false, for_v22},
+	{FrameTCO, "Content type", TextValue, false, for_v22},
+	{FrameTCR, "Copyright message", TextValue, false, for_v22},
+	{FrameTDA, "Date", TextValue, false, for_v22},
+	{FrameTDY, "Playlist delay", TextValue, false, for_v22},
+	{FrameTEN, "Encoded by", TextValue, false, for_v22},
+	{FrameTFT, "File type", TextValue, false, for_v22},
+	{FrameTIM, "Time", TextValue, false, for_v22},
+	{FrameTKE, "Initial key", TextValue, false, for_v22},
+	{FrameTLA, "Language(s)", TextValue, false, for_v22},
+	{FrameTLE, "Length", TextValue, false, for_v22},
+	{FrameTMT, "Media type", TextValue, false, for_v22},
+	{FrameTOA, "Original artist(s)/performer(s)", TextValue, false, for_v22},
+	{FrameTOF, "Original filename", TextValue, false, for_v22},
+	{FrameTOL, "Original lyricist(s)/text writer(s)", TextValue, false, for_v22},
+	{FrameTOR, "Original release year", TextValue, false, for_v22},
+	{FrameTOT, "Original album/Movie/Show title", TextValue, false, for_v22},
+	{FrameTP1, "Lead artist(s)/Lead performer(s)/Soloist(s)/Performing group", TextValue, false, for_v22},
+	{FrameTP2, "Band/Orchestra/Accompaniment", TextValue, false, for_v22},
+	{FrameTP3, "Conductor/Performer refinement", TextValue, false, for_v22},
+	{FrameTP4, "Interpreted, remixed, or otherwise modified by", TextValue, false, for_v22},
+	{FrameTPA, "Part of a set", TextValue, false, for_v22},
+	{FrameTPB, "Publisher", TextValue, false, for_v22},
+	{FrameTRC, "ISRC (international standard recording code)", TextValue, false, for_v22},
+	{FrameTRD, "Recording dates", TextValue, false, for_v22},
+	{FrameTRK, "Track number/Position in set", TextValue, false, for_v22},
+	{FrameTSI, "Size", TextValue, false, for_v22},
+	{FrameTSS, "Software/hardware and settings used for encoding", TextValue, false, for_v22},
+	{FrameTT1, "Content group description", TextValue, false, for_v22},
+	{FrameTT2, "Title/Songname/Content description", TextValue, false, for_v22},
+	{FrameTT3, "Subtitle/Description refinement", TextValue, false, for_v22},
+	{FrameTXT, "Lyricist/text writer", TextValue, false, for_v22},
+	{FrameTXX, "User defined text information frame", UserTextValue, true, for_v22},
+	{FrameTYE, "Year", TextValue, false, for_v22},
+	{FrameUFI, "Unique file identifier", BinaryValue, true, for_v22},
+	{FrameULT, "Unsynchronized lyric/text transcription", LocalizedTextValue, true, for_v22},
+	{FrameWAF, "Official audio file webpage", URLValue, false, for_v22},
+	{FrameWAR, "Official artist/performer webpage", URLValue, true, for_v22},
+	{FrameWAS, "Official audio source webpage", URLValue, false, for_v22},
+	{FrameWCM, "Commercial information", URLValue, true, for_v22},
+	{FrameWCP, "Copyright/Legal information", URLValue, false, for_v22},
+	{FrameWPB, "Publishers official webpage", URLValue, false, for_v22},
+	{FrameWXX, "User defined URL link frame", UserURLValue, true, for_v22},
+})
+
+func make_frame_infos(infos []FrameInfo) map[string]FrameInfo {
+	ret := make(map[string]FrameInfo, len(infos))
+	for _, info := range infos {
+		ret[info.ID] = info
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLookupFrame(t *testing.T) {
+	info, ok := LookupFrame(FrameTDRC)
+	if !ok || info.Name != "Recording time" || info.Type != TextValue || info.Repeatable {
+		t.Errorf("Unexpected info for TDRC: %v %v", info, ok)
+	}
+	if info.AllowedIn(Version23) || !info.AllowedIn(Version24) || info.AllowedIn(Version22) {
+		t.Errorf("TDRC should be allowed only in v2.4")
+	}
+
+	if info, ok := LookupFrame(FramePIC); !ok || info.Type != PictureValue || !info.AllowedIn(Version22) || info.AllowedIn(Version23) {
+		t.Errorf("Unexpected info for PIC: %v %v", info, ok)
+	}
+	if info, _ := LookupFrame(FrameAPIC); !info.Repeatable || !info.AllowedIn(Version23) || !info.AllowedIn(Version24) {
+		t.Errorf("Unexpected info for APIC: %v", info)
+	}
+	if _, ok := LookupFrame("ABCD"); ok {
+		t.Errorf("Non standard frame ID found")
+	}
+
+	//every frame in the test files is a standard frame of the file's version
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		fil, err := os.Open(filname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id3tag, err := ReadID3(fil)
+		fil.Close()
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		for _, frame := range id3tag.Frames {
+			if info, ok := LookupFrame(frame.FrameID); !ok || !info.AllowedIn(id3tag.Version) {
+				t.Errorf("%s: frame %s not known for version %v", filname, frame.FrameID, id3tag.Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// FuzzReadID3 feeds arbitrary bytes through the parser and every accessor. Seeds live in
+// testdata/fuzz/FuzzReadID3 alongside the sample files; run with go test -fuzz=FuzzReadID3
+func FuzzReadID3(f *testing.F) {
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		data, err := os.ReadFile(filname)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		id3tag, err := ReadID3(bytes.NewReader(data))
+		bytes_tag, bytes_err := ReadID3Bytes(data)
+		if (err == nil) != (bytes_err == nil) {
+			t.Fatalf("ReadID3 and ReadID3Bytes disagree: %v, %v", err, bytes_err)
+		}
+		if err != nil {
+			return
+		}
+		if len(id3tag.Frames) != len(bytes_tag.Frames) {
+			t.Fatalf("ReadID3 read %v frames, ReadID3Bytes read %v", len(id3tag.Frames), len(bytes_tag.Frames))
+		}
+		for _, id3frame := range id3tag.Frames {
+			id3tag.GetTagData(id3frame.FrameID)
+			id3tag.GetTextFrameData(id3frame.FrameID)
+		}
+		id3tag.GetTitle()
+		id3tag.GetAlbum()
+		id3tag.GetArtist()
+		id3tag.GetComposer()
+		id3tag.GetCoverPic()
+		id3tag.GetPictures()
+		id3tag.Fields("/")
+		id3tag.GetRating(WindowsMediaPlayerRating)
+		id3tag.GetGenres()
+		id3tag.GetChapters()
+		id3tag.GetComments()
+		id3tag.GetSyncedLyrics()
+		id3tag.GetMetadata()
+		id3tag.Fingerprint()
+		if buf, err := id3tag.MarshalBinary(); err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		} else if err := new(ID3Tag).UnmarshalBinary(buf); err != nil {
+			t.Fatalf("UnmarshalBinary of marshalled tag failed: %v", err)
+		}
+	})
+}
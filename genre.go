@@ -0,0 +1,134 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strconv"
+	"strings"
+)
+
+// genres is the ID3v1 genre table as extended by Winamp, indexed by genre number
+var genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop",
+	"Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B", "Rap",
+	"Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska", "Death Metal", "Pranks",
+	"Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance",
+	"Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"AlternRock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock",
+	"Ethnic", "Gothic", "Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream",
+	"Southern Rock", "Comedy", "Cult", "Gangsta", "Top 40", "Christian Rap", "Pop/Funk", "Jungle",
+	"Native American", "Cabaret", "New Wave", "Psychadelic", "Rave", "Showtunes", "Trailer", "Lo-Fi",
+	"Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+	"Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion", "Bebob", "Latin", "Revival",
+	"Celtic", "Bluegrass", "Avantgarde", "Gothic Rock", "Progressive Rock", "Psychedelic Rock", "Symphonic Rock", "Slow Rock",
+	"Big Band", "Chorus", "Easy Listening", "Acoustic", "Humour", "Speech", "Chanson", "Opera",
+	"Chamber Music", "Sonata", "Symphony", "Booty Bass", "Primus", "Porn Groove", "Satire", "Slow Jam",
+	"Club", "Tango", "Samba", "Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle",
+	"Duet", "Punk Rock", "Drum Solo", "A capella", "Euro-House", "Dance Hall", "Goa", "Drum & Bass",
+	"Club-House", "Hardcore Techno", "Terror", "Indie", "BritPop", "Negerpunk", "Polsk Punk", "Beat",
+	"Christian Gangsta Rap", "Heavy Metal", "Black Metal", "Crossover", "Contemporary Christian", "Christian Rock", "Merengue", "Salsa",
+	"Thrash Metal", "Anime", "Jpop", "Synthpop", "Abstract", "Art Rock", "Baroque", "Bhangra",
+	"Big Beat", "Breakbeat", "Chillout", "Downtempo", "Dub", "EBM", "Eclectic", "Electro",
+	"Electroclash", "Emo", "Experimental", "Garage", "Global", "IDM", "Illbient", "Industro-Goth",
+	"Jam Band", "Krautrock", "Leftfield", "Lounge", "Math Rock", "New Romantic", "Nu-Breakz", "Post-Punk",
+	"Post-Rock", "Psytrance", "Shoegaze", "Space Rock", "Trop Rock", "World Music", "Neoclassical", "Audiobook",
+	"Audio Theatre", "Neue Deutsche Welle", "Podcast", "Indie Rock", "G-Funk", "Dubstep", "Garage Rock", "Psybient",
+}
+
+// GenreName returns the name of the ID3v1 genre number index and false if there is no such genre
+func GenreName(index int) (string, bool) {
+	if index < 0 || index >= len(genres) {
+		return "", false
+	}
+	return genres[index], true
+}
+
+// GenreIndex returns the ID3v1 genre number of a genre name, ignoring case, and false if the name is not
+// in the ID3v1 genre table
+func GenreIndex(name string) (int, bool) {
+	for j, genre := range genres {
+		if strings.EqualFold(genre, name) {
+			return j, true
+		}
+	}
+	return -1, false
+}
+
+// GetGenres returns the genres in the TCON frame. ID3v1 genre numbers, either bare as in v2.4 or in
+// parentheses as in v2.3, are replaced with their names, as are the RX (remix) and CR (cover) references.
+// A name following a v2.3 reference refines it and is returned after it unless it repeats it.
+func (id3tag ID3Tag) GetGenres() ([]string, error) {
+	values, err := id3tag.get_text_values("TCON")
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0, len(values))
+	for _, value := range values {
+		ret = append(ret, decode_genre(value)...)
+	}
+	return ret, nil
+}
+
+func decode_genre(value string) []string {
+	if index, err := strconv.Atoi(value); err == nil {
+		if name, ok := GenreName(index); ok {
+			return []string{name}
+		}
+		return []string{value}
+	}
+	ret := make([]string, 0, 1)
+	for strings.HasPrefix(value, "(") && !strings.HasPrefix(value, "((") {
+		end := strings.IndexByte(value, ')')
+		if end == -1 {
+			break
+		}
+		ref := value[1:end]
+		switch ref {
+		case "RX":
+			ret = append(ret, "Remix")
+		case "CR":
+			ret = append(ret, "Cover")
+		default:
+			index, err := strconv.Atoi(ref)
+			name, ok := GenreName(index)
+			if err != nil || !ok {
+				return append(ret, value)
+			}
+			ret = append(ret, name)
+		}
+		value = value[end+1:]
+	}
+	//a leading (( escapes a parenthesis that starts a name
+	value = strings.TrimPrefix(value, "(")
+	if value != "" && (len(ret) == 0 || !strings.EqualFold(ret[len(ret)-1], value)) {
+		ret = append(ret, value)
+	}
+	return ret
+}
+
+// SetGenres returns a copy of the tag whose TCON frame holds genres. v2.4 tags get the names as plain null
+// separated values. v2.2 and v2.3 have no multiple values, so their tags get a reference such as "(17)"
+// for each genre of the ID3v1 genre table, as in "(17)(20)", followed by the names of the other genres,
+// joined with "/" as encodetext joins values, as in "(17)Custom". A single genre from the table is written
+// as "(17)Rock" for legacy players that show the text. Passing no genres removes the frame.
+func (id3tag ID3Tag) SetGenres(genres []string) ID3Tag {
+	if id3tag.Version != Version22 && id3tag.Version != Version23 || len(genres) == 0 {
+		return id3tag.SetText("TCON", genres...)
+	}
+	refs, names := "", make([]string, 0)
+	for _, genre := range genres {
+		if index, ok := GenreIndex(genre); ok {
+			refs += "(" + strconv.Itoa(index) + ")"
+		} else {
+			names = append(names, genre)
+		}
+	}
+	refinement := strings.Join(names, "/")
+	if len(genres) == 1 && refs != "" {
+		refinement = genres[0]
+	} else if strings.HasPrefix(refinement, "(") {
+		//a leading (( escapes a parenthesis that starts a name
+		refinement = "(" + refinement
+	}
+	return id3tag.SetText("TCON", refs+refinement)
+}
@@ -0,0 +1,100 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestGenreTable(t *testing.T) {
+	if len(genres) != 192 {
+		t.Errorf("Expected 192 genres, got %d", len(genres))
+	}
+	if name, ok := GenreName(17); !ok || name != "Rock" {
+		t.Errorf("Expected Rock for genre 17, got %q", name)
+	}
+	if name, ok := GenreName(191); !ok || name != "Psybient" {
+		t.Errorf("Expected Psybient for genre 191, got %q", name)
+	}
+	if _, ok := GenreName(192); ok {
+		t.Errorf("Genre 192 should not exist")
+	}
+	if index, ok := GenreIndex("hip-hop"); !ok || index != 7 {
+		t.Errorf("Expected 7 for Hip-Hop, got %d", index)
+	}
+	if _, ok := GenreIndex("Nonexistent"); ok {
+		t.Errorf("Unknown genre found")
+	}
+}
+
+func TestGetGenres(t *testing.T) {
+	cases := []struct {
+		ver  byte
+		data string
+		want []string
+	}{
+		{4, "\x0317\x00Electronic\x0052", []string{"Rock", "Electronic", "Electronic"}},
+		{3, "\x00(17)Rock", []string{"Rock"}},
+		{3, "\x00(4)Eurodisco", []string{"Disco", "Eurodisco"}},
+		{3, "\x00(51)(39)", []string{"Techno-Industrial", "Noise"}},
+		{3, "\x00(RX)(CR)", []string{"Remix", "Cover"}},
+		{3, "\x00((Parens) Music", []string{"(Parens) Music"}},
+		{3, "\x00(999)", []string{"(999)"}},
+	}
+	for _, c := range cases {
+		id3tag, err := ReadID3Bytes(make_tag(c.ver, make_frame(c.ver, "TCON", 0, []byte(c.data))))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		if got, err := id3tag.GetGenres(); err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: expected %q, got %q, %v", c.data, c.want, got, err)
+		}
+	}
+}
+
+func TestSetGenres(t *testing.T) {
+	cases := []struct {
+		ver    Version
+		genres []string
+		data   string
+		want   []string
+	}{
+		{Version24, []string{"Rock", "(Parens) Music", "Tr\u00e4umerei"}, "\x03Rock\x00(Parens) Music\x00Tr\xc3\xa4umerei", nil},
+		{Version23, []string{"Rock"}, "\x00(17)Rock", nil},
+		{Version23, []string{"Rock", "Alternative"}, "\x00(17)(20)", nil},
+		{Version23, []string{"Rock", "(Parens) Music"}, "\x00(17)((Parens) Music", nil},
+		{Version23, []string{"(Parens) Music"}, "\x00((Parens) Music", nil},
+		{Version23, []string{"Tr\u00e4umerei", "Rock", "Custom"}, "\x00(17)Tr\xe4umerei/Custom", []string{"Rock", "Tr\u00e4umerei/Custom"}},
+		{Version22, []string{"Rock", "Pop"}, "\x00(17)(13)", nil},
+	}
+	for _, c := range cases {
+		id3tag, err := ReadID3Bytes(make_tag(byte(c.ver)))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		id3tag = id3tag.SetGenres(c.genres)
+		if data := id3tag.GetTagData("TCON")[0]; string(data) != c.data {
+			t.Errorf("v%v %q: expected TCON %q, got %q", c.ver, c.genres, c.data, data)
+		}
+
+		want := c.want
+		if want == nil {
+			want = c.genres
+		}
+		reread := id3tag
+		if c.ver != Version22 {
+			var buf bytes.Buffer
+			if err := WriteID3(&buf, id3tag); err != nil {
+				t.Fatalf("Error in writing tag: %v", err)
+			}
+			if reread, err = ReadID3(&buf); err != nil {
+				t.Fatalf("Error in reading written tag: %v", err)
+			}
+		}
+		if got, err := reread.GetGenres(); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("v%v: expected %q, got %q, %v", c.ver, want, got, err)
+		}
+	}
+}
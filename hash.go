@@ -0,0 +1,98 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"strings"
+)
+
+// Hash returns a SHA-256 hash of the content of the frame, which is the same for frames holding the same
+// values however they were encoded. The text of text frames, TXXX, URL, COMM and USLT frames is hashed
+// as decoded, along with the language of COMM and USLT frames, so re-encoding it from ISO-8859-1 to
+// UTF-16 or UTF-8 or adding string terminators leaves the hash unchanged. Other frames, and frames that
+// are compressed, encrypted or unsynchronised, are hashed as their raw data. Frame flags are not hashed.
+func (frame ID3Frame) Hash() [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte(frame.FrameID))
+	h.Write([]byte{0})
+	if values, ok := hash_values(frame); ok {
+		h.Write([]byte{'t'})
+		for _, value := range values {
+			h.Write([]byte(value))
+			h.Write([]byte{0})
+		}
+	} else {
+		h.Write([]byte{'b'})
+		h.Write(frame.Data)
+	}
+	var ret [sha256.Size]byte
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
+
+// Fingerprint returns a SHA-256 hash of the frames of the tag, combining their Hash without regard to
+// their order, so sync tools can tell whether the metadata of two files differs without comparing it
+// frame by frame. The tag version, padding and size are not part of it.
+func (id3tag ID3Tag) Fingerprint() [sha256.Size]byte {
+	hashes := make([][sha256.Size]byte, 0, len(id3tag.Frames))
+	for _, frame := range id3tag.Frames {
+		hashes = append(hashes, frame.Hash())
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+
+	h := sha256.New()
+	for _, sum := range hashes {
+		h.Write(sum[:])
+	}
+	var ret [sha256.Size]byte
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
+
+// hash_values returns the decoded values of a frame Hash hashes as text, or false if it hashes the frame
+// as raw data
+func hash_values(frame ID3Frame) ([]string, bool) {
+	data := frame.Data
+	if frame.Compression || frame.Encryption || frame.Unsynchronisation || len(data) == 0 {
+		return nil, false
+	}
+	var id3tag ID3Tag
+	switch {
+	case strings.HasPrefix(frame.FrameID, "T"):
+		values, err := id3tag.decodetext_values(data[0], data[1:len(data)])
+		if err != nil {
+			return nil, false
+		}
+		//a trailing terminator reads as an empty value
+		for len(values) > 1 && values[len(values)-1] == "" {
+			values = values[0 : len(values)-1]
+		}
+		return values, true
+	case frame.FrameID == "WXXX" || frame.FrameID == "WXX":
+		desc, url := split_text(data[0], data[1:len(data)])
+		description, err := id3tag.decodetext(data[0], desc)
+		if err != nil {
+			return nil, false
+		}
+		return []string{description, strings.TrimRight(decodeISO88591(url), "\x00")}, true
+	case strings.HasPrefix(frame.FrameID, "W"):
+		return []string{strings.TrimRight(decodeISO88591(data), "\x00")}, true
+	case (frame.FrameID == "COMM" || frame.FrameID == "USLT" || frame.FrameID == "COM" || frame.FrameID == "ULT") && len(data) >= 4:
+		desc, text := split_text(data[0], data[4:len(data)])
+		description, err := id3tag.decodetext(data[0], desc)
+		if err != nil {
+			return nil, false
+		}
+		txt, err := id3tag.decodetext(data[0], text)
+		if err != nil {
+			return nil, false
+		}
+		return []string{string(data[1:4]), description, strings.TrimRight(txt, "\x00")}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,83 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestFrameHash(t *testing.T) {
+	frame := func(id, data string) ID3Frame {
+		return ID3Frame{FrameID: id, Length: uint32(len(data)), Data: []byte(data)}
+	}
+	title := frame("TIT2", "\x00Caf\xe9")
+	flagged := title
+	flagged.ReadOnly, flagged.StatusFlags = true, 0x10
+
+	same := []ID3Frame{
+		frame("TIT2", "\x00Caf\xe9\x00"),
+		frame("TIT2", "\x01\xff\xfeC\x00a\x00f\x00\xe9\x00"),
+		frame("TIT2", "\x02\x00C\x00a\x00f\x00\xe9\x00\x00"),
+		frame("TIT2", "\x03Caf\u00e9"),
+		flagged,
+	}
+	for j, other := range same {
+		if title.Hash() != other.Hash() {
+			t.Errorf("Case %v: hash differs from that of the same text", j)
+		}
+	}
+
+	different := []ID3Frame{
+		frame("TIT2", "\x00Cafe"),
+		frame("TIT3", "\x00Caf\xe9"),
+		frame("TIT2", "\x00Caf\xe9\x00Bar"),
+		frame("PRIV", "\x00Caf\xe9"),
+	}
+	for j, other := range different {
+		if title.Hash() == other.Hash() {
+			t.Errorf("Case %v: hash matches that of different content", j)
+		}
+	}
+
+	comment := frame("COMM", "\x00engdesc\x00text")
+	if comment.Hash() != frame("COMM", "\x03engdesc\x00text\x00").Hash() {
+		t.Errorf("Hash of re-encoded comment differs")
+	}
+	if comment.Hash() == frame("COMM", "\x00deudesc\x00text").Hash() {
+		t.Errorf("Hash of comment in another language matches")
+	}
+	if frame("PRIV", "owner\x00data").Hash() == frame("PRIV", "owner\x00data\x00").Hash() {
+		t.Errorf("Hash of binary frames ignores their raw data")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	frame := func(id, data string) ID3Frame {
+		return ID3Frame{FrameID: id, Length: uint32(len(data)), Data: []byte(data)}
+	}
+	tag := ID3Tag{Version: Version24, Frames: []ID3Frame{
+		frame("TIT2", "\x03Title"),
+		frame("TPE1", "\x03Artist"),
+		frame("PRIV", "owner\x00data"),
+	}}
+	reordered := ID3Tag{Version: Version23, PaddingBytes: 1000, Frames: []ID3Frame{
+		frame("PRIV", "owner\x00data"),
+		frame("TPE1", "\x01\xff\xfeA\x00r\x00t\x00i\x00s\x00t\x00"),
+		frame("TIT2", "\x00Title"),
+	}}
+	if tag.Fingerprint() != reordered.Fingerprint() {
+		t.Errorf("Fingerprint of reordered, re-encoded and padded tag differs")
+	}
+
+	changed := tag.SetText("TPE1", "Other")
+	if tag.Fingerprint() == changed.Fingerprint() {
+		t.Errorf("Fingerprint of changed tag matches")
+	}
+	doubled := ID3Tag{Frames: append(append([]ID3Frame(nil), tag.Frames...), tag.Frames[0])}
+	if tag.Fingerprint() == doubled.Fingerprint() {
+		t.Errorf("Fingerprint of tag with a duplicate frame matches")
+	}
+	if (ID3Tag{}).Fingerprint() == tag.Fingerprint() {
+		t.Errorf("Fingerprint of empty tag matches")
+	}
+}
@@ -0,0 +1,107 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Hexdump writes an annotated hex dump of every frame with the given FrameID to w, or to standard error
+// if w is nil, for attaching to reports of misparsed frames. Each dump shows the frame header as it was
+// read, or as it would be written for the tag's version if the frame was not read from a tag of that
+// version, what its flags mean, the fields the flags put in front of the content, and the content itself.
+func (id3tag ID3Tag) Hexdump(w io.Writer, frameid string) error {
+	if w == nil {
+		w = os.Stderr
+	}
+	found := false
+	for j, frame := range id3tag.Frames {
+		if frame.FrameID != frameid {
+			continue
+		}
+		found = true
+		if err := id3tag.hexdump_frame(w, j, frame); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return errors.New(fmt.Sprintf("No such frame %v found in the taglist", frameid))
+	}
+	return nil
+}
+
+func (id3tag ID3Tag) hexdump_frame(w io.Writer, index int, frame ID3Frame) error {
+	name := "unknown frame"
+	if info, ok := LookupFrame(frame.FrameID); ok {
+		name = info.Name
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Frame %v (%v) at index %v, ID3v2.%v\n", frame.FrameID, name, index, id3tag.Version)
+
+	if header, ok := read_header(id3tag.Version, frame); ok {
+		fmt.Fprintf(&buf, "Header as read:\n%v", hex.Dump(header))
+	} else if id3tag.Version == Version22 {
+		size := uint32(len(frame.Data))
+		header = append([]byte(frame.FrameID), byte(size>>16), byte(size>>8), byte(size))
+		fmt.Fprintf(&buf, "Header as it would be written:\n%v", hex.Dump(header))
+	} else if encoded, err := encode_frame(id3tag.Version, frame); err == nil {
+		fmt.Fprintf(&buf, "Header as it would be written:\n%v", hex.Dump(encoded[0:len(encoded)-len(frame.Data)]))
+	} else {
+		fmt.Fprintf(&buf, "Header cannot be encoded: %v\n", err)
+	}
+	fmt.Fprintf(&buf, "Declared size %v, status flags 0x%02X, format flags 0x%02X\n", frame.Length, frame.StatusFlags, frame.FormatFlags)
+	fmt.Fprintf(&buf, "Flags: %v\n", describe_frame_flags(frame))
+	if frame.Grouping {
+		fmt.Fprintf(&buf, "Group symbol 0x%02X\n", frame.GroupSymbol)
+	}
+	if frame.Encryption {
+		fmt.Fprintf(&buf, "Encryption method 0x%02X\n", frame.EncryptionMethod)
+	}
+	if frame.DataLength != 0 {
+		fmt.Fprintf(&buf, "Data length %v\n", frame.DataLength)
+	}
+	fmt.Fprintf(&buf, "Content, %v bytes:\n%v\n", len(frame.Data), hex.Dump(frame.Data))
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// read_header returns the header the frame was read with, if it was read from a tag of version ver with
+// the ID and flags it has
+func read_header(ver Version, frame ID3Frame) ([]byte, bool) {
+	header := frame.header
+	if ver == Version22 {
+		return header, len(header) == 6 && string(header[0:3]) == frame.FrameID
+	}
+	return header, len(header) == 10 && string(header[0:4]) == frame.FrameID && header[8] == frame.StatusFlags && header[9] == frame.FormatFlags
+}
+
+// describe_frame_flags lists the flags set on frame
+func describe_frame_flags(frame ID3Frame) string {
+	flags := make([]string, 0)
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{frame.DiscardOnTagAlter, "discard on tag alter"},
+		{frame.DiscardOnFileAlter, "discard on file alter"},
+		{frame.ReadOnly, "read only"},
+		{frame.Grouping, "grouping"},
+		{frame.Compression, "compression"},
+		{frame.Encryption, "encryption"},
+		{frame.Unsynchronisation, "unsynchronisation"},
+		{frame.Data_Length_Indicator, "data length indicator"},
+	} {
+		if flag.set {
+			flags = append(flags, flag.name)
+		}
+	}
+	if len(flags) == 0 {
+		return "none"
+	}
+	return strings.Join(flags, ", ")
+}
@@ -0,0 +1,52 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHexdump(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "TIT2", 0x40, []byte("\x05\x03Title"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	var buf strings.Builder
+	if err := id3tag.Hexdump(&buf, "TIT2"); err != nil {
+		t.Fatalf("Error in dumping frame: %v", err)
+	}
+	dump := buf.String()
+	for _, want := range []string{
+		"Frame TIT2 (Title/Songname/Content description)",
+		"54 49 54 32 00 00 00 07  00 40 ",
+		"Flags: grouping",
+		"Group symbol 0x05",
+		"|.Title|",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("Expected the dump to contain %q:\n%v", want, dump)
+		}
+	}
+	if err := id3tag.Hexdump(&buf, "TALB"); err == nil {
+		t.Errorf("Expected an error for a missing frame")
+	}
+
+	//a size written as a plain integer is dumped as read, not as it would be written
+	data := append([]byte{3}, strings.Repeat("a", 127)...)
+	plain := append([]byte("TIT2\x00\x00\x00\x80\x00\x00"), data...)
+	if id3tag, err = ReadID3Bytes(make_tag(4, plain)); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	id3tag = id3tag.SetText("TALB", "Album")
+	buf.Reset()
+	id3tag.Hexdump(&buf, "TIT2")
+	if dump := buf.String(); !strings.Contains(dump, "Header as read:\n00000000  54 49 54 32 00 00 00 80  00 00 ") {
+		t.Errorf("Expected the header as read:\n%v", dump)
+	}
+	buf.Reset()
+	id3tag.Hexdump(&buf, "TALB")
+	if dump := buf.String(); !strings.Contains(dump, "Header as it would be written:\n00000000  54 41 4c 42 00 00 00 06  00 00 ") {
+		t.Errorf("Expected the header as it would be written:\n%v", dump)
+	}
+}
@@ -0,0 +1,203 @@
+// Package id3http serves the metadata of uploaded audio files as JSON, for deploying a metadata inspection
+// service straight from the id3v2reader package. Files are read with id3v2reader.ReadMetadata, so the
+// formats it knows are served; import the mp4 and flac sub-packages to add theirs.
+package id3http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+// DefaultMaxSize is the largest file a Handler reads unless told otherwise
+const DefaultMaxSize = 32 << 20
+
+// An Option configures a Handler.
+type Option func(*Handler)
+
+// MaxSize sets the largest file, uploaded or fetched, that the handler reads. Larger files are refused.
+func MaxSize(size int64) Option {
+	return func(h *Handler) {
+		h.max_size = size
+	}
+}
+
+// AllowURLs lets clients name a file to inspect with a url parameter instead of uploading it, which the
+// handler then fetches with client. This is off by default since it lets anyone who can reach the
+// handler make requests from the server; client should have a timeout and, where the server can reach
+// internal services, a transport refusing them.
+func AllowURLs(client *http.Client) Option {
+	return func(h *Handler) {
+		h.client = client
+	}
+}
+
+// A Handler answers requests carrying an audio file with its metadata. The file is either the request body
+// of a POST or PUT, or the "file" field of a multipart form, or, if AllowURLs is given, fetched from the
+// url query parameter. The response is a Response encoded as JSON, or an object with an "error" field
+// together with a 4xx or 5xx status.
+type Handler struct {
+	max_size int64
+	client   *http.Client
+}
+
+// New returns a Handler configured by opts
+func New(opts ...Option) *Handler {
+	h := &Handler{max_size: DefaultMaxSize}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Response is the JSON a Handler answers with, whose members are all snake_case; Metadata is encoded by
+// its MarshalJSON. Version, Text, Pictures and Warnings are only filled for files whose metadata is held
+// in an ID3v2 tag at their start.
+type Response struct {
+	Format   string               `json:"format"`
+	Version  string               `json:"version,omitempty"`
+	Metadata id3v2reader.Metadata `json:"metadata"`
+	Text     map[string][]string  `json:"text,omitempty"`
+	Pictures []PictureInfo        `json:"pictures,omitempty"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+// PictureInfo describes an attached picture without its data
+type PictureInfo struct {
+	MIMEType    string `json:"mime_type"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Size        int    `json:"size"`
+}
+
+// request_error is an error answered with a status other than 400
+type request_error struct {
+	status int
+	msg    string
+}
+
+func (err request_error) Error() string {
+	return err.msg
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := h.read_file(w, r)
+	if err == nil {
+		var resp Response
+		if resp, err = Inspect(data); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+	}
+	status := http.StatusBadRequest
+	if rerr, ok := err.(request_error); ok {
+		status = rerr.status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// read_file returns the file the request carries or names
+func (h *Handler) read_file(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if target := r.URL.Query().Get("url"); target != "" {
+		return h.fetch(target)
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+	default:
+		return nil, request_error{http.StatusMethodNotAllowed, "Upload a file with POST or PUT"}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.max_size+1<<20) //leaves room for multipart framing
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return read_limited(r.Body, h.max_size)
+	}
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		return nil, read_error(err, fmt.Sprintf("Could not read the form: %v", err))
+	}
+	defer r.MultipartForm.RemoveAll()
+	fil, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, errors.New("The form has no file field")
+	}
+	defer fil.Close()
+	return read_limited(fil, h.max_size)
+}
+
+func (h *Handler) fetch(target string) ([]byte, error) {
+	if h.client == nil {
+		return nil, request_error{http.StatusForbidden, "Fetching files by URL is not enabled"}
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New(fmt.Sprintf("Not an http or https URL: %q", target))
+	}
+	resp, err := h.client.Get(u.String())
+	if err != nil {
+		return nil, request_error{http.StatusBadGateway, fmt.Sprintf("Could not fetch %v: %v", u, err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, request_error{http.StatusBadGateway, fmt.Sprintf("Fetching %v returned %v", u, resp.Status)}
+	}
+	data, err := read_limited(resp.Body, h.max_size)
+	if _, ok := err.(request_error); err != nil && !ok {
+		return nil, request_error{http.StatusBadGateway, fmt.Sprintf("Could not fetch %v: %v", u, err)}
+	}
+	return data, err
+}
+
+// read_limited reads all of rd, refusing with 413 more than max_size bytes. Other read errors are
+// returned as they are, which are answered with 400 for the request's own body.
+func read_limited(rd io.Reader, max_size int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(rd, max_size+1))
+	if err != nil {
+		return nil, read_error(err, fmt.Sprintf("Could not read the file: %v", err))
+	}
+	if int64(len(data)) > max_size {
+		return nil, request_error{http.StatusRequestEntityTooLarge, fmt.Sprintf("Files larger than %v bytes are not accepted", max_size)}
+	}
+	return data, nil
+}
+
+// read_error returns err as msg, answered with 413 if it is the MaxBytesReader limit of the request
+// being reached
+func read_error(err error, msg string) error {
+	var too_large *http.MaxBytesError
+	if errors.As(err, &too_large) {
+		return request_error{http.StatusRequestEntityTooLarge, msg}
+	}
+	return errors.New(msg)
+}
+
+// Inspect returns the Response a Handler answers with for a file starting with data. The error of a file
+// that cannot be read gives the reason, as in the "error" field of a Handler's answer.
+func Inspect(data []byte) (Response, error) {
+	md, format, err := id3v2reader.ReadMetadata(bytes.NewReader(data))
+	if err != nil && format == "" {
+		return Response{}, request_error{http.StatusUnsupportedMediaType, err.Error()}
+	}
+	if err != nil {
+		return Response{}, request_error{http.StatusUnprocessableEntity, err.Error()}
+	}
+	resp := Response{Format: format, Metadata: md}
+	if id3tag, err := id3v2reader.ReadID3Bytes(data); err == nil {
+		resp.Version = id3tag.Version.String()
+		resp.Text = id3tag.AllText()
+		resp.Warnings = id3tag.Warnings
+		pics, _ := id3tag.GetPictures()
+		for _, pic := range pics {
+			resp.Pictures = append(resp.Pictures, PictureInfo{pic.MIMEType, pic.Type.String(), pic.Description, len(pic.Data)})
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,77 @@
+package id3http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHandler(t *testing.T) {
+	mp3, err := os.ReadFile("../testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := New(MaxSize(1 << 20))
+
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	part, _ := mw.CreateFormFile("file", "test.mp3")
+	part.Write(mp3)
+	mw.Close()
+
+	uploads := map[string]*http.Request{
+		"body": httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(mp3)),
+		"form": httptest.NewRequest(http.MethodPost, "/", &form),
+	}
+	uploads["form"].Header.Set("Content-Type", mw.FormDataContentType())
+	for name, req := range uploads {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("%v: unexpected response %v %s", name, rec.Code, rec.Body.Bytes())
+		}
+		if resp.Format != "mp3" || resp.Version != "2.4" || resp.Metadata.Album != "ID3 Tag Test" || len(resp.Pictures) != 1 {
+			t.Errorf("%v: unexpected response %+v", name, resp)
+		}
+		if body := rec.Body.String(); !strings.Contains(body, `"album":"ID3 Tag Test"`) || !strings.Contains(body, `"mime_type":`) {
+			t.Errorf("%v: response is not in snake_case: %s", name, body)
+		}
+	}
+
+	statuses := map[string]struct {
+		handler *Handler
+		req     *http.Request
+		status  int
+	}{
+		"too large": {New(MaxSize(1000)), httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(mp3)), http.StatusRequestEntityTooLarge},
+		"unknown":   {handler, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("not audio"))), http.StatusUnsupportedMediaType},
+		"broken":    {handler, httptest.NewRequest(http.MethodPost, "/", iotest.ErrReader(errors.New("connection reset"))), http.StatusBadRequest},
+		"get":       {handler, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusMethodNotAllowed},
+		"url":       {handler, httptest.NewRequest(http.MethodGet, "/?url=http://localhost/test.mp3", nil), http.StatusForbidden},
+	}
+	for name, c := range statuses {
+		rec := httptest.NewRecorder()
+		c.handler.ServeHTTP(rec, c.req)
+		if rec.Code != c.status {
+			t.Errorf("%v: expected status %v, got %v %s", name, c.status, rec.Code, rec.Body.Bytes())
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(mp3)
+	}))
+	defer server.Close()
+	rec := httptest.NewRecorder()
+	New(AllowURLs(server.Client())).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?url="+server.URL, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the file to be fetched, got %v %s", rec.Code, rec.Body.Bytes())
+	}
+}
@@ -1,32 +1,108 @@
-//
 package id3v2reader
 
 import (
 	//"bytes"
 	"bytes"
 	"errors"
-	"fmt"
 	"io"
-	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
 	"unicode/utf16"
 )
 
+// ErrNoTag is returned by ReadID3 and its variants when the input does not start with an ID3v2 tag, that
+// is when a complete header was read and does not match. Errors reading the header, including inputs too
+// short to hold one, are returned wrapped instead, so that callers can tell files to check for an ID3v1
+// tag from files that could not be read.
+var ErrNoTag = errors.New("Did not find an ID3v2 header at start of file")
+
 // ID3Frames contain the data extracted from each frame. Data extraction functions are bound to ID3Frame to give human readable representations
 // Since flag handling differs between ID3 versions, each frame has 1 byte of version info appended
+// Length is the frame size declared in the frame header. Any group symbol, encryption method and data length
+// indicator preceding the frame content are moved into their own fields, so Data holds only the (possibly
+// still compressed or encrypted) frame content. DataLength is the decoded content size declared by the v2.4
+// data length indicator or the v2.3 decompressed size field, and is zero when the frame declares none.
+// StatusFlags and FormatFlags are the two flag bytes exactly as they appeared in the frame header, reserved
+// bits included, so that a writer can reproduce them even where the decoded booleans cannot.
+// DiscardOnTagAlter and DiscardOnFileAlter are the tag and file alter preservation flags: a frame carrying
+// them must be dropped when the tag, respectively the audio, is changed by a program that does not know
+// the frame. ReadOnly frames should not be changed without knowing why they were marked so.
 type ID3Frame struct {
 	FrameID               string
 	Length                uint32
+	StatusFlags           byte
+	FormatFlags           byte
+	DiscardOnTagAlter     bool
+	DiscardOnFileAlter    bool
+	ReadOnly              bool
 	Compression           bool
 	Encryption            bool
 	Unsynchronisation     bool
 	Data_Length_Indicator bool
+	Grouping              bool
+	GroupSymbol           byte
+	EncryptionMethod      byte
+	DataLength            uint32
 	Data                  []byte
+	header                []byte //the frame header as read, which Hexdump shows
+}
+
+// Version is the major version of an ID3v2 tag, 2 for ID3v2.2, 3 for ID3v2.3 and 4 for ID3v2.4. v2.2 tags
+// are read with their three character frame IDs as is, so getters looking for v2.3 frame IDs do not find
+// their frames; GetPictures and GetCoverPic do read v2.2 PIC frames.
+type Version byte
+
+const (
+	Version22 Version = 2
+	Version23 Version = 3
+	Version24 Version = 4
+)
+
+func (ver Version) String() string {
+	return "2." + strconv.Itoa(int(ver))
 }
 
-// An ID3Tag type is an alias for a dictionary mapping FrameID with an ID3Frame. Though ID3 tags can contain theoritically multiple non
-// text-frames of the same type (text-frames are restricted) as per standard, a simplifying assumption of 1 to 1 mapping between FrameID
-// and the frame data is used here. ReadID3 function reads only the very first occurance of any FrameID
-type ID3Tag []ID3Frame
+// An ID3Tag holds the frames of a tag in the order they were read along with what its header declared.
+// Though ID3 tags can contain theoritically multiple non text-frames of the same type (text-frames are
+// restricted) as per standard, the getters like GetTitle use only the very first occurance of a FrameID.
+// ID3Tag used to be a []ID3Frame; code ranging over or indexing a tag must now use Frames, as noted in
+// the changelog.
+//
+// Size is the tag size declared in the tag header, which excludes the 10 byte header itself. Of those bytes,
+// PaddingBytes were zero padding following the last frame and UnparsedBytes could be attributed neither to
+// frames nor to padding - typically because the encoder overstated the tag size or wrote a corrupt frame.
+// Footer is set for v2.4 tags followed by a footer. Experimental is set for tags whose header carries the
+// experimental indicator, which are only read when WithExperimentalTags allows them.
+//
+// An ID3Tag is safe for concurrent reads from any number of goroutines, including the getters, which decode
+// each frame once and keep the result in a cache guarded against concurrent use. Methods that change a tag,
+// such as WithFrame or SetText, never modify the tag they are called on: they return a changed copy with a
+// Frames slice of its own, so a tag handed to other goroutines can be edited without locking, and frames
+// of the copy can be assigned or appended without the original seeing it.
+//
+// Nothing stops code from changing Frames or frame Data in place, which is not safe: the exported fields
+// are the tag itself, not copies. Frame Data in particular is shared between a tag and its copies and must
+// be treated as read-only; tags read with ReadID3Bytes additionally share Data with the buffer they were
+// read from. Use Clone for a tag whose Data may be changed.
+type ID3Tag struct {
+	Version        Version
+	Revision       byte
+	Size           uint32
+	Footer         bool
+	Experimental   bool
+	ExtendedHeader *ExtendedHeader //nil unless the tag has an extended header
+	Frames         []ID3Frame
+	PaddingBytes   uint32
+	UnparsedBytes  uint32
+	Warnings       []string //problems worked around while reading, unless read with WithStrictParsing
+
+	cfg              read_config //options the tag was read with, which also govern decoding
+	altered          bool        //set by the mutators so that WriteID3 knows the tag was altered
+	original         []ID3Frame  //the frames as read, for Changes; nil for tags not read
+	original_version Version
+	cache            *text_cache //decoded text of the frames, shared by unchanged copies of the tag; nil for tags not read
+}
 
 func decodeISO88591(buf []byte) string {
 	end_of_string := bytes.IndexByte(buf, 0)
@@ -71,6 +147,12 @@ func decodetext(encoding byte, data []byte) (string, error) {
 	case 0:
 		return decodeISO88591(data), nil
 	case 1:
+		if len(data) == 0 {
+			//an empty string, such as a description, has nothing to put a BOM in front of
+			return "", nil
+		} else if len(data) < 2 {
+			break
+		}
 		if data[0] == 0xFE && data[1] == 0xFF {
 			return decodeUTF16(data[2:len(data)], true), nil
 		} else if data[0] == 0xFF && data[1] == 0xFE {
@@ -84,31 +166,249 @@ func decodetext(encoding byte, data []byte) (string, error) {
 	return "", errors.New("Unable to parse text frame")
 }
 
-func read_bytes(rd io.Reader, length uint32) ([]byte, error) {
-	buf := make([]byte, length)
-	n, _ := rd.Read(buf)
-	if uint32(n) == length {
-		return buf, nil
+// split_text splits data at the end of its first string, which is terminated by a single zero byte in
+// ISO-8859-1 and UTF-8 and by a zero code unit in the UTF-16 encodings. The terminator itself is dropped.
+// A string that is not terminated runs to the end of data.
+func split_text(encoding byte, data []byte) (first, rest []byte) {
+	if encoding == 1 || encoding == 2 {
+		for j := 0; j+1 < len(data); j += 2 {
+			if data[j] == 0 && data[j+1] == 0 {
+				return data[0:j], data[j+2 : len(data)]
+			}
+		}
+		return data, nil
+	}
+	if end := bytes.IndexByte(data, 0); end != -1 {
+		return data[0:end], data[end+1 : len(data)]
 	}
-	return nil, errors.New(fmt.Sprintf("Could not read %v bytes", length))
+	return data, nil
 }
 
-func read_validated(rd io.Reader, length uint32, match_pattern string) ([]byte, error) {
-	if buf, err := read_bytes(rd, length); err != nil {
-		return nil, err
-	} else {
-		valid, rxperr := regexp.Match(match_pattern, buf)
-		if rxperr == nil && valid {
+// decodetext decodes like the package level decodetext but applies the options the tag was read with
+func (id3tag ID3Tag) decodetext(encoding byte, data []byte) (string, error) {
+	var txt string
+	var err error
+	if encoding == 1 && !id3tag.cfg.no_utf16_guess && !has_bom(data) {
+		txt = decodeUTF16(data, guess_utf16_bigendian(data))
+	} else if txt, err = decodetext(encoding, data); err != nil {
+		return txt, err
+	}
+	if id3tag.cfg.trim_text {
+		txt = trim_text(txt)
+	}
+	return txt, nil
+}
+
+// trim_text removes trailing whitespace and nulls and replaces runs of control characters other than
+// line breaks with a space
+func trim_text(txt string) string {
+	var buf strings.Builder
+	in_controls := false
+	for _, r := range txt {
+		if unicode.IsControl(r) && r != '\n' {
+			if !in_controls {
+				buf.WriteByte(' ')
+			}
+			in_controls = true
+			continue
+		}
+		in_controls = false
+		buf.WriteRune(r)
+	}
+	return strings.TrimRightFunc(buf.String(), unicode.IsSpace)
+}
+
+// decodetext_values decodes every string in a text frame body. v2.4 separates multiple values with the
+// encoding's terminator; in UTF-16 each value normally starts with its own BOM, and values missing one
+// reuse the BOM of the first value.
+func (id3tag ID3Tag) decodetext_values(encoding byte, data []byte) ([]string, error) {
+	values := make([]string, 0, 1)
+	var bom []byte
+	for len(data) > 0 {
+		var value []byte
+		value, data = split_text(encoding, data)
+		if encoding == 1 {
+			if has_bom(value) {
+				bom = value[0:2]
+			} else if bom != nil {
+				value = append(append(make([]byte, 0, len(value)+2), bom...), value...)
+			}
+		}
+		text, err := id3tag.decodetext(encoding, value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, text)
+	}
+	return values, nil
+}
+
+func has_bom(data []byte) bool {
+	return len(data) >= 2 && (data[0] == 0xFE && data[1] == 0xFF || data[0] == 0xFF && data[1] == 0xFE)
+}
+
+// guess_utf16_bigendian guesses the byte order of UTF-16 text that lacks a BOM. Most tag text is Latin
+// script, whose code units have a zero high byte: those zeros land on even offsets in big endian text
+// and on odd offsets in little endian text. Ties go to little endian, which most BOM-less taggers write.
+func guess_utf16_bigendian(data []byte) bool {
+	even_zeros, odd_zeros := 0, 0
+	for j := 0; j+1 < len(data); j += 2 {
+		if data[j] == 0 && data[j+1] == 0 {
+			break
+		}
+		if data[j] == 0 {
+			even_zeros++
+		}
+		if data[j+1] == 0 {
+			odd_zeros++
+		}
+	}
+	return even_zeros > odd_zeros
+}
+
+// declared lengths above this are not trusted for a single up front allocation
+const max_prealloc = 1 << 20
+
+func read_bytes(rd io.Reader, length uint32) ([]byte, error) {
+	var err error
+	if length <= max_prealloc {
+		buf := make([]byte, length)
+		if _, err = io.ReadFull(rd, buf); err == nil {
 			return buf, nil
 		}
+	} else {
+		//let the buffer grow as data actually arrives so a corrupt or hostile size field
+		//cannot make us allocate hundreds of megabytes for a short input
+		var buf bytes.Buffer
+		if _, err = io.CopyN(&buf, rd, int64(length)); err == nil {
+			return buf.Bytes(), nil
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	return nil, wrap_error("Could not read "+utoa(uint64(length))+" bytes", err)
+}
+
+// tag_source hands out successive chunks of a tag, either copied out of a stream or sliced
+// straight out of an in-memory buffer, and passes over chunks nobody wants
+type tag_source interface {
+	next(length uint32) ([]byte, error)
+	skip(length uint32) error
+}
+
+// A peeker is a tag_source that can return up to length of the bytes lying offset bytes ahead without
+// consuming anything, fewer if the data ends first, so that the parser can check where a frame would end
+type peeker interface {
+	peek(offset, length uint32) ([]byte, bool)
+}
+
+type reader_source struct {
+	rd io.Reader
+}
+
+func (src reader_source) next(length uint32) ([]byte, error) {
+	return read_bytes(src.rd, length)
+}
+
+// new_reader_source returns the source ReadID3 parses rd from. In single read mode the whole tag is read
+// up front, or as much of it as rd holds so that truncated tags are handled as in the default mode.
+func new_reader_source(rd io.Reader, cfg read_config) (tag_source, error) {
+	if !cfg.single_read {
+		return reader_source{rd}, nil
+	}
+	header := make([]byte, 10)
+	n, err := io.ReadFull(rd, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, wrap_error("Could not read the tag header", err)
+	}
+	length, ok := stream_tag_length(header[0:n])
+	if !ok {
+		return &bytes_source{header[0:n]}, nil
+	}
+	var buf bytes.Buffer
+	if length <= max_prealloc {
+		buf.Grow(10 + int(length))
+	}
+	buf.Write(header)
+	if _, err := io.CopyN(&buf, rd, length); err != nil && err != io.EOF {
+		return nil, wrap_error("Could not read the tag", err)
+	}
+	return &bytes_source{buf.Bytes()}, nil
+}
+
+// skip seeks past length bytes when rd can seek and reads and discards them otherwise. A seek beyond the
+// end of rd is not noticed until the next read.
+func (src reader_source) skip(length uint32) error {
+	if seeker, ok := src.rd.(io.Seeker); ok {
+		_, err := seeker.Seek(int64(length), io.SeekCurrent)
+		return err
+	}
+	if _, err := io.CopyN(io.Discard, src.rd, int64(length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return wrap_error("Could not read "+utoa(uint64(length))+" bytes", err)
 	}
-	return nil, errors.New(fmt.Sprintf("Could not find a match to the expression %s\n", match_pattern))
+	return nil
+}
+
+// peek reads ahead when rd can seek and reports false otherwise
+func (src reader_source) peek(offset, length uint32) ([]byte, bool) {
+	seeker, ok := src.rd.(io.Seeker)
+	if !ok {
+		return nil, false
+	}
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false
+	}
+	defer seeker.Seek(pos, io.SeekStart)
+	if _, err := seeker.Seek(int64(offset), io.SeekCurrent); err != nil {
+		return nil, false
+	}
+	buf := make([]byte, length)
+	n, _ := io.ReadFull(src.rd, buf)
+	return buf[0:n], true
+}
+
+type bytes_source struct {
+	buf []byte
+}
+
+func (src *bytes_source) next(length uint32) ([]byte, error) {
+	if uint64(length) > uint64(len(src.buf)) {
+		return nil, wrap_error("Could not read "+utoa(uint64(length))+" bytes", io.ErrUnexpectedEOF)
+	}
+	//capacity is clipped so that appending to one frame can never overwrite the next
+	buf := src.buf[0:length:length]
+	src.buf = src.buf[length:len(src.buf)]
+	return buf, nil
+}
+
+func (src *bytes_source) peek(offset, length uint32) ([]byte, bool) {
+	if uint64(offset) >= uint64(len(src.buf)) {
+		return nil, true
+	}
+	rest := src.buf[offset:len(src.buf)]
+	if uint64(length) < uint64(len(rest)) {
+		rest = rest[0:length]
+	}
+	return rest, true
+}
+
+func (src *bytes_source) skip(length uint32) error {
+	_, err := src.next(length)
+	return err
 }
 
 func convert_synchsafe_int(buf []byte) (uint32, error) {
 	retval := uint32(0)
-	valid, rxperr := regexp.Match("[\x00-\x7F]{4}", buf)
-	if rxperr == nil && valid {
+	valid := len(buf) >= 4
+	for j := 0; valid && j < 4; j++ {
+		valid = buf[j] < 0x80
+	}
+	if valid {
 		for j := 0; j < 4; j++ {
 			retval = retval | (uint32(0x7F&buf[j]) << uint(7*(3-j)))
 		}
@@ -122,13 +422,13 @@ func convert_regular_int(buf []byte) (uint32, error) {
 	if len(buf) != 4 {
 		return uint32(0), errors.New("4 bytes are needed to convert a regular uint")
 	}
-	return uint32(buf[0]<<24 | buf[1]<<16 | buf[2]<<8 | buf[3]), nil
+	return uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3]), nil
 }
 
 func read_bitbool(b byte) (bit7, bit6, bit5, bit4, bit3, bit2, bit1, bit0 bool) {
 	retbools := make([]bool, 8)
 	for j := uint(0); j < 8; j++ {
-		if b&1<<j != 0 {
+		if b&(1<<j) != 0 {
 			retbools[j] = true
 		} else {
 			retbools[j] = false
@@ -137,63 +437,574 @@ func read_bitbool(b byte) (bit7, bit6, bit5, bit4, bit3, bit2, bit1, bit0 bool)
 	return retbools[7], retbools[6], retbools[5], retbools[4], retbools[3], retbools[2], retbools[1], retbools[0]
 }
 
-func ReadID3(rd io.Reader) (ID3Tag, error) {
+// split_frame_data moves the fields that frame flags add in front of the frame content into their own
+// ID3Frame fields. v2.4 orders them group symbol, encryption method, data length indicator while v2.3 orders
+// them decompressed size, encryption method, group symbol.
+func split_frame_data(tag_ver byte, curframe *ID3Frame, frdata []byte) error {
+	take := func(length int) ([]byte, error) {
+		if len(frdata) < length {
+			return nil, errors.New("Frame " + curframe.FrameID + " is too short for the fields its flags declare")
+		}
+		field := frdata[0:length]
+		frdata = frdata[length:len(frdata)]
+		return field, nil
+	}
+
+	if tag_ver == 3 {
+		if curframe.Compression {
+			field, err := take(4)
+			if err != nil {
+				return err
+			}
+			if curframe.DataLength, err = convert_regular_int(field); err != nil {
+				return err
+			}
+		}
+		if curframe.Encryption {
+			field, err := take(1)
+			if err != nil {
+				return err
+			}
+			curframe.EncryptionMethod = field[0]
+		}
+		if curframe.Grouping {
+			field, err := take(1)
+			if err != nil {
+				return err
+			}
+			curframe.GroupSymbol = field[0]
+		}
+	} else {
+		if curframe.Grouping {
+			field, err := take(1)
+			if err != nil {
+				return err
+			}
+			curframe.GroupSymbol = field[0]
+		}
+		if curframe.Encryption {
+			field, err := take(1)
+			if err != nil {
+				return err
+			}
+			curframe.EncryptionMethod = field[0]
+		}
+		if curframe.Data_Length_Indicator {
+			field, err := take(4)
+			if err != nil {
+				return err
+			}
+			if curframe.DataLength, err = convert_synchsafe_int(field); err != nil {
+				return err
+			}
+		}
+	}
+	curframe.Data = frdata
+	return nil
+}
+
+// ReadID3 reads an ID3v2 tag from the start of rd. Frame data is copied into freshly allocated slices.
+func ReadID3(rd io.Reader, opts ...Option) (ID3Tag, error) {
+	cfg := new_read_config(opts)
+	rd, cfg.header_offset = scan_reader(rd, cfg.scan_limit)
+	src, err := new_reader_source(rd, cfg)
+	if err != nil {
+		return ID3Tag{}, err
+	}
+	return read_tag(src, cfg)
+}
+
+// ReadID3Bytes reads an ID3v2 tag from the start of buf. Unlike ReadID3, no frame data is copied:
+// the Data of every returned frame aliases buf, so buf must not be modified while the tag is in use.
+// This makes it cheap to scan tags carrying large embedded payloads out of memory mapped files.
+func ReadID3Bytes(buf []byte, opts ...Option) (ID3Tag, error) {
+	cfg := new_read_config(opts)
+	if cfg.header_offset = find_header(buf, cfg.scan_limit); cfg.header_offset > 0 {
+		buf = buf[cfg.header_offset:len(buf)]
+	}
+	return read_tag(&bytes_source{buf}, cfg)
+}
+
+// ReadID3Func reads the ID3v2 tag at the start of rd like ReadID3 but hands each frame to fn as soon as it
+// is parsed instead of collecting them into an ID3Tag, so that scans of large libraries hold at most one
+// frame in memory. fn may return io.EOF to stop reading once it has found what it needs, in which case
+// ReadID3Func returns nil and rd is left positioned after the last frame read. Any other error fn returns
+// stops reading and is returned.
+func ReadID3Func(rd io.Reader, fn func(ID3Frame) error, opts ...Option) error {
+	cfg := new_read_config(opts)
+	cfg.on_frame = fn
+	rd, cfg.header_offset = scan_reader(rd, cfg.scan_limit)
+	src, err := new_reader_source(rd, cfg)
+	if err != nil {
+		return err
+	}
+	_, err = read_tag(src, cfg)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// RawTag returns the tag at the start of rd exactly as it is stored: header, extended header, frames,
+// padding and footer. The tag is not parsed, so tags of any version and with any flags are returned, for
+// archiving them or transplanting them into other files.
+func RawTag(rd io.Reader) ([]byte, error) {
+	header, err := read_bytes(rd, 10)
+	if err != nil {
+		return nil, wrap_error("Could not read the tag header", err)
+	}
+	length, ok := stream_tag_length(header)
+	if !ok {
+		return nil, ErrNoTag
+	}
+	body, err := read_bytes(rd, uint32(length))
+	if err != nil {
+		return nil, wrap_error("Tag is truncated", err)
+	}
+	return append(header, body...), nil
+}
+
+// stream_tag_length returns the number of bytes following header that belong to the tag it starts
+func stream_tag_length(header []byte) (int64, bool) {
+	if len(header) < 10 || string(header[0:3]) != "ID3" || header[3] == 0xFF || header[4] == 0xFF {
+		return 0, false
+	}
+	size, err := convert_synchsafe_int(header[6:10])
+	if err != nil {
+		return 0, false
+	}
+	if header[3] == 4 && header[5]&0x10 != 0 {
+		return int64(size) + 10, true
+	}
+	return int64(size), true
+}
+
+func read_tag(src tag_source, cfg read_config) (ID3Tag, error) {
 
 	var tag_ver byte
-	var header_unsync, header_has_ext, header_expt bool
-	var tag_length, data_read_ctr uint32
+	var header_unsync, header_has_ext, header_expt, header_footer bool
+	var tag_length uint32
+	var data_read_ctr uint64 //v2.3 frame sizes are full 32 bit values so the running total needs headroom
+	var frames_read int
+
+	var rettag = ID3Tag{Frames: make([]ID3Frame, 0), cache: new_text_cache()}
+
+	//problems the parser can work around are errors in strict mode and warnings otherwise
+	problem := func(err error) error {
+		if cfg.strict {
+			return err
+		}
+		rettag.Warnings = append(rettag.Warnings, err.Error())
+		return nil
+	}
+
+	//a read coming up short means the input ends within the tag, which is worked around like any other
+	//problem, while every other failure of the reader is returned
+	truncated := false //set once reading the tag failed, after which there is no footer left to consume
+	read_failed := func(err error) error {
+		truncated = true
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return problem(errors.New("Tag of " + utoa(uint64(tag_length)) + " bytes is truncated after " + utoa(data_read_ctr) + " bytes"))
+		}
+		return err
+	}
 
-	var rettag = make(ID3Tag, 1)
+	if cfg.header_offset > 0 {
+		rettag.Warnings = append(rettag.Warnings, "Tag header found after "+strconv.Itoa(cfg.header_offset)+" bytes of junk")
+	}
 
 	//read and validate the ID3 tag header
-	if header, header_err := read_validated(rd, 10, "ID3[\x03\x04]..[\x00-\x7F]{4}"); header_err != nil {
-		return nil, errors.New("Did not find supported ID3v2 header at start of file")
+	if header, header_err := src.next(10); header_err != nil {
+		return ID3Tag{}, wrap_error("Could not read the tag header", header_err)
+	} else if string(header[0:3]) != "ID3" {
+		return ID3Tag{}, ErrNoTag
+	} else if header[3] == 0xFF || header[4] == 0xFF {
+		//the standard reserves 0xFF so that a header can never be mistaken for an MPEG sync
+		return ID3Tag{}, errors.New("Invalid ID3v2 version " + utoa(uint64(header[3])) + "." + utoa(uint64(header[4])))
+	} else if header[3] < 2 || header[3] > 4 {
+		return ID3Tag{}, errors.New("Unsupported ID3v2 version " + utoa(uint64(header[3])))
 	} else {
+		//revisions are backwards compatible within a major version, so any revision is read
 		tag_ver = header[3]
-		header_unsync, header_has_ext, header_expt, _, _, _, _, _ = read_bitbool(header[5:6][0])
-		tag_length, _ = convert_synchsafe_int(header[6:10])
+		header_unsync, header_has_ext, header_expt, header_footer, _, _, _, _ = read_bitbool(header[5:6][0])
+		var size_err error
+		if tag_length, size_err = convert_synchsafe_int(header[6:10]); size_err != nil {
+			return ID3Tag{}, errors.New("Invalid tag size: " + size_err.Error())
+		}
+		if undefined := header[5] & [5]byte{2: 0x3F, 3: 0x1F, 4: 0x0F}[tag_ver]; undefined != 0 {
+			if err := problem(errors.New("Tag header sets undefined flags " + hex_bytes([]byte{undefined}))); err != nil {
+				return ID3Tag{}, err
+			}
+		}
 
-		if header_unsync || header_has_ext || header_expt {
-			return nil, errors.New(fmt.Sprintf("Tag has one or more unsupported features: Unsynchronization:%v Extended Header:%v Experimental:%v", header_unsync, header_has_ext, header_expt))
+		if tag_ver == 2 {
+			//v2.2 uses the bit for compression, for which no scheme was ever defined, and has no other flags
+			if header_has_ext {
+				return ID3Tag{}, errors.New("Tag has one or more unsupported features: Compression:true")
+			}
+			header_expt, header_footer = false, false
 		}
 
+		if header_unsync || header_expt && !cfg.allow_experimental {
+			return ID3Tag{}, errors.New("Tag has one or more unsupported features: Unsynchronization:" + strconv.FormatBool(header_unsync) + " Experimental:" + strconv.FormatBool(header_expt))
+		}
+
+		rettag.Version = Version(tag_ver)
+		rettag.Revision = header[4]
+		rettag.Size = tag_length
+		rettag.cfg = cfg
+		rettag.Footer = tag_ver == 4 && header_footer
+		rettag.Experimental = header_expt
+
 		data_read_ctr = 0
 
-		for data_read_ctr < tag_length {
-			if frameheader, frameheader_err := read_validated(rd, 10, "[A-Z0-9]{4}......"); frameheader_err != nil {
+		//v2.2 frame headers are a three character ID and a three byte size without any flags
+		frameheader_length, frameid_length := uint32(10), 4
+		if tag_ver == 2 {
+			frameheader_length, frameid_length = 6, 3
+		}
+
+		if header_has_ext {
+			exthdr, exthdr_length, exthdr_err := read_extended_header(src, tag_ver)
+			if exthdr_err != nil {
+				return ID3Tag{}, exthdr_err
+			}
+			rettag.ExtendedHeader = exthdr
+			data_read_ctr = uint64(exthdr_length)
+		}
+
+		for data_read_ctr < uint64(tag_length) {
+			if uint64(tag_length)-data_read_ctr < uint64(frameheader_length) {
+				//too short for another frame header
+				var scan_err error
+				rettag.PaddingBytes, rettag.UnparsedBytes, scan_err = scan_remainder(src, nil, tag_length-uint32(data_read_ctr))
+				if scan_err != nil {
+					if err := read_failed(scan_err); err != nil {
+						return ID3Tag{}, err
+					}
+				}
 				break
+			}
+			frameheader, frameheader_err := src.next(frameheader_length)
+			if frameheader_err != nil {
+				rettag.UnparsedBytes = tag_length - uint32(data_read_ctr)
+				if err := read_failed(frameheader_err); err != nil {
+					return ID3Tag{}, err
+				}
+				break
+			}
+			if !valid_frameid_bytes(frameheader[0:frameid_length], cfg.lenient_frameids) {
+				var scan_err error
+				rettag.PaddingBytes, rettag.UnparsedBytes, scan_err = scan_remainder(src, frameheader, tag_length-uint32(data_read_ctr))
+				if scan_err != nil {
+					if err := read_failed(scan_err); err != nil {
+						return ID3Tag{}, err
+					}
+				}
+				break
+			}
+
+			curframe := new(ID3Frame)
+			curframe.header = append([]byte(nil), frameheader...)
+			var size_err error
+			if tag_ver == 2 {
+				curframe.FrameID = string(frameheader[0:3])
+				curframe.Length, size_err = convert_regular_int(append([]byte{0}, frameheader[3:6]...))
 			} else {
-				curframe := new(ID3Frame)
 				curframe.FrameID = string(frameheader[0:4])
-				if tag_ver == 3 {
+				curframe.StatusFlags = frameheader[8]
+				curframe.FormatFlags = frameheader[9]
+			}
+			if tag_ver == 3 {
+				curframe.Length, size_err = convert_regular_int(frameheader[4:8])
+				curframe.DiscardOnTagAlter, curframe.DiscardOnFileAlter, curframe.ReadOnly, _, _, _, _, _ = read_bitbool(frameheader[8])
+				curframe.Compression, curframe.Encryption, curframe.Grouping, _, _, _, _, _ = read_bitbool(frameheader[9])
+				curframe.Data_Length_Indicator = false
+				curframe.Unsynchronisation = false
+			} else if tag_ver == 4 {
+				if curframe.Length, size_err = convert_synchsafe_int(frameheader[4:8]); size_err != nil {
+					//several taggers wrote v2.4 frame sizes as plain integers, which is the best guess left
 					curframe.Length, _ = convert_regular_int(frameheader[4:8])
-					curframe.Compression, curframe.Encryption, _, _, _, _, _, _ = read_bitbool(frameheader[9])
-					curframe.Data_Length_Indicator = false
-					curframe.Unsynchronisation = false
-				} else { //tag version is 4 already checked for only 3 & 4 match before getting here
-					curframe.Length, _ = convert_synchsafe_int(frameheader[4:8])
-					_, _, _, _, curframe.Compression, curframe.Encryption, curframe.Unsynchronisation, curframe.Data_Length_Indicator = read_bitbool(frameheader[9])
+					size_err = problem(errors.New("Frame " + curframe.FrameID + " size " + hex_bytes(frameheader[4:8]) + " is not synchsafe"))
 				}
+				_, curframe.DiscardOnTagAlter, curframe.DiscardOnFileAlter, curframe.ReadOnly, _, _, _, _ = read_bitbool(frameheader[8])
+				_, curframe.Grouping, _, _, curframe.Compression, curframe.Encryption, curframe.Unsynchronisation, curframe.Data_Length_Indicator = read_bitbool(frameheader[9])
+			}
 
-				if frdata, dterr := read_bytes(rd, curframe.Length); dterr != nil {
+			if size_err != nil {
+				return ID3Tag{}, size_err
+			}
+			if !cfg.strict && tag_ver > 2 {
+				//old iTunes and LAME versions wrote v2.3 frame sizes synchsafe, and some taggers wrote v2.4
+				//sizes as plain integers that happen to be valid synchsafe ones, so when the size does not
+				//lead to another frame but the other reading does, the other reading is taken
+				remaining := uint64(tag_length) - data_read_ctr - uint64(frameheader_length)
+				pk, can_peek := src.(peeker)
+				plausible := func(length uint32) bool {
+					if uint64(length) >= remaining {
+						return uint64(length) == remaining
+					}
+					next, ok := pk.peek(length, 4)
+					return ok && (len(next) > 0 && next[0] == 0 || len(next) == 4 && valid_frameid_bytes(next, cfg.lenient_frameids))
+				}
+				other, other_err := convert_synchsafe_int(frameheader[4:8])
+				desc := "synchsafe"
+				if tag_ver == 4 {
+					other, other_err = convert_regular_int(frameheader[4:8])
+					desc = "a plain integer"
+				}
+				if can_peek && other_err == nil && other != curframe.Length && !plausible(curframe.Length) && plausible(other) {
+					if err := problem(errors.New("Frame " + curframe.FrameID + " size " + hex_bytes(frameheader[4:8]) + " was written as " + desc)); err != nil {
+						return ID3Tag{}, err
+					}
+					curframe.Length = other
+				}
+			}
+			if remaining := uint64(tag_length) - data_read_ctr - uint64(frameheader_length); uint64(curframe.Length) > remaining {
+				if err := problem(errors.New("Frame " + curframe.FrameID + " declares " + utoa(uint64(curframe.Length)) + " bytes but only " + utoa(remaining) + " remain in the tag")); err != nil {
+					return ID3Tag{}, err
+				}
+				curframe.Length = uint32(remaining)
+			}
+
+			if !cfg.wants_frame(curframe.FrameID) {
+				if skip_err := src.skip(curframe.Length); skip_err != nil {
+					rettag.UnparsedBytes = tag_length - uint32(data_read_ctr)
+					if err := read_failed(skip_err); err != nil {
+						return ID3Tag{}, err
+					}
+					if cfg.on_truncated != nil {
+						cfg.on_truncated(*curframe, 10+data_read_ctr+uint64(frameheader_length))
+					}
 					break
+				}
+				data_read_ctr += uint64(curframe.Length) + uint64(frameheader_length)
+				continue
+			}
+
+			if frdata, dterr := src.next(curframe.Length); dterr != nil {
+				rettag.UnparsedBytes = tag_length - uint32(data_read_ctr)
+				if err := read_failed(dterr); err != nil {
+					return ID3Tag{}, err
+				}
+				if cfg.on_truncated != nil {
+					cfg.on_truncated(*curframe, 10+data_read_ctr+uint64(frameheader_length))
+				}
+				break
+			} else {
+				data_read_ctr += uint64(curframe.Length) + uint64(frameheader_length)
+				//frames too short to hold the fields their flags announce are dropped
+				if split_err := split_frame_data(tag_ver, curframe, frdata); split_err != nil {
+					if err := problem(split_err); err != nil {
+						return ID3Tag{}, err
+					}
 				} else {
-					curframe.Data = frdata
-					data_read_ctr += curframe.Length + 10
-					rettag = append(rettag, *curframe)
-					//rettag[curframe.FrameID] = *curframe
+					frames_read++
+					if cfg.on_frame == nil {
+						rettag.Frames = append(rettag.Frames, *curframe)
+					} else if err := cfg.on_frame(*curframe); err != nil {
+						return rettag, err
+					}
+				}
+				if cfg.progress != nil {
+					bytes_read := tag_length
+					if data_read_ctr < uint64(tag_length) {
+						bytes_read = uint32(data_read_ctr)
+					}
+					cfg.progress(Progress{bytes_read, tag_length, frames_read})
 				}
 			}
 		}
 	}
 
+	//the footer repeats the header so there is nothing new to learn from it, but it must be consumed
+	//to leave rd positioned right after the tag, whether or not all of the tag could be parsed
+	if rettag.Footer && !truncated {
+		if _, footer_err := src.next(10); footer_err != nil {
+			if err := read_failed(footer_err); err != nil {
+				return ID3Tag{}, err
+			}
+		}
+	}
+
+	rettag.original, rettag.original_version = rettag.Frames, rettag.Version
 	return rettag, nil
 }
 
-//gets data from each of the frames referred to by a tag title
+// valid_frameid_bytes reports whether id consists of uppercase letters and digits, or with lenient set of
+// any letters and digits
+func valid_frameid_bytes(id []byte, lenient bool) bool {
+	for _, c := range id {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || lenient && c >= 'a' && c <= 'z') {
+			return false
+		}
+	}
+	return len(id) > 0
+}
+
+// ExtendedHeader holds the optional extended header of a tag. Update, CRC and restrictions may all
+// appear in v2.4 tags while v2.3 extended headers carry only the CRC and the padding size.
+type ExtendedHeader struct {
+	Update          bool //the tag is an update of a tag found earlier in the file or stream
+	HasCRC          bool
+	CRC             uint32
+	HasRestrictions bool
+	Restrictions    byte
+	PaddingSize     uint32
+}
+
+// read_extended_header returns the extended header along with the number of tag bytes it took up
+func read_extended_header(src tag_source, tag_ver byte) (*ExtendedHeader, uint32, error) {
+	exthdr := new(ExtendedHeader)
+	malformed := errors.New("Malformed extended header")
+
+	sizebuf, err := src.next(4)
+	if err != nil {
+		return nil, 0, malformed
+	}
+
+	if tag_ver == 3 {
+		//the v2.3 size excludes the size field itself and is 6 or 10 depending on the CRC flag
+		size, _ := convert_regular_int(sizebuf)
+		if size != 6 && size != 10 {
+			return nil, 0, malformed
+		}
+		buf, err := src.next(size)
+		if err != nil {
+			return nil, 0, malformed
+		}
+		exthdr.HasCRC = buf[0]&0x80 != 0
+		exthdr.PaddingSize, _ = convert_regular_int(buf[2:6])
+		if exthdr.HasCRC {
+			if size != 10 {
+				return nil, 0, malformed
+			}
+			exthdr.CRC, _ = convert_regular_int(buf[6:10])
+		}
+		return exthdr, size + 4, nil
+	}
+
+	//the v2.4 size is synchsafe and includes the size field
+	size, err := convert_synchsafe_int(sizebuf)
+	if err != nil || size < 6 {
+		return nil, 0, malformed
+	}
+	buf, err := src.next(size - 4)
+	if err != nil || buf[0] != 1 {
+		return nil, 0, malformed
+	}
+	flags := buf[1]
+	cursor := buf[2:len(buf)]
+	//every flag that is set is followed by a length byte and that many bytes of flag data, in flag order
+	flag_data := func(length byte) ([]byte, error) {
+		if len(cursor) < 1 || cursor[0] != length || len(cursor) < 1+int(length) {
+			return nil, malformed
+		}
+		data := cursor[1 : 1+length]
+		cursor = cursor[1+length : len(cursor)]
+		return data, nil
+	}
+	if flags&0x40 != 0 {
+		if _, err := flag_data(0); err != nil {
+			return nil, 0, err
+		}
+		exthdr.Update = true
+	}
+	if flags&0x20 != 0 {
+		data, err := flag_data(5)
+		if err != nil {
+			return nil, 0, err
+		}
+		//35 bit synchsafe integer of which only the low 32 bits can be set
+		exthdr.HasCRC = true
+		exthdr.CRC = uint32(data[0])<<28 | uint32(data[1])<<21 | uint32(data[2])<<14 | uint32(data[3])<<7 | uint32(data[4])
+	}
+	if flags&0x10 != 0 {
+		data, err := flag_data(1)
+		if err != nil {
+			return nil, 0, err
+		}
+		exthdr.HasRestrictions = true
+		exthdr.Restrictions = data[0]
+	}
+	return exthdr, size, nil
+}
+
+// ReadAllID3 reads every ID3v2 tag found back to back at the start of rd, as left behind by concatenated
+// streams or careless editing. An error is returned only if rd does not start with a tag; reading stops
+// quietly at the first bytes after a tag that do not start another one.
+func ReadAllID3(rd io.Reader, opts ...Option) ([]ID3Tag, error) {
+	first, err := ReadID3(rd, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tags := []ID3Tag{first}
+	for {
+		next, err := ReadID3(rd, opts...)
+		if err != nil {
+			break
+		}
+		tags = append(tags, next)
+	}
+	return tags, nil
+}
+
+// scan_remainder consumes the remaining length bytes of a tag, the first of which have already been read
+// into pending, and splits them into the run of zero padding following the last frame and whatever
+// could not be parsed. Bytes missing from a truncated input count as unparsed.
+func scan_remainder(src tag_source, pending []byte, length uint32) (padding, unparsed uint32, err error) {
+	in_padding := true
+	count := func(buf []byte) {
+		for _, b := range buf {
+			if in_padding && b == 0 {
+				padding++
+			} else {
+				in_padding = false
+			}
+		}
+	}
+
+	count(pending)
+	scanned := uint32(len(pending))
+	for scanned < length {
+		chunk := length - scanned
+		if chunk > 1<<16 {
+			chunk = 1 << 16
+		}
+		var buf []byte
+		if buf, err = src.next(chunk); err != nil {
+			break
+		}
+		count(buf)
+		scanned += chunk
+	}
+	return padding, length - padding, err
+}
+
+// GetTagData returns the Data of each frame with the given FrameID, leaving out frames that are
+// compressed, encrypted or unsynchronised. The data is copied so that changing it cannot corrupt the tag,
+// unless the tag was read with WithAliasedTagData.
 func (id3tag ID3Tag) GetTagData(frameid string) [][]byte {
+	ret := id3tag.tag_data(frameid)
+	for j, data := range ret {
+		ret[j] = id3tag.own_data(data)
+	}
+	return ret
+}
+
+// own_data returns a copy of data from the frames of the tag for the caller to keep, or data itself if the
+// tag was read with WithAliasedTagData
+func (id3tag ID3Tag) own_data(data []byte) []byte {
+	if id3tag.cfg.alias_tag_data {
+		return data
+	}
+	return append([]byte(nil), data...)
+}
+
+// tag_data returns the Data of the frames GetTagData returns without copying it
+func (id3tag ID3Tag) tag_data(frameid string) [][]byte {
 	ret := make([][]byte, 0)
-	for _, id3frame := range id3tag {
+	for _, id3frame := range id3tag.Frames {
 		if id3frame.FrameID == frameid {
 			if id3frame.Compression || id3frame.Encryption || id3frame.Unsynchronisation {
 				//tk code to handle compression, unsynchronization
@@ -206,12 +1017,60 @@ func (id3tag ID3Tag) GetTagData(frameid string) [][]byte {
 }
 
 func (id3tag ID3Tag) GetTextFrameData(frameid string) (string, error) {
-	framedatas := id3tag.GetTagData(frameid)
+	framedatas := id3tag.tag_data(frameid)
 	if len(framedatas) > 0 {
-		text, err := decodetext(framedatas[0][0], framedatas[0][1:len(framedatas[0])])
-		return text, err
+		if len(framedatas[0]) == 0 {
+			return "", errors.New("Frame " + frameid + " is empty")
+		}
+		data := framedatas[0]
+		text, err := id3tag.cached_text(data, false, func() ([]string, error) {
+			txt, err := id3tag.decodetext(data[0], data[1:len(data)])
+			return []string{txt}, err
+		})
+		return text[0], err
+	}
+	return "", errors.New("No such frame " + frameid + " found in the taglist")
+}
+
+// AllText decodes every text frame of the tag in one pass, mapping each FrameID to its values in tag
+// order. v2.4 frames holding several null separated values yield one entry per value. TXXX frames are
+// keyed "TXXX:" followed by their description. Frames that fail to decode are left out.
+func (id3tag ID3Tag) AllText() map[string][]string {
+	ret := make(map[string][]string)
+	for _, frame := range id3tag.Frames {
+		if !strings.HasPrefix(frame.FrameID, "T") || len(frame.Data) == 0 {
+			continue
+		}
+		if frame.Compression || frame.Encryption || frame.Unsynchronisation {
+			continue
+		}
+		values, err := id3tag.decodetext_values(frame.Data[0], frame.Data[1:len(frame.Data)])
+		if err != nil || len(values) == 0 {
+			continue
+		}
+		key := frame.FrameID
+		if frame.FrameID == "TXXX" {
+			key = "TXXX:" + values[0]
+			values = values[1:len(values)]
+		}
+		ret[key] = append(ret[key], values...)
+	}
+	return ret
+}
+
+// get_text_values decodes all the values of the first frameid frame
+func (id3tag ID3Tag) get_text_values(frameid string) ([]string, error) {
+	framedatas := id3tag.tag_data(frameid)
+	if len(framedatas) == 0 {
+		return nil, errors.New("No such frame " + frameid + " found in the taglist")
+	}
+	if len(framedatas[0]) == 0 {
+		return nil, errors.New("Frame " + frameid + " is empty")
 	}
-	return "", errors.New(fmt.Sprintf("No such frame %v found in the taglist", frameid))
+	data := framedatas[0]
+	return id3tag.cached_text(data, true, func() ([]string, error) {
+		return id3tag.decodetext_values(data[0], data[1:len(data)])
+	})
 }
 
 func (id3tag ID3Tag) GetTitle() (string, error) {
@@ -234,21 +1093,124 @@ func (id3tag ID3Tag) GetComposer() (string, error) {
 	return txt, err
 }
 
-func (id3tag ID3Tag) GetCoverPic() ([]byte, error) {
-	framedatas := id3tag.GetTagData("APIC")
-	for _, framedata := range framedatas {
-		text_encoding := framedata[0]
-		if mime_type_end := bytes.IndexByte(framedata[1:len(framedata)], 0); mime_type_end != -1 && mime_type_end+3 < len(framedata) {
-			if pictype := framedata[mime_type_end+2]; pictype == 3 || pictype == 4 {
-				if text_encoding == 0 || text_encoding == 3 {
-					desc_end := bytes.IndexByte(framedata[mime_type_end+3:len(framedata)], 0)
-					return framedata[desc_end+1 : len(framedata)], nil
-				} else if text_encoding == 1 || text_encoding == 2 {
-					desc_end := bytes.Index(framedata[mime_type_end+3:len(framedata)], []byte{0, 0})
-					return framedata[desc_end+2 : len(framedata)], nil
-				}
+// GetContentGroup returns the TIT1 content group, which classical releases use for the work a piece
+// belongs to, such as "Piano Concerto No. 1".
+func (id3tag ID3Tag) GetContentGroup() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TIT1")
+	return txt, err
+}
+
+// GetSubtitle returns the TIT3 subtitle, which refines the title, such as a movement name.
+func (id3tag ID3Tag) GetSubtitle() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TIT3")
+	return txt, err
+}
+
+// GetSetSubtitle returns the v2.4 TSST set subtitle: the title of the disc of a multi-disc set.
+func (id3tag ID3Tag) GetSetSubtitle() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TSST")
+	return txt, err
+}
+
+func (id3tag ID3Tag) GetOriginalAlbum() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TOAL")
+	return txt, err
+}
+
+func (id3tag ID3Tag) GetOriginalArtist() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TOPE")
+	return txt, err
+}
+
+func (id3tag ID3Tag) GetOriginalLyricist() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TOLY")
+	return txt, err
+}
+
+// GetOriginalReleaseDate returns the original release date as stored: a v2.4 TDOR timestamp such as
+// "1975-10-31", or a v2.3 TORY year. Both frames are looked for whatever the tag version since taggers
+// converting between versions often leave the other one behind.
+func (id3tag ID3Tag) GetOriginalReleaseDate() (string, error) {
+	if txt, err := id3tag.GetTextFrameData("TDOR"); err == nil {
+		return txt, nil
+	}
+	txt, err := id3tag.GetTextFrameData("TORY")
+	return txt, err
+}
+
+// GetOriginalReleaseYear returns the year of the original release from TDOR or TORY.
+func (id3tag ID3Tag) GetOriginalReleaseYear() (int, error) {
+	txt, err := id3tag.GetOriginalReleaseDate()
+	if err != nil {
+		return 0, err
+	}
+	if len(txt) < 4 {
+		return 0, errors.New("Original release date " + strconv.Quote(txt) + " has no year")
+	}
+	year, err := strconv.Atoi(txt[0:4])
+	if err != nil {
+		return 0, errors.New("Original release date " + strconv.Quote(txt) + " has no year")
+	}
+	return year, nil
+}
+
+// DefaultCoverPreference is the order in which GetCoverPic picks a picture when given no preference
+var DefaultCoverPreference = []PictureType{FrontCover, OtherPicture, BackCover}
+
+// GetCoverPic returns the image data of the attached picture whose type comes first in preference, or
+// DefaultCoverPreference when no preference is given. If no picture has any of the preferred types, the
+// first picture in the tag is returned. Among pictures of the same type the first one wins. The image data
+// is copied as GetTagData copies frame data.
+func (id3tag ID3Tag) GetCoverPic(preference ...PictureType) ([]byte, error) {
+	if len(preference) == 0 {
+		preference = DefaultCoverPreference
+	}
+	pics, _ := id3tag.pictures()
+	for _, want := range preference {
+		for _, pic := range pics {
+			if pic.Type == want {
+				return id3tag.own_data(pic.Data), nil
 			}
 		}
 	}
+	if len(pics) > 0 {
+		return id3tag.own_data(pics[0].Data), nil
+	}
 	return []byte{}, errors.New("No cover pic found")
 }
+
+// wrapped_error is an error with a message of its own wrapping err, as fmt.Errorf makes with %w. The parser
+// core does without fmt and regexp so that it stays small enough for TinyGo and embedded targets.
+type wrapped_error struct {
+	msg string
+	err error
+}
+
+func wrap_error(msg string, err error) error {
+	return &wrapped_error{msg, err}
+}
+
+func (err *wrapped_error) Error() string {
+	return err.msg + ": " + err.err.Error()
+}
+
+func (err *wrapped_error) Unwrap() error {
+	return err.err
+}
+
+func utoa(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+// hex_bytes formats buf as fmt does with "% X"
+func hex_bytes(buf []byte) string {
+	const digits = "0123456789ABCDEF"
+	ret := make([]byte, 0, 3*len(buf))
+	for j, b := range buf {
+		if j > 0 {
+			ret = append(ret, ' ')
+		}
+		ret = append(ret, digits[b>>4], digits[b&0x0F])
+	}
+	return string(ret)
+}
@@ -1,7 +1,13 @@
+//go:build !id3v2core
+
 package id3v2reader
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -50,3 +56,482 @@ func Test(t *testing.T) {
 		}
 	}
 }
+
+func synchsafe(n uint32) []byte {
+	return []byte{byte(n>>21) & 0x7F, byte(n>>14) & 0x7F, byte(n>>7) & 0x7F, byte(n) & 0x7F}
+}
+
+// make_frame builds a raw frame for tag version ver with the given format flags byte
+func make_frame(ver byte, frameid string, format_flags byte, data []byte) []byte {
+	frame := []byte(frameid)
+	if ver == 2 {
+		//v2.2 frame headers have a three byte size and no flags
+		size := uint32(len(data))
+		return append(append(frame, byte(size>>16), byte(size>>8), byte(size)), data...)
+	}
+	if ver == 3 {
+		size := uint32(len(data))
+		frame = append(frame, byte(size>>24), byte(size>>16), byte(size>>8), byte(size))
+	} else {
+		frame = append(frame, synchsafe(uint32(len(data)))...)
+	}
+	frame = append(frame, 0, format_flags)
+	return append(frame, data...)
+}
+
+// make_tag builds a raw tag of version ver holding frames
+func make_tag(ver byte, frames ...[]byte) []byte {
+	body := make([]byte, 0)
+	for _, frame := range frames {
+		body = append(body, frame...)
+	}
+	tag := append([]byte{'I', 'D', '3', ver, 0, 0}, synchsafe(uint32(len(body)))...)
+	return append(tag, body...)
+}
+
+func TestSizeConversions(t *testing.T) {
+	synchsafe_cases := map[uint32][]byte{
+		0:          {0, 0, 0, 0},
+		0x7F:       {0, 0, 0, 0x7F},
+		0x80:       {0, 0, 1, 0},
+		1<<24 - 1:  {0x07, 0x7F, 0x7F, 0x7F},
+		1 << 24:    {0x08, 0, 0, 0},
+		0x0FFFFFFF: {0x7F, 0x7F, 0x7F, 0x7F},
+	}
+	for want, buf := range synchsafe_cases {
+		if got, err := convert_synchsafe_int(buf); err != nil || got != want {
+			t.Errorf("convert_synchsafe_int(%v) = %v, %v; want %v", buf, got, err, want)
+		}
+	}
+	if _, err := convert_synchsafe_int([]byte{0x80, 0, 0, 0}); err == nil {
+		t.Errorf("Expected an error converting a synchsafe int with the high bit set")
+	}
+
+	regular_cases := map[uint32][]byte{
+		0xFF:       {0, 0, 0, 0xFF},
+		0x1000:     {0, 0, 0x10, 0},
+		1 << 24:    {1, 0, 0, 0},
+		0xFFFFFFFF: {0xFF, 0xFF, 0xFF, 0xFF},
+	}
+	for want, buf := range regular_cases {
+		if got, err := convert_regular_int(buf); err != nil || got != want {
+			t.Errorf("convert_regular_int(%v) = %v, %v; want %v", buf, got, err, want)
+		}
+	}
+}
+
+func TestLargeFrames(t *testing.T) {
+	payload := bytes.Repeat([]byte{0xAB}, 1<<24+1)
+	for _, ver := range []byte{3, 4} {
+		raw := make_tag(ver, make_frame(ver, "APIC", 0, payload), make_frame(ver, "TIT2", 0, []byte("\x00Title")))
+		for _, read := range []func([]byte) (ID3Tag, error){
+			func(buf []byte) (ID3Tag, error) { return ReadID3(bytes.NewReader(buf)) },
+			func(buf []byte) (ID3Tag, error) { return ReadID3Bytes(buf) },
+		} {
+			id3tag, err := read(raw)
+			if err != nil {
+				t.Fatalf("v2.%v: error in reading tag: %v", ver, err)
+			}
+			if pics := id3tag.GetTagData("APIC"); len(pics) != 1 || len(pics[0]) != len(payload) {
+				t.Errorf("v2.%v: expected one APIC frame of %v bytes", ver, len(payload))
+			}
+			if title, err := id3tag.GetTitle(); err != nil || title != "Title" {
+				t.Errorf("v2.%v: expected the frame after a >16MB frame to be read, got %q, %v", ver, title, err)
+			}
+		}
+	}
+}
+
+func TestFramePrefixFields(t *testing.T) {
+	//v2.4 grouping + data length indicator: group symbol, then the synchsafe decoded size
+	v24 := append([]byte{0x05}, synchsafe(1000)...)
+	v24 = append(v24, "abc"...)
+	//v2.3 compression + grouping: decompressed size, then the group symbol
+	v23 := []byte{0, 0, 0x03, 0xE8, 0x05, 'a', 'b', 'c'}
+
+	cases := []struct {
+		ver   byte
+		flags byte
+		data  []byte
+	}{
+		{4, 0x41, v24},
+		{3, 0xA0, v23},
+	}
+	for _, c := range cases {
+		id3tag, err := ReadID3Bytes(make_tag(c.ver, make_frame(c.ver, "GEOB", c.flags, c.data)))
+		if err != nil {
+			t.Fatalf("v2.%v: error in reading tag: %v", c.ver, err)
+		}
+		frame := id3tag.Frames[len(id3tag.Frames)-1]
+		if frame.FrameID != "GEOB" || !frame.Grouping || frame.GroupSymbol != 5 || frame.DataLength != 1000 || string(frame.Data) != "abc" {
+			t.Errorf("v2.%v: unexpected frame %+v", c.ver, frame)
+		}
+		if frame.Length != uint32(len(c.data)) {
+			t.Errorf("v2.%v: Length %v should be the declared frame size %v", c.ver, frame.Length, len(c.data))
+		}
+	}
+
+	//a data length indicator that does not fit in the frame drops just that frame
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "GEOB", 0x01, []byte{0}), make_frame(4, "TIT2", 0, []byte("\x00Title"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if len(id3tag.GetTagData("GEOB")) != 0 {
+		t.Errorf("Expected the truncated GEOB frame to be dropped")
+	}
+	if title, _ := id3tag.GetTitle(); title != "Title" {
+		t.Errorf("Expected the frame after the dropped frame to be read, got %q", title)
+	}
+}
+
+func TestRemainder(t *testing.T) {
+	title := make_frame(4, "TIT2", 0, []byte("\x00Title"))
+	overstated := make_tag(4, title, make([]byte, 5), []byte{0xFF, 0xFB, 0x90, 0x64}, make([]byte, 11))
+	truncated := make_tag(4, title, make([]byte, 30))
+	truncated = truncated[0 : len(truncated)-10]
+
+	cases := []struct {
+		name              string
+		raw               []byte
+		padding, unparsed uint32
+	}{
+		{"padding", make_tag(4, title, make([]byte, 20)), 20, 0},
+		{"short padding", make_tag(4, title, make([]byte, 4)), 4, 0},
+		{"garbage after padding", overstated, 5, 15},
+		{"truncated padding", truncated, 10, 20}, //the short final chunk cannot be read so it counts as unparsed
+		{"no padding", make_tag(4, title), 0, 0},
+	}
+	for _, c := range cases {
+		for _, read := range []func([]byte) (ID3Tag, error){
+			func(buf []byte) (ID3Tag, error) { return ReadID3(bytes.NewReader(buf)) },
+			func(buf []byte) (ID3Tag, error) { return ReadID3Bytes(buf) },
+		} {
+			id3tag, err := read(c.raw)
+			if err != nil {
+				t.Fatalf("%v: error in reading tag: %v", c.name, err)
+			}
+			if id3tag.PaddingBytes != c.padding || id3tag.UnparsedBytes != c.unparsed {
+				t.Errorf("%v: got padding %v unparsed %v, want %v and %v", c.name, id3tag.PaddingBytes, id3tag.UnparsedBytes, c.padding, c.unparsed)
+			}
+			if title, _ := id3tag.GetTitle(); title != "Title" {
+				t.Errorf("%v: unexpected title %q", c.name, title)
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		name    string
+		raw     []byte
+		padding uint32
+	}{
+		{"empty tag", make_tag(4), 0},
+		{"padding only", make_tag(3, make([]byte, 64)), 64},
+		{"short padding only", make_tag(2, make([]byte, 3)), 3},
+	} {
+		id3tag, err := ReadID3Bytes(c.raw)
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", c.name, err)
+		}
+		if len(id3tag.Frames) != 0 || id3tag.PaddingBytes != c.padding || id3tag.UnparsedBytes != 0 {
+			t.Errorf("%v: got %v frames, padding %v unparsed %v", c.name, len(id3tag.Frames), id3tag.PaddingBytes, id3tag.UnparsedBytes)
+		}
+		if _, err := id3tag.GetTitle(); err == nil {
+			t.Errorf("%v: expected no title", c.name)
+		}
+	}
+
+	fil, err := os.Open("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fil.Close()
+	if id3tag, err := ReadID3(fil); err != nil {
+		t.Errorf("Error in reading tag: %v", err)
+	} else if id3tag.Version != Version23 || id3tag.Size != 6134 || id3tag.PaddingBytes != 792 || id3tag.UnparsedBytes != 0 {
+		t.Errorf("Unexpected tag header report: version %v size %v padding %v unparsed %v", id3tag.Version, id3tag.Size, id3tag.PaddingBytes, id3tag.UnparsedBytes)
+	}
+}
+
+func TestReadAllID3(t *testing.T) {
+	first := make_tag(3, make_frame(3, "TIT2", 0, []byte("\x00First")))
+	second := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x00Second")), make([]byte, 8))
+	//give the second tag a footer
+	second[5] |= 0x10
+	second = append(second, '3', 'D', 'I', 4, 0, 0x10)
+	second = append(second, second[6:10]...)
+	third := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x00Third")))
+	audio := []byte{0xFF, 0xFB, 0x90, 0x64}
+
+	stream := make([]byte, 0)
+	for _, part := range [][]byte{first, second, third, audio} {
+		stream = append(stream, part...)
+	}
+	rd := bytes.NewReader(stream)
+	tags, err := ReadAllID3(rd)
+	if err != nil {
+		t.Fatalf("Error in reading tags: %v", err)
+	}
+	want := []string{"First", "Second", "Third"}
+	if len(tags) != len(want) {
+		t.Fatalf("Expected %v tags, got %v", len(want), len(tags))
+	}
+	for j, id3tag := range tags {
+		if title, _ := id3tag.GetTitle(); title != want[j] {
+			t.Errorf("Tag %v: expected title %q, got %q", j, want[j], title)
+		}
+	}
+	if !tags[1].Footer || tags[1].PaddingBytes != 8 {
+		t.Errorf("Expected the second tag to have a footer and 8 bytes of padding, got %+v", tags[1])
+	}
+
+	if _, err := ReadAllID3(bytes.NewReader(audio)); err == nil {
+		t.Errorf("Expected an error reading a stream without a tag")
+	}
+
+	//a tag whose last bytes cannot be parsed still has its footer consumed
+	damaged := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x00Damaged")), []byte("junk"))
+	damaged[5] |= 0x10
+	damaged = append(damaged, '3', 'D', 'I', 4, 0, 0x10)
+	damaged = append(damaged, damaged[6:10]...)
+	tags, err = ReadAllID3(bytes.NewReader(append(damaged, third...)))
+	if err != nil || len(tags) != 2 || tags[0].UnparsedBytes != 4 {
+		t.Fatalf("Expected a damaged tag and the tag following its footer, got %v tags, %v", len(tags), err)
+	}
+	if title, _ := tags[1].GetTitle(); title != "Third" {
+		t.Errorf("Expected the tag after the footer to be read, got %q", title)
+	}
+}
+
+func TestRawFlags(t *testing.T) {
+	//status byte with tag alter preservation and a reserved bit set, format byte with a reserved bit set
+	frame := make_frame(4, "TIT2", 0x80, []byte("\x00Title"))
+	frame[8] = 0x41
+	id3tag, err := ReadID3Bytes(make_tag(4, frame))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	read := id3tag.Frames[len(id3tag.Frames)-1]
+	if read.StatusFlags != 0x41 || read.FormatFlags != 0x80 {
+		t.Errorf("Expected raw flags 0x41 0x80, got %#x %#x", read.StatusFlags, read.FormatFlags)
+	}
+	if read.Compression || read.Encryption || read.Grouping || read.Unsynchronisation || read.Data_Length_Indicator {
+		t.Errorf("Reserved flag bits should not decode to any known flag: %+v", read)
+	}
+}
+
+func TestOriginalRelease(t *testing.T) {
+	v23, err := ReadID3Bytes(make_tag(3,
+		make_frame(3, "TOAL", 0, []byte("\x00Night at the Opera")),
+		make_frame(3, "TOPE", 0, []byte("\x00Queen")),
+		make_frame(3, "TOLY", 0, []byte("\x00Freddie Mercury")),
+		make_frame(3, "TORY", 0, []byte("\x001975")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	v24, err := ReadID3Bytes(make_tag(4, make_frame(4, "TDOR", 0, []byte("\x031975-10-31"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	if album, _ := v23.GetOriginalAlbum(); album != "Night at the Opera" {
+		t.Errorf("Unexpected original album %q", album)
+	}
+	if artist, _ := v23.GetOriginalArtist(); artist != "Queen" {
+		t.Errorf("Unexpected original artist %q", artist)
+	}
+	if lyricist, _ := v23.GetOriginalLyricist(); lyricist != "Freddie Mercury" {
+		t.Errorf("Unexpected original lyricist %q", lyricist)
+	}
+	for _, id3tag := range []ID3Tag{v23, v24} {
+		if year, err := id3tag.GetOriginalReleaseYear(); err != nil || year != 1975 {
+			t.Errorf("v%v: expected original release year 1975, got %v, %v", id3tag.Version, year, err)
+		}
+	}
+	if date, _ := v24.GetOriginalReleaseDate(); date != "1975-10-31" {
+		t.Errorf("Unexpected original release date %q", date)
+	}
+	if _, err := v24.GetOriginalAlbum(); err == nil {
+		t.Errorf("Expected an error for a missing original album")
+	}
+}
+
+func TestWorkStructure(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT1", 0, []byte("\x03Piano Concerto No. 1")),
+		make_frame(4, "TIT2", 0, []byte("\x03Piano Concerto No. 1: I. Allegro maestoso")),
+		make_frame(4, "TIT3", 0, []byte("\x03I. Allegro maestoso")),
+		make_frame(4, "TSST", 0, []byte("\x03The Chopin Concertos")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	getters := map[string]func() (string, error){
+		"Piano Concerto No. 1": id3tag.GetContentGroup,
+		"I. Allegro maestoso":  id3tag.GetSubtitle,
+		"The Chopin Concertos": id3tag.GetSetSubtitle,
+	}
+	for want, get := range getters {
+		if got, err := get(); err != nil || got != want {
+			t.Errorf("Expected %q, got %q, %v", want, got, err)
+		}
+	}
+}
+
+func TestRawTag(t *testing.T) {
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		data, err := os.ReadFile(filname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd := bytes.NewReader(data)
+		raw, err := RawTag(rd)
+		if err != nil {
+			t.Fatalf("%v: error in reading raw tag: %v", filname, err)
+		}
+		id3tag, _ := ReadID3Bytes(data)
+		if !bytes.Equal(raw, data[0:10+id3tag.Size]) || rd.Len() != len(data)-len(raw) {
+			t.Errorf("%v: raw tag differs from the tag in the file", filname)
+		}
+	}
+
+	//unsupported features do not matter as the tag is not parsed
+	unsynced := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")))
+	unsynced[5] = 0x80
+	if raw, err := RawTag(bytes.NewReader(unsynced)); err != nil || !bytes.Equal(raw, unsynced) {
+		t.Errorf("Unexpected raw tag %q, %v", raw, err)
+	}
+	if _, err := RawTag(bytes.NewReader(unsynced[0 : len(unsynced)-1])); err == nil {
+		t.Errorf("Expected an error for a truncated tag")
+	}
+	if _, err := RawTag(bytes.NewReader([]byte("no tag here"))); err == nil {
+		t.Errorf("Expected an error for a missing tag")
+	}
+}
+
+func TestGetArtists(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "TPE1", 0, []byte("\x03Alpha; Beta\x00Gamma FEAT. Delta\x00 "))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if artists := id3tag.GetArtists(); !reflect.DeepEqual(artists, []string{"Alpha; Beta", "Gamma FEAT. Delta"}) {
+		t.Errorf("Unexpected artists without separators %q", artists)
+	}
+	if artists := id3tag.GetArtists(CommonArtistSeparators...); !reflect.DeepEqual(artists, []string{"Alpha", "Beta", "Gamma", "Delta"}) {
+		t.Errorf("Unexpected artists with separators %q", artists)
+	}
+	if artists := (ID3Tag{}).GetArtists(); artists != nil {
+		t.Errorf("Expected no artists without a TPE1 frame, got %q", artists)
+	}
+}
+
+func TestReadID3Func(t *testing.T) {
+	fil, err := os.Open("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fil.Close()
+
+	ids := make([]string, 0)
+	if err := ReadID3Func(fil, func(frame ID3Frame) error {
+		ids = append(ids, frame.FrameID)
+		return nil
+	}); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if len(ids) != 11 || ids[0] == "" {
+		t.Errorf("Expected the 11 frames of the tag, got %v", ids)
+	}
+
+	fil.Seek(0, io.SeekStart)
+	var title string
+	if err := ReadID3Func(fil, func(frame ID3Frame) error {
+		if frame.FrameID != "TIT2" {
+			return nil
+		}
+		title, _ = decodetext(frame.Data[0], frame.Data[1:])
+		return io.EOF
+	}); err != nil || title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" {
+		t.Errorf("Expected to stop at the title, got %q, %v", title, err)
+	}
+
+	stop := errors.New("stop")
+	if err := ReadID3Func(bytes.NewReader(make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03T")))), func(ID3Frame) error {
+		return stop
+	}); err != stop {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+}
+
+type failing_reader struct{ err error }
+
+func (fr failing_reader) Read(p []byte) (int, error) {
+	return 0, fr.err
+}
+
+func TestErrNoTag(t *testing.T) {
+	if _, err := ReadID3(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEfmt "))); err != ErrNoTag {
+		t.Errorf("Expected ErrNoTag for a file without a tag, got %v", err)
+	}
+	if _, err := ReadID3Bytes([]byte("ID3\x04")); err == ErrNoTag || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected a short read for a truncated header, got %v", err)
+	}
+	if _, err := ReadID3Bytes([]byte("ID3\x05\x00\x00\x00\x00\x00\x00")); err == nil || err == ErrNoTag {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+	broken := errors.New("broken")
+	if _, err := ReadID3(failing_reader{broken}); !errors.Is(err, broken) {
+		t.Errorf("Expected the read error to be wrapped, got %v", err)
+	}
+	if _, err := RawTag(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEfmt "))); err != ErrNoTag {
+		t.Errorf("Expected RawTag to return ErrNoTag, got %v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	frames := make([][]byte, 0)
+	for _, id := range []string{"TIT2", "TPE1", "TALB", "TCON", "TYER", "TRCK", "COMM"} {
+		frames = append(frames, make_frame(4, id, 0, []byte("\x03value of "+id+" long enough")))
+	}
+	tag := make_tag(4, frames...)
+	disk := errors.New("disk on fire")
+
+	for _, opts := range [][]Option{nil, {WithSingleRead(true)}, {OnlyFrames("TIT2")}} {
+		failing := io.MultiReader(bytes.NewReader(tag[0:150]), failing_reader{disk})
+		if id3tag, err := ReadID3(failing, opts...); !errors.Is(err, disk) {
+			t.Errorf("%v: expected the read error, got %v frames, %v", len(opts), len(id3tag.Frames), err)
+		}
+
+		id3tag, err := ReadID3(bytes.NewReader(tag[0:150]), opts...)
+		if err != nil || id3tag.UnparsedBytes == 0 || len(id3tag.Warnings) != 1 {
+			t.Errorf("%v: expected a truncation warning, got %v, %v", len(opts), id3tag.Warnings, err)
+		}
+		if _, err := ReadID3(bytes.NewReader(tag[0:150]), append(opts, WithStrictParsing(true))...); err == nil {
+			t.Errorf("%v: expected an error for a truncated tag in strict mode", len(opts))
+		}
+	}
+}
+
+func TestHeaderValidation(t *testing.T) {
+	raw := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")))
+	header := func(revision, flags byte) []byte {
+		tag := append([]byte(nil), raw...)
+		tag[4], tag[5] = revision, flags
+		return tag
+	}
+
+	id3tag, err := ReadID3Bytes(header(7, 0))
+	if err != nil || id3tag.Revision != 7 {
+		t.Errorf("Expected a future revision to be read, got %v, %v", id3tag.Revision, err)
+	}
+	if _, err := ReadID3Bytes(header(0xFF, 0)); err == nil {
+		t.Errorf("Expected a 0xFF revision to be rejected")
+	}
+	id3tag, err = ReadID3Bytes(header(0, 0x01))
+	if err != nil || len(id3tag.Warnings) != 1 {
+		t.Errorf("Expected a warning for an undefined flag, got %q, %v", id3tag.Warnings, err)
+	}
+	if _, err := ReadID3Bytes(header(0, 0x01), WithStrictParsing(true)); err == nil {
+		t.Errorf("Expected strict parsing to reject an undefined flag")
+	}
+}
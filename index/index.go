@@ -0,0 +1,314 @@
+// Package index keeps a SQLite index of the tags of a music library, updated incrementally so that only
+// files whose size or modification time changed since the last update are read again. The caller opens the
+// database with the SQLite driver of their choice, such as github.com/mattn/go-sqlite3 or modernc.org/sqlite,
+// which keeps cgo and the choice of driver out of this package.
+//
+// The index has three tables: files, with one row per file holding the commonly displayed fields or the
+// error reading it, tags, with one row per field as flattened by ID3Tag.Fields, and pictures, with one row
+// per attached picture less its image data.
+package index
+
+import (
+	"database/sql"
+	"errors"
+	"io/fs"
+	"strings"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+// FieldSeparator joins the values of fields with several values in the tags table
+const FieldSeparator = "; "
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS files (
+		id INTEGER PRIMARY KEY,
+		path TEXT NOT NULL UNIQUE,
+		size INTEGER NOT NULL,
+		mtime INTEGER NOT NULL,
+		version TEXT,
+		title TEXT,
+		artist TEXT,
+		album TEXT,
+		album_artist TEXT,
+		error TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS tags (
+		file_id INTEGER NOT NULL REFERENCES files(id),
+		key TEXT NOT NULL,
+		value TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS tags_key ON tags(key, value)`,
+	`CREATE INDEX IF NOT EXISTS tags_file ON tags(file_id)`,
+	`CREATE TABLE IF NOT EXISTS pictures (
+		file_id INTEGER NOT NULL REFERENCES files(id),
+		type TEXT NOT NULL,
+		mime_type TEXT NOT NULL,
+		description TEXT NOT NULL,
+		size INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS pictures_file ON pictures(file_id)`,
+}
+
+// An Index is a library index stored in a SQLite database.
+type Index struct {
+	db *sql.DB
+}
+
+// Open returns the index kept in db, creating its tables if they do not exist yet
+func Open(db *sql.DB) (*Index, error) {
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+	return &Index{db}, nil
+}
+
+// Stats counts what an Update did. Failed files are counted as added or updated as well, since they are
+// indexed with their error.
+type Stats struct {
+	Added     int
+	Updated   int
+	Removed   int
+	Unchanged int
+	Failed    int
+}
+
+type file_state struct {
+	id    int64
+	size  int64
+	mtime int64
+}
+
+// Update brings the index up to date with the audio files in the tree rooted at root in fsys, which are
+// found and read as id3v2reader.Scan finds and reads them. Files that are new or whose size or modification time changed are
+// read using up to workers goroutines, and files that are gone are removed from the index. Paths are
+// stored as fsys names, so an index should always be updated with the same fsys.
+func (ix *Index) Update(fsys fs.FS, root string, workers int, opts ...id3v2reader.Option) (Stats, error) {
+	var stats Stats
+	indexed, err := ix.load()
+	if err != nil {
+		return stats, err
+	}
+
+	changed := make([]string, 0)
+	current := make(map[string]file_state)
+	err = fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() || !id3v2reader.HasFormatExtension(name) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		state := file_state{size: info.Size(), mtime: info.ModTime().UnixNano()}
+		current[name] = state
+		if old, ok := indexed[name]; ok && old.size == state.size && old.mtime == state.mtime {
+			stats.Unchanged++
+		} else {
+			changed = append(changed, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	results, _ := id3v2reader.ExtractMediaFS(fsys, changed, workers, opts...)
+
+	tx, err := ix.db.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Rollback() //does nothing once committed
+	for _, result := range results {
+		old, existed := indexed[result.Path]
+		if existed {
+			stats.Updated++
+			if err := remove_file(tx, old.id, false); err != nil {
+				return stats, err
+			}
+		} else {
+			stats.Added++
+		}
+		if result.Err != nil {
+			stats.Failed++
+		}
+		if err := store_file(tx, result, current[result.Path], old.id, existed); err != nil {
+			return stats, err
+		}
+	}
+	for name, old := range indexed {
+		if _, ok := current[name]; !ok {
+			stats.Removed++
+			if err := remove_file(tx, old.id, true); err != nil {
+				return stats, err
+			}
+		}
+	}
+	return stats, tx.Commit()
+}
+
+// Query returns the indexed files that satisfy q, in order of path. The Results are rebuilt from the
+// index: their Tag holds the text frames of the tags table, with values split at FieldSeparator, and
+// their Metadata is what those frames yield with the title, artist, album and album artist of the files
+// table. Fields of other frames and the duration are not indexed, so comparisons on them fail. Files that
+// failed to read have their error as Err.
+func (ix *Index) Query(q *id3v2reader.Query) ([]id3v2reader.Result, error) {
+	results, err := ix.results()
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(results), nil
+}
+
+// results rebuilds the Result of every indexed file, in order of path
+func (ix *Index) results() ([]id3v2reader.Result, error) {
+	texts := make(map[int64]map[string]string)
+	rows, err := ix.db.Query(`SELECT file_id, key, value FROM tags`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var key, value string
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return nil, err
+		}
+		if texts[id] == nil {
+			texts[id] = make(map[string]string)
+		}
+		texts[id][key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = ix.db.Query(`SELECT id, path, version, title, artist, album, album_artist, error FROM files ORDER BY path`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results := make([]id3v2reader.Result, 0)
+	for rows.Next() {
+		var id int64
+		var result id3v2reader.Result
+		var version, title, artist, album, album_artist, errtext sql.NullString
+		if err := rows.Scan(&id, &result.Path, &version, &title, &artist, &album, &album_artist, &errtext); err != nil {
+			return nil, err
+		}
+		if errtext.Valid {
+			result.Err = errors.New(errtext.String)
+		} else {
+			result.Tag = text_tag(version.String, texts[id])
+			result.Metadata = result.Tag.GetMetadata()
+			md := &result.Metadata
+			md.Title, md.Artist, md.Album, md.AlbumArtist = title.String, artist.String, album.String, album_artist.String
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// text_tag returns a tag of the given version, or v2.4 if it is not one, holding the text frames among
+// fields as stored in the tags table
+func text_tag(version string, fields map[string]string) id3v2reader.ID3Tag {
+	id3tag := id3v2reader.ID3Tag{Version: id3v2reader.Version24}
+	for _, ver := range []id3v2reader.Version{id3v2reader.Version22, id3v2reader.Version23} {
+		if version == ver.String() {
+			id3tag.Version = ver
+		}
+	}
+	for key, value := range fields {
+		values := strings.Split(value, FieldSeparator)
+		switch {
+		case strings.HasPrefix(key, "TXXX:"):
+			id3tag = id3tag.SetUserText(strings.TrimPrefix(key, "TXXX:"), values...)
+		case strings.HasPrefix(key, "T") && key != "TXXX" && key != "TXX" && !strings.Contains(key, ":"):
+			id3tag = id3tag.SetText(key, values...)
+		}
+	}
+	return id3tag
+}
+
+func (ix *Index) load() (map[string]file_state, error) {
+	rows, err := ix.db.Query(`SELECT id, path, size, mtime FROM files`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]file_state)
+	for rows.Next() {
+		var name string
+		var state file_state
+		if err := rows.Scan(&state.id, &name, &state.size, &state.mtime); err != nil {
+			return nil, err
+		}
+		ret[name] = state
+	}
+	return ret, rows.Err()
+}
+
+// remove_file deletes the tags and pictures of a file, and the file itself if entirely is set
+func remove_file(tx *sql.Tx, id int64, entirely bool) error {
+	stmts := []string{`DELETE FROM tags WHERE file_id = ?`, `DELETE FROM pictures WHERE file_id = ?`}
+	if entirely {
+		stmts = append(stmts, `DELETE FROM files WHERE id = ?`)
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// store_file writes the row of a file, updating row id if exists is set, along with its tags and pictures
+func store_file(tx *sql.Tx, result id3v2reader.Result, state file_state, id int64, exists bool) error {
+	var version, errtext sql.NullString
+	if result.Err != nil {
+		errtext = sql.NullString{String: result.Err.Error(), Valid: true}
+	} else if result.Tag.Version != 0 {
+		version = sql.NullString{String: result.Tag.Version.String(), Valid: true}
+	}
+	md := result.Metadata
+	args := []interface{}{result.Path, state.size, state.mtime, version, md.Title, md.Artist, md.Album, md.AlbumArtist, errtext}
+	if exists {
+		_, err := tx.Exec(`UPDATE files SET path = ?, size = ?, mtime = ?, version = ?, title = ?, artist = ?, album = ?,
+			album_artist = ?, error = ? WHERE id = ?`, append(args, id)...)
+		if err != nil {
+			return err
+		}
+	} else {
+		res, err := tx.Exec(`INSERT INTO files (path, size, mtime, version, title, artist, album, album_artist, error)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	if result.Err != nil {
+		return nil
+	}
+
+	for key, value := range result.Tag.Fields(FieldSeparator) {
+		if _, err := tx.Exec(`INSERT INTO tags (file_id, key, value) VALUES (?, ?, ?)`, id, key, value); err != nil {
+			return err
+		}
+	}
+	pics, _ := result.Tag.GetPictures()
+	for _, pic := range pics {
+		_, err := tx.Exec(`INSERT INTO pictures (file_id, type, mime_type, description, size) VALUES (?, ?, ?, ?, ?)`,
+			id, pic.Type.String(), pic.MIMEType, pic.Description, len(pic.Data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
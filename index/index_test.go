@@ -0,0 +1,115 @@
+//go:build sqlite
+
+// The index tests need a SQLite driver, so they only build with the sqlite tag:
+//
+//	go test -tags sqlite ./index
+package index
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/srinathh/id3v2reader"
+)
+
+func TestUpdate(t *testing.T) {
+	mp3, err := os.ReadFile("../testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) //every connection to :memory: opens a database of its own
+
+	ix, err := Open(db)
+	if err != nil {
+		t.Fatalf("Error in creating the index: %v", err)
+	}
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"music/a.mp3":    {Data: mp3, ModTime: modified},
+		"music/b.MP3":    {Data: mp3, ModTime: modified},
+		"music/bad.mp3":  {Data: []byte("not a tag"), ModTime: modified},
+		"music/note.txt": {Data: []byte("ignored")},
+	}
+
+	stats, err := ix.Update(fsys, "music", 2)
+	if err != nil || stats != (Stats{Added: 3, Failed: 1}) {
+		t.Fatalf("Unexpected first update %+v, %v", stats, err)
+	}
+	var album string
+	if err := db.QueryRow(`SELECT album FROM files WHERE path = 'music/a.mp3'`).Scan(&album); err != nil || album != "ID3 Tag Test" {
+		t.Errorf("Unexpected album %q, %v", album, err)
+	}
+	var pictures int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pictures`).Scan(&pictures); err != nil || pictures != 2 {
+		t.Errorf("Expected 2 pictures, got %v, %v", pictures, err)
+	}
+
+	fsys["music/a.mp3"] = &fstest.MapFile{Data: mp3, ModTime: modified.Add(time.Hour)}
+	delete(fsys, "music/bad.mp3")
+	stats, err = ix.Update(fsys, "music", 2)
+	if err != nil || stats != (Stats{Updated: 1, Removed: 1, Unchanged: 1}) {
+		t.Fatalf("Unexpected second update %+v, %v", stats, err)
+	}
+	var tags int
+	if err := db.QueryRow(`SELECT COUNT(DISTINCT file_id) FROM tags`).Scan(&tags); err != nil || tags != 2 {
+		t.Errorf("Expected the tags of 2 files, got %v, %v", tags, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	mp3, err := os.ReadFile("../testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ix, err := Open(db)
+	if err != nil {
+		t.Fatalf("Error in creating the index: %v", err)
+	}
+	fsys := fstest.MapFS{
+		"music/b.mp3":   {Data: mp3},
+		"music/a.mp3":   {Data: mp3},
+		"music/bad.mp3": {Data: []byte("not a tag")},
+	}
+	if _, err := ix.Update(fsys, "music", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, want := range map[string][]string{
+		`album = "ID3 Tag Test" AND year = 2013`: {"music/a.mp3", "music/b.mp3"},
+		`path ~ "a.mp3" AND genre != ""`:         {"music/a.mp3"},
+		`title = "no such title"`:                {},
+	} {
+		q, err := id3v2reader.ParseQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := ix.Query(q)
+		if err != nil {
+			t.Fatalf("%v: %v", query, err)
+		}
+		paths := make([]string, 0)
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("%v: expected %v, got %v", query, want, paths)
+		}
+	}
+}
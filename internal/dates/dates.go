@@ -0,0 +1,21 @@
+// Package dates parses the release dates the format readers of the id3v2reader module find in the
+// metadata of MP4 and FLAC files.
+package dates
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Parse parses an ISO 8601 date as precise as the second or as loose as the year
+func Parse(txt string) (time.Time, error) {
+	txt = strings.TrimSpace(txt)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, txt); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New(fmt.Sprintf("Unrecognised date %q", txt))
+}
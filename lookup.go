@@ -0,0 +1,119 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A LookupOption changes which frames Lookup and LookupText consider a match.
+type LookupOption func(*lookup_config)
+
+type lookup_config struct {
+	aliases bool
+	lenient bool
+}
+
+// WithAliases makes a lookup also match frames holding the same information under another ID: the v2.2
+// equivalent of a frame and the IDs a frame replaced or was replaced by between v2.3 and v2.4, so that
+// looking up TDRC finds the TYER frame a sloppy tagger kept in a v2.4 file. Frames with the requested ID
+// still come first.
+func WithAliases() LookupOption {
+	return func(cfg *lookup_config) {
+		cfg.aliases = true
+	}
+}
+
+// Lenient makes a lookup ignore the case of frame IDs and any spaces or null bytes padding them, as
+// written by a few broken taggers.
+func Lenient() LookupOption {
+	return func(cfg *lookup_config) {
+		cfg.lenient = true
+	}
+}
+
+// first_translation returns the index of the first row of frame_translations holding frameid, or -1
+func first_translation(frameid string) int {
+	for j, row := range frame_translations {
+		for _, id := range row {
+			if id == frameid {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// frame_aliases returns the IDs of frames holding the same information as frames with the ID frameid in
+// other versions, newest first, which are those of the first row of frame_translations holding it. IDs
+// that row only shares with frameid by way of an earlier row are left out, so TDAT, which TDRC replaced
+// along with TYER, is not an alias of TDRC.
+func frame_aliases(frameid string) []string {
+	ret := make([]string, 0)
+	first := first_translation(frameid)
+	if first < 0 {
+		return ret
+	}
+	row := frame_translations[first]
+	for col := len(row) - 1; col >= 0; col-- {
+		id := row[col]
+		if id != "" && id != frameid && first_translation(id) == first && (len(ret) == 0 || ret[len(ret)-1] != id) {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+// lookup_ids returns the frame IDs a lookup of frameid matches, in order of preference
+func lookup_ids(frameid string, cfg lookup_config) []string {
+	if cfg.lenient {
+		frameid = normalize_frameid(frameid)
+	}
+	ids := []string{frameid}
+	if !cfg.aliases {
+		return ids
+	}
+	return append(ids, frame_aliases(frameid)...)
+}
+
+func normalize_frameid(frameid string) string {
+	return strings.ToUpper(strings.Trim(frameid, " \x00"))
+}
+
+// Lookup returns the frames with the ID frameid, widened by opts to aliases or sloppily written IDs.
+// Frames are ordered by how closely their ID matches and otherwise in tag order.
+func (id3tag ID3Tag) Lookup(frameid string, opts ...LookupOption) []ID3Frame {
+	var cfg lookup_config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ret := make([]ID3Frame, 0)
+	for _, id := range lookup_ids(frameid, cfg) {
+		for _, frame := range id3tag.Frames {
+			frame_id := frame.FrameID
+			if cfg.lenient {
+				frame_id = normalize_frameid(frame_id)
+			}
+			if frame_id == id {
+				ret = append(ret, frame)
+			}
+		}
+	}
+	return ret
+}
+
+// LookupText returns the text of the first frame Lookup finds, as GetTextFrameData does.
+func (id3tag ID3Tag) LookupText(frameid string, opts ...LookupOption) (string, error) {
+	for _, frame := range id3tag.Lookup(frameid, opts...) {
+		if frame.Compression || frame.Encryption || frame.Unsynchronisation {
+			continue
+		}
+		if len(frame.Data) == 0 {
+			return "", errors.New(fmt.Sprintf("Frame %v is empty", frame.FrameID))
+		}
+		return id3tag.decodetext(frame.Data[0], frame.Data[1:len(frame.Data)])
+	}
+	return "", errors.New(fmt.Sprintf("No such frame %v found in the taglist", frameid))
+}
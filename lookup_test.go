@@ -0,0 +1,58 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	raw := make_tag(4,
+		make_frame(4, "TYER", 0, []byte("\x031999")),
+		make_frame(4, "TDRC", 0, []byte("\x032001-05")),
+	)
+	copy(raw[10+15:], "tdrc") //second frame ID written in lowercase
+	if id3tag, _ := ReadID3Bytes(raw); len(id3tag.Lookup("TDRC", Lenient())) != 0 {
+		t.Errorf("Expected the lowercase frame to end the tag by default")
+	}
+	id3tag, err := ReadID3Bytes(raw, WithLenientFrameIDs(true))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	if frames := id3tag.Lookup("TDRC"); len(frames) != 0 {
+		t.Errorf("Expected no strict match, got %v frames", len(frames))
+	}
+	if txt, err := id3tag.LookupText("TDRC", WithAliases()); err != nil || txt != "1999" {
+		t.Errorf("Expected the TYER alias, got %q, %v", txt, err)
+	}
+	if txt, err := id3tag.LookupText("TDRC", Lenient()); err != nil || txt != "2001-05" {
+		t.Errorf("Expected the lowercase frame, got %q, %v", txt, err)
+	}
+
+	ids := make([]string, 0)
+	for _, frame := range id3tag.Lookup("tdrc", Lenient(), WithAliases()) {
+		ids = append(ids, frame.FrameID)
+	}
+	if !reflect.DeepEqual(ids, []string{"tdrc", "TYER"}) {
+		t.Errorf("Unexpected lookup order %v", ids)
+	}
+}
+
+func TestFrameAliases(t *testing.T) {
+	for frameid, want := range map[string][]string{
+		"TDRC": {"TYER", "TYE"},
+		"TYE":  {"TDRC", "TYER"},
+		"TDAT": {"TDA"},
+		"TIPL": {"IPLS", "IPL"},
+		"TMCL": {},
+		"PCNT": {"CNT"},
+		"PIC":  {"APIC"},
+		"XYZW": {},
+	} {
+		if got := frame_aliases(frameid); !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: expected aliases %v, got %v", frameid, want, got)
+		}
+	}
+}
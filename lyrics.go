@@ -0,0 +1,197 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SyncedLyrics is the content of a SYLT frame: text synchronised with the audio. ContentType is 1 for
+// lyrics and 2 for text transcription; the standard lists the other types. Timestamps are milliseconds
+// from the start of the audio unless MPEGFrames is set, in which case they count MPEG frames.
+type SyncedLyrics struct {
+	Language    string
+	Description string
+	ContentType byte
+	MPEGFrames  bool
+	Lines       []SyncedLine
+}
+
+// A SyncedLine is a piece of synchronised text and the time it starts at. A text starting with a newline
+// begins a new line of the lyrics.
+type SyncedLine struct {
+	Timestamp uint32
+	Text      string
+}
+
+// GetSyncedLyrics returns the SYLT frames of the tag in the order they were read. Malformed frames are
+// left out.
+func (id3tag ID3Tag) GetSyncedLyrics() ([]SyncedLyrics, error) {
+	ret := make([]SyncedLyrics, 0)
+	for _, framedata := range id3tag.tag_data("SYLT") {
+		if lyrics, err := id3tag.parse_sylt(framedata); err == nil {
+			ret = append(ret, lyrics)
+		}
+	}
+	if len(ret) == 0 {
+		return ret, errors.New("No such frame SYLT found in the taglist")
+	}
+	return ret, nil
+}
+
+func (id3tag ID3Tag) parse_sylt(framedata []byte) (SyncedLyrics, error) {
+	var lyrics SyncedLyrics
+	if len(framedata) < 6 {
+		return lyrics, errors.New("SYLT frame is too short")
+	}
+	encoding := framedata[0]
+	lyrics.Language = strings.TrimSpace(decodeISO88591(framedata[1:4]))
+	lyrics.MPEGFrames = framedata[4] == 1
+	lyrics.ContentType = framedata[5]
+
+	desc, data := split_text(encoding, framedata[6:len(framedata)])
+	var err error
+	if lyrics.Description, err = id3tag.decodetext(encoding, desc); err != nil {
+		return lyrics, err
+	}
+	lyrics.Lines = make([]SyncedLine, 0)
+	for len(data) > 0 {
+		var text []byte
+		text, data = split_text(encoding, data)
+		if len(data) < 4 {
+			return lyrics, errors.New("SYLT frame ends within a synchronised text")
+		}
+		var line SyncedLine
+		line.Timestamp, _ = convert_regular_int(data[0:4])
+		if line.Text, err = id3tag.decodetext(encoding, text); err != nil {
+			return lyrics, err
+		}
+		lyrics.Lines = append(lyrics.Lines, line)
+		data = data[4:len(data)]
+	}
+	return lyrics, nil
+}
+
+// SetSyncedLyrics returns a copy of the tag with a SYLT frame holding lyrics, replacing any SYLT frame with
+// the same language and description
+func (id3tag ID3Tag) SetSyncedLyrics(lyrics SyncedLyrics) ID3Tag {
+	strs := []string{lyrics.Description}
+	for _, line := range lyrics.Lines {
+		strs = append(strs, line.Text)
+	}
+	encoding := text_encoding(id3tag.Version, strs...)
+	terminator := string_terminator(encoding)
+
+	lang := (lyrics.Language + "   ")[0:3]
+	timestamp_format := byte(2)
+	if lyrics.MPEGFrames {
+		timestamp_format = 1
+	}
+	data := append([]byte{encoding}, lang...)
+	data = append(data, timestamp_format, lyrics.ContentType)
+	data = append(append(data, encode_string(encoding, lyrics.Description)...), terminator...)
+	for _, line := range lyrics.Lines {
+		data = append(append(data, encode_string(encoding, line.Text)...), terminator...)
+		data = append(data, encode_regular_int(line.Timestamp)...)
+	}
+
+	frames := make([]ID3Frame, 0, len(id3tag.Frames)+1)
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID == "SYLT" {
+			if old, err := id3tag.parse_sylt(frame.Data); err == nil && strings.EqualFold(old.Language, lyrics.Language) && old.Description == lyrics.Description {
+				continue
+			}
+		}
+		frames = append(frames, frame)
+	}
+	id3tag.Frames = append(frames, ID3Frame{FrameID: "SYLT", Length: uint32(len(data)), Data: data})
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// LRC returns the lyrics in the .lrc format with one line per synchronised text, in timestamp order.
+// Lyrics timed in MPEG frames cannot be converted as the frame duration depends on the audio.
+func (lyrics SyncedLyrics) LRC() (string, error) {
+	if lyrics.MPEGFrames {
+		return "", errors.New("Lyrics timed in MPEG frames cannot be converted to LRC")
+	}
+	lines := append([]SyncedLine(nil), lyrics.Lines...)
+	sort.SliceStable(lines, func(a, b int) bool {
+		return lines[a].Timestamp < lines[b].Timestamp
+	})
+	var buf strings.Builder
+	if lyrics.Language != "" {
+		fmt.Fprintf(&buf, "[la:%s]\n", lyrics.Language)
+	}
+	for _, line := range lines {
+		cs := line.Timestamp / 10
+		text := strings.TrimLeft(line.Text, "\r\n")
+		fmt.Fprintf(&buf, "[%02d:%02d.%02d]%s\n", cs/6000, cs/100%60, cs%100, text)
+	}
+	return buf.String(), nil
+}
+
+var lrc_tag = &lazy_regexp{expr: `^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`}
+var lrc_metadata = &lazy_regexp{expr: `^\[([a-z]+):(.*)\]\s*$`}
+
+// ParseLRC reads lyrics in the .lrc format. Lines with several timestamps yield one synchronised text per
+// timestamp, the offset tag shifts all timestamps and the la tag sets the language. Other metadata tags
+// and lines without timestamps are ignored. The lines are returned in timestamp order with ContentType
+// set to lyrics.
+func ParseLRC(rd io.Reader) (SyncedLyrics, error) {
+	lyrics := SyncedLyrics{ContentType: 1, Lines: make([]SyncedLine, 0)}
+	offset := int64(0)
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if m := lrc_metadata.get().FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "offset":
+				//a positive offset makes the lyrics appear sooner
+				if n, err := strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64); err == nil {
+					offset = n
+				}
+			case "la":
+				lyrics.Language = strings.TrimSpace(m[2])
+			}
+			continue
+		}
+		times := make([]int64, 0, 1)
+		for {
+			m := lrc_tag.get().FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			min, _ := strconv.ParseInt(m[1], 10, 64)
+			sec, _ := strconv.ParseInt(m[2], 10, 64)
+			ms := int64(0)
+			if m[3] != "" {
+				//fractions are hundredths in most files but some write milliseconds
+				ms, _ = strconv.ParseInt((m[3] + "00")[0:3], 10, 64)
+			}
+			times = append(times, (min*60+sec)*1000+ms)
+			line = line[len(m[0]):len(line)]
+		}
+		for _, t := range times {
+			t -= offset
+			if t < 0 {
+				t = 0
+			}
+			lyrics.Lines = append(lyrics.Lines, SyncedLine{uint32(t), line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return lyrics, err
+	}
+	sort.SliceStable(lyrics.Lines, func(a, b int) bool {
+		return lyrics.Lines[a].Timestamp < lyrics.Lines[b].Timestamp
+	})
+	return lyrics, nil
+}
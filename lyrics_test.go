@@ -0,0 +1,52 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncedLyrics(t *testing.T) {
+	lrc := "[ti:Song]\n[la:eng]\n[offset:+500]\n[00:12.50][01:02.00]Chorus line\n[00:01.5]First line\nno timestamp\n"
+	lyrics, err := ParseLRC(strings.NewReader(lrc))
+	if err != nil {
+		t.Fatalf("Error in parsing LRC: %v", err)
+	}
+	want := SyncedLyrics{Language: "eng", ContentType: 1, Lines: []SyncedLine{{1000, "First line"}, {12000, "Chorus line"}, {61500, "Chorus line"}}}
+	if !reflect.DeepEqual(lyrics, want) {
+		t.Fatalf("Expected %v, got %v", want, lyrics)
+	}
+
+	for _, ver := range []byte{3, 4} {
+		id3tag, err := ReadID3Bytes(make_tag(ver))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		lyrics.Lines[0].Text = "\u0416irst line"
+		id3tag = id3tag.SetSyncedLyrics(lyrics).SetSyncedLyrics(lyrics)
+
+		var buf bytes.Buffer
+		if err := WriteID3(&buf, id3tag); err != nil {
+			t.Fatalf("Error in writing tag: %v", err)
+		}
+		if id3tag, err = ReadID3(&buf); err != nil {
+			t.Fatalf("Error in reading written tag: %v", err)
+		}
+		got, err := id3tag.GetSyncedLyrics()
+		if err != nil || len(got) != 1 || !reflect.DeepEqual(got[0], lyrics) {
+			t.Errorf("v2.%d: expected %v, got %v, %v", ver, lyrics, got, err)
+		}
+	}
+
+	exported, err := lyrics.LRC()
+	if want := "[la:eng]\n[00:01.00]\u0416irst line\n[00:12.00]Chorus line\n[01:01.50]Chorus line\n"; err != nil || exported != want {
+		t.Errorf("Unexpected LRC %q, %v", exported, err)
+	}
+	lyrics.MPEGFrames = true
+	if _, err := lyrics.LRC(); err == nil {
+		t.Errorf("Expected an error for lyrics timed in MPEG frames")
+	}
+}
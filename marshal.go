@@ -0,0 +1,232 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// marshal_version is the version of the encoding written by MarshalBinary, bumped whenever it changes
+const marshal_version = 4
+
+// MarshalBinary encodes the frame, every field included, so that parsed tags can be cached without
+// reading the audio files again.
+func (frame ID3Frame) MarshalBinary() ([]byte, error) {
+	return append_frame(nil, frame), nil
+}
+
+// UnmarshalBinary decodes a frame encoded by MarshalBinary.
+func (frame *ID3Frame) UnmarshalBinary(data []byte) error {
+	rd := binary_reader{buf: append([]byte(nil), data...)}
+	decoded := rd.frame()
+	if err := rd.done(); err != nil {
+		return err
+	}
+	*frame = decoded
+	return nil
+}
+
+// MarshalBinary encodes the tag with its frames and everything its header declared, along with the
+// options it was read with that govern decoding, so that a cached tag decodes text as the original did.
+// The encoding is versioned; tags encoded by a later version of this package may fail to unmarshal.
+func (id3tag ID3Tag) MarshalBinary() ([]byte, error) {
+	buf := []byte{'I', 'D', '3', 'T', marshal_version}
+	buf = append(buf, byte(id3tag.Version), id3tag.Revision)
+	buf = binary.BigEndian.AppendUint32(buf, id3tag.Size)
+	buf = append(buf, pack_bools(id3tag.Footer, id3tag.Experimental, id3tag.altered, id3tag.ExtendedHeader != nil,
+		id3tag.cfg.no_utf16_guess, id3tag.cfg.trim_text, id3tag.cfg.allow_experimental, id3tag.cfg.lenient_frameids))
+	buf = append(buf, pack_bools(id3tag.cfg.normalize), byte(id3tag.cfg.normalization))
+	if exthdr := id3tag.ExtendedHeader; exthdr != nil {
+		buf = append(buf, pack_bools(exthdr.Update, exthdr.HasCRC, exthdr.HasRestrictions), exthdr.Restrictions)
+		buf = binary.BigEndian.AppendUint32(buf, exthdr.CRC)
+		buf = binary.BigEndian.AppendUint32(buf, exthdr.PaddingSize)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, id3tag.PaddingBytes)
+	buf = binary.BigEndian.AppendUint32(buf, id3tag.UnparsedBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(id3tag.Warnings)))
+	for _, warning := range id3tag.Warnings {
+		buf = append_bytes(buf, []byte(warning))
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(id3tag.Frames)))
+	for _, frame := range id3tag.Frames {
+		buf = append_frame(buf, frame)
+	}
+
+	//the frames as read, for Changes, which are mostly still the frames of the tag
+	switch {
+	case id3tag.original == nil:
+		buf = append(buf, 0)
+	case len(id3tag.original) > 0 && len(id3tag.original) == len(id3tag.Frames) && &id3tag.original[0] == &id3tag.Frames[0]:
+		buf = append(buf, 2, byte(id3tag.original_version))
+	default:
+		buf = append(buf, 1, byte(id3tag.original_version))
+		buf = binary.AppendUvarint(buf, uint64(len(id3tag.original)))
+		for _, frame := range id3tag.original {
+			buf = append_frame(buf, frame)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a tag encoded by MarshalBinary. data is copied once, and the Data of the frames
+// share the copy.
+func (id3tag *ID3Tag) UnmarshalBinary(data []byte) error {
+	data = append([]byte(nil), data...)
+	if len(data) < 5 || string(data[0:4]) != "ID3T" {
+		return errors.New("Not an encoded tag")
+	}
+	if data[4] != marshal_version {
+		return errors.New(fmt.Sprintf("Unsupported tag encoding version %v", data[4]))
+	}
+	rd := binary_reader{buf: data[5:len(data)]}
+	decoded := ID3Tag{cache: new_text_cache()}
+	decoded.Version, decoded.Revision = Version(rd.byte()), rd.byte()
+	decoded.Size = rd.uint32()
+	var has_exthdr bool
+	unpack_bools(rd.byte(), &decoded.Footer, &decoded.Experimental, &decoded.altered, &has_exthdr,
+		&decoded.cfg.no_utf16_guess, &decoded.cfg.trim_text, &decoded.cfg.allow_experimental, &decoded.cfg.lenient_frameids)
+	unpack_bools(rd.byte(), &decoded.cfg.normalize)
+	decoded.cfg.normalization = Normalization(rd.byte())
+	if has_exthdr {
+		exthdr := new(ExtendedHeader)
+		unpack_bools(rd.byte(), &exthdr.Update, &exthdr.HasCRC, &exthdr.HasRestrictions)
+		exthdr.Restrictions = rd.byte()
+		exthdr.CRC, exthdr.PaddingSize = rd.uint32(), rd.uint32()
+		decoded.ExtendedHeader = exthdr
+	}
+	decoded.PaddingBytes, decoded.UnparsedBytes = rd.uint32(), rd.uint32()
+	if count := rd.count(); count > 0 {
+		decoded.Warnings = make([]string, count)
+		for j := range decoded.Warnings {
+			decoded.Warnings[j] = string(rd.bytes())
+		}
+	}
+	if count := rd.count(); count > 0 {
+		decoded.Frames = make([]ID3Frame, count)
+		for j := range decoded.Frames {
+			decoded.Frames[j] = rd.frame()
+		}
+	}
+	switch rd.byte() {
+	case 1:
+		decoded.original_version = Version(rd.byte())
+		decoded.original = make([]ID3Frame, rd.count())
+		for j := range decoded.original {
+			decoded.original[j] = rd.frame()
+		}
+	case 2:
+		decoded.original_version = Version(rd.byte())
+		decoded.original = decoded.Frames
+	}
+	if err := rd.done(); err != nil {
+		return err
+	}
+	*id3tag = decoded
+	return nil
+}
+
+func append_frame(buf []byte, frame ID3Frame) []byte {
+	buf = append_bytes(buf, []byte(frame.FrameID))
+	buf = binary.BigEndian.AppendUint32(buf, frame.Length)
+	buf = append(buf, frame.StatusFlags, frame.FormatFlags)
+	buf = append(buf, pack_bools(frame.DiscardOnTagAlter, frame.DiscardOnFileAlter, frame.ReadOnly, frame.Compression,
+		frame.Encryption, frame.Unsynchronisation, frame.Data_Length_Indicator, frame.Grouping))
+	buf = append(buf, frame.GroupSymbol, frame.EncryptionMethod)
+	buf = binary.BigEndian.AppendUint32(buf, frame.DataLength)
+	buf = append_bytes(buf, frame.header)
+	if frame.Data == nil {
+		return append(buf, 0)
+	}
+	return append_bytes(append(buf, 1), frame.Data)
+}
+
+func append_bytes(buf, data []byte) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(data))), data...)
+}
+
+func pack_bools(bools ...bool) byte {
+	var packed byte
+	for j, b := range bools {
+		if b {
+			packed |= 0x80 >> uint(j)
+		}
+	}
+	return packed
+}
+
+func unpack_bools(packed byte, bools ...*bool) {
+	for j, b := range bools {
+		*b = packed&(0x80>>uint(j)) != 0
+	}
+}
+
+// binary_reader takes the fields of an encoded tag off buf, remembering the first error so that callers
+// need only check it once at the end
+type binary_reader struct {
+	buf []byte
+	err error
+}
+
+func (rd *binary_reader) take(length uint64) []byte {
+	if rd.err == nil && length > uint64(len(rd.buf)) {
+		rd.err = errors.New("Encoded tag is truncated")
+	}
+	if rd.err != nil {
+		return make([]byte, 4) //zeros enough for any fixed size field
+	}
+	field := rd.buf[0:length:length]
+	rd.buf = rd.buf[length:len(rd.buf)]
+	return field
+}
+
+func (rd *binary_reader) byte() byte {
+	return rd.take(1)[0]
+}
+
+func (rd *binary_reader) uint32() uint32 {
+	return binary.BigEndian.Uint32(rd.take(4))
+}
+
+// count reads a uvarint element count, which must not exceed the bytes left since every element takes one
+func (rd *binary_reader) count() uint64 {
+	n, size := binary.Uvarint(rd.buf)
+	if rd.err == nil && (size <= 0 || n > uint64(len(rd.buf))) {
+		rd.err = errors.New("Encoded tag is malformed")
+	}
+	if rd.err != nil {
+		return 0
+	}
+	rd.buf = rd.buf[size:len(rd.buf)]
+	return n
+}
+
+func (rd *binary_reader) bytes() []byte {
+	return rd.take(rd.count())
+}
+
+func (rd *binary_reader) frame() ID3Frame {
+	var frame ID3Frame
+	frame.FrameID = string(rd.bytes())
+	frame.Length = rd.uint32()
+	frame.StatusFlags, frame.FormatFlags = rd.byte(), rd.byte()
+	unpack_bools(rd.byte(), &frame.DiscardOnTagAlter, &frame.DiscardOnFileAlter, &frame.ReadOnly, &frame.Compression,
+		&frame.Encryption, &frame.Unsynchronisation, &frame.Data_Length_Indicator, &frame.Grouping)
+	frame.GroupSymbol, frame.EncryptionMethod = rd.byte(), rd.byte()
+	frame.DataLength = rd.uint32()
+	if header := rd.bytes(); len(header) > 0 {
+		frame.header = header
+	}
+	if rd.byte() != 0 {
+		frame.Data = rd.bytes()
+	}
+	return frame
+}
+
+func (rd *binary_reader) done() error {
+	if rd.err == nil && len(rd.buf) != 0 {
+		rd.err = errors.New("Encoded tag has trailing bytes")
+	}
+	return rd.err
+}
@@ -0,0 +1,50 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	raw, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(raw, WithTextTrimming(true))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	id3tag.ExtendedHeader = &ExtendedHeader{HasRestrictions: true, Restrictions: RestrictText30Chars}
+	id3tag.Warnings = []string{"warning"}
+	id3tag = id3tag.SetText("TIT3", "Subtitle")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(id3tag); err != nil {
+		t.Fatalf("Error in encoding tag: %v", err)
+	}
+	var decoded ID3Tag
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Error in decoding tag: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, id3tag) {
+		t.Errorf("Decoded tag differs from the original")
+	}
+
+	encoded, _ := id3tag.Frames[0].MarshalBinary()
+	var frame ID3Frame
+	if err := frame.UnmarshalBinary(encoded); err != nil || !reflect.DeepEqual(frame, id3tag.Frames[0]) {
+		t.Errorf("Decoded frame %+v differs from the original, %v", frame, err)
+	}
+
+	encoded, _ = id3tag.MarshalBinary()
+	for _, cut := range []int{3, 20, len(encoded) - 1} {
+		if err := decoded.UnmarshalBinary(encoded[0:cut]); err == nil {
+			t.Errorf("Expected an error for an encoding cut at %v bytes", cut)
+		}
+	}
+}
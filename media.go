@@ -0,0 +1,125 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+)
+
+type media_type struct {
+	name        string
+	refinements map[string]string
+}
+
+var cassette_types = map[string]string{
+	"I":   "Type I cassette (ferric/normal)",
+	"II":  "Type II cassette (chrome)",
+	"III": "Type III cassette (ferric chrome)",
+	"IV":  "Type IV cassette (metal)",
+}
+
+// media_types maps the TMED codes listed in the ID3v2.3 standard to their names
+var media_types = map[string]media_type{
+	"DIG": {"Other digital media", map[string]string{"A": "Analogue transfer from media"}},
+	"ANA": {"Other analogue media", map[string]string{"WAC": "Wax cylinder", "8CA": "8-track tape cassette"}},
+	"CD":  {"CD", map[string]string{"A": "Analogue transfer from media", "DD": "DDD", "AD": "ADD", "AA": "AAD"}},
+	"LD":  {"Laserdisc", nil},
+	"TT": {"Turntable records", map[string]string{
+		"33": "33.33 rpm", "45": "45 rpm", "71": "71.29 rpm", "76": "76.59 rpm", "78": "78.26 rpm", "80": "80 rpm",
+	}},
+	"MD": {"MiniDisc", map[string]string{"A": "Analogue transfer from media"}},
+	"DAT": {"DAT", map[string]string{
+		"A": "Analogue transfer from media",
+		"1": "standard, 48 kHz/16 bits, linear",
+		"2": "mode 2, 32 kHz/16 bits, linear",
+		"3": "mode 3, 32 kHz/12 bits, non-linear, low speed",
+		"4": "mode 4, 32 kHz/12 bits, 4 channels",
+		"5": "mode 5, 44.1 kHz/16 bits, linear",
+		"6": "mode 6, 44.1 kHz/16 bits, 'wide track' play",
+	}},
+	"DCC": {"DCC", map[string]string{"A": "Analogue transfer from media"}},
+	"DVD": {"DVD", map[string]string{"A": "Analogue transfer from media"}},
+	"TV":  {"Television", map[string]string{"PAL": "PAL", "NTSC": "NTSC", "SECAM": "SECAM"}},
+	"VID": {"Video", map[string]string{
+		"PAL": "PAL", "NTSC": "NTSC", "SECAM": "SECAM", "VHS": "VHS", "SVHS": "S-VHS", "BETA": "BETAMAX",
+	}},
+	"RAD": {"Radio", map[string]string{"FM": "FM", "AM": "AM", "LW": "LW", "MW": "MW"}},
+	"TEL": {"Telephone", map[string]string{"I": "ISDN"}},
+	"MC":  {"MC (normal cassette)", merge_refinements(cassette_types, map[string]string{"4": "4.75 cm/s (normal speed for a two sided cassette)", "9": "9.5 cm/s"})},
+	"REE": {"Reel", merge_refinements(cassette_types, map[string]string{"9": "9.5 cm/s", "19": "19 cm/s", "38": "38 cm/s", "76": "76 cm/s"})},
+}
+
+func merge_refinements(a, b map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range a {
+		ret[k] = v
+	}
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
+
+// decode_media_type replaces the parenthesized codes of a TMED value, such as "(MC/I)" or "(CD/DD)", with
+// their names. A code and its refinement are joined with ", " and several media with "; ". Free text
+// following the codes is kept, as are codes the standard does not define. "((" stands for a literal "(".
+func decode_media_type(txt string) string {
+	names := make([]string, 0)
+	rest := txt
+	for strings.HasPrefix(rest, "(") && !strings.HasPrefix(rest, "((") {
+		end := strings.IndexByte(rest, ')')
+		if end == -1 {
+			break
+		}
+		code := rest[1:end]
+		rest = rest[end+1 : len(rest)]
+
+		parts := strings.Split(code, "/")
+		media, ok := media_types[parts[0]]
+		if !ok {
+			names = append(names, code)
+			continue
+		}
+		name := media.name
+		for _, refinement := range parts[1:len(parts)] {
+			if refined, ok := media.refinements[refinement]; ok {
+				name += ", " + refined
+			} else {
+				name += ", " + refinement
+			}
+		}
+		names = append(names, name)
+	}
+	rest = strings.Replace(rest, "((", "(", -1)
+
+	ret := strings.Join(names, "; ")
+	if rest != "" {
+		if ret != "" && !strings.HasPrefix(rest, " ") {
+			ret += " "
+		}
+		ret += rest
+	}
+	return ret
+}
+
+// GetMood returns the v2.4 TMOO mood, such as "Calm" or "Aggressive".
+func (id3tag ID3Tag) GetMood() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TMOO")
+	return txt, err
+}
+
+// GetMediaType returns the TMED media the audio was taken from, with the standard's codes decoded, so
+// "(MC/I)" is returned as "MC (normal cassette), Type I cassette (ferric/normal)".
+func (id3tag ID3Tag) GetMediaType() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TMED")
+	if err != nil {
+		return "", err
+	}
+	return decode_media_type(txt), nil
+}
+
+// GetFileType returns the TFLT file type, such as "MPG/3".
+func (id3tag ID3Tag) GetFileType() (string, error) {
+	txt, err := id3tag.GetTextFrameData("TFLT")
+	return txt, err
+}
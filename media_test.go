@@ -0,0 +1,47 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestDecodeMediaType(t *testing.T) {
+	cases := map[string]string{
+		"(MC/I)":             "MC (normal cassette), Type I cassette (ferric/normal)",
+		"(CD/DD)":            "CD, DDD",
+		"(TT/45)(DIG/A)":     "Turntable records, 45 rpm; Other digital media, Analogue transfer from media",
+		"(VID/PAL/VHS)":      "Video, PAL, VHS",
+		"(MC) with 4 tracks": "MC (normal cassette) with 4 tracks",
+		"(XYZ)":              "XYZ",
+		"((live) recording":  "(live) recording",
+		"Vinyl":              "Vinyl",
+		"(RAD/FM":            "(RAD/FM",
+	}
+	for code, want := range cases {
+		if got := decode_media_type(code); got != want {
+			t.Errorf("decode_media_type(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestMediaGetters(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TMOO", 0, []byte("\x03Calm")),
+		make_frame(4, "TMED", 0, []byte("\x03(RAD/FM)")),
+		make_frame(4, "TFLT", 0, []byte("\x03MPG/3")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	getters := map[string]func() (string, error){
+		"Calm":      id3tag.GetMood,
+		"Radio, FM": id3tag.GetMediaType,
+		"MPG/3":     id3tag.GetFileType,
+	}
+	for want, get := range getters {
+		if got, err := get(); err != nil || got != want {
+			t.Errorf("Expected %q, got %q, %v", want, got, err)
+		}
+	}
+}
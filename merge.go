@@ -0,0 +1,77 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// MergeID3 combines the tags read by ReadAllID3 into a single view. Starting from the first tag, the frames
+// of every later tag carrying the v2.4 "tag is an update" flag override the corresponding frames read so
+// far, as the standard requires. Later tags without the flag are independent tags and are not merged.
+//
+// Frames correspond when they are text frames with the same FrameID, TXXX or WXXX frames with the same
+// description, COMM or USLT frames with the same language and description, or otherwise identical frames.
+func MergeID3(tags []ID3Tag) ID3Tag {
+	if len(tags) == 0 {
+		return ID3Tag{}
+	}
+	merged := tags[0]
+	merged.Frames = append(make([]ID3Frame, 0, len(tags[0].Frames)), tags[0].Frames...)
+	merged.cache = merged.fresh_cache()
+
+	for _, update := range tags[1:len(tags)] {
+		if update.ExtendedHeader == nil || !update.ExtendedHeader.Update {
+			continue
+		}
+		for _, frame := range update.Frames {
+			key := frame_key(frame)
+			replaced := false
+			kept := merged.Frames[0:0]
+			for _, existing := range merged.Frames {
+				if frame_key(existing) != key {
+					kept = append(kept, existing)
+				} else if !replaced {
+					kept = append(kept, frame)
+					replaced = true
+				}
+			}
+			if !replaced {
+				kept = append(kept, frame)
+			}
+			merged.Frames = kept
+		}
+	}
+	return merged
+}
+
+// ReadMergedID3 reads all the tags at the start of rd and returns both their merged view and the raw tags.
+func ReadMergedID3(rd io.Reader, opts ...Option) (ID3Tag, []ID3Tag, error) {
+	tags, err := ReadAllID3(rd, opts...)
+	if err != nil {
+		return ID3Tag{}, nil, err
+	}
+	return MergeID3(tags), tags, nil
+}
+
+// frame_key identifies which frames of a tag describe the same thing
+func frame_key(frame ID3Frame) string {
+	switch frame.FrameID {
+	case "TXXX", "WXXX":
+		return frame.FrameID + "\x00" + frame.Description()
+	case "COMM", "USLT":
+		if len(frame.Data) > 4 {
+			return frame.FrameID + "\x00" + string(frame.Data[1:4]) + "\x00" + frame.Description()
+		}
+	}
+	if strings.HasPrefix(frame.FrameID, "T") {
+		return frame.FrameID
+	}
+	var key bytes.Buffer
+	key.WriteString(frame.FrameID)
+	key.WriteByte(0)
+	key.Write(frame.Data)
+	return key.String()
+}
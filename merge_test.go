@@ -0,0 +1,52 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMergeID3(t *testing.T) {
+	first := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x00Old Title")),
+		make_frame(4, "TALB", 0, []byte("\x00Album")),
+		make_frame(4, "TXXX", 0, []byte("\x00MOOD\x00calm")),
+	)
+	update := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x00New Title")),
+		make_frame(4, "TXXX", 0, []byte("\x00MOOD\x00lively")),
+		make_frame(4, "TXXX", 0, []byte("\x00TEMPO\x00fast")),
+	)
+	//insert an extended header carrying just the update flag
+	exthdr := []byte{0, 0, 0, 7, 1, 0x40, 0}
+	update[5] |= 0x40
+	update = append(append(update[0:6:6], synchsafe(uint32(len(update)-10+len(exthdr)))...), append(exthdr, update[10:len(update)]...)...)
+	independent := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x00Unrelated")))
+
+	stream := append(append(append([]byte{}, first...), update...), independent...)
+	merged, raw, err := ReadMergedID3(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("Error in reading tags: %v", err)
+	}
+	if len(raw) != 3 {
+		t.Fatalf("Expected 3 raw tags, got %v", len(raw))
+	}
+	if raw[1].ExtendedHeader == nil || !raw[1].ExtendedHeader.Update {
+		t.Fatalf("Expected the second tag to be flagged as an update")
+	}
+	if title, _ := raw[0].GetTitle(); title != "Old Title" {
+		t.Errorf("The raw first tag should be left alone, got title %q", title)
+	}
+
+	if title, _ := merged.GetTitle(); title != "New Title" {
+		t.Errorf("Expected the update to override the title, got %q", title)
+	}
+	if album, _ := merged.GetAlbum(); album != "Album" {
+		t.Errorf("Expected the album to survive the merge, got %q", album)
+	}
+	txxx := merged.GetTagData("TXXX")
+	if len(txxx) != 2 || !bytes.HasSuffix(txxx[0], []byte("lively")) || !bytes.HasSuffix(txxx[1], []byte("fast")) {
+		t.Errorf("Expected the MOOD TXXX to be replaced and TEMPO added, got %q", txxx)
+	}
+}
@@ -0,0 +1,233 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata is a summary of the commonly displayed fields of a tag. Fields missing from the tag are left
+// at their zero values.
+type Metadata struct {
+	Title         string
+	Artist        string
+	Album         string
+	AlbumArtist   string
+	Composer      string
+	Track         int //0 if the tag has none
+	Disc          int //0 if the tag has none
+	Genres        []string
+	RecordingDate time.Time
+	Duration      time.Duration
+	Extra         map[string]string //values of TXXX frames registered with RegisterUserText, keyed by field
+}
+
+// GetMetadata returns the summary of the tag used by batch extraction. The frames of v2.2 tags are read
+// under their v2.3 IDs.
+func (id3tag ID3Tag) GetMetadata() Metadata {
+	if id3tag.Version == Version22 {
+		id3tag = id3tag.with_v23_frameids()
+	}
+	var md Metadata
+	md.Title, _ = id3tag.GetTitle()
+	md.Artist, _ = id3tag.GetArtist()
+	md.Album, _ = id3tag.GetAlbum()
+	md.Extra = id3tag.GetUserTextFields()
+	if md.AlbumArtist, _ = id3tag.GetTextFrameData("TPE2"); md.AlbumArtist == "" {
+		md.AlbumArtist = md.Extra["AlbumArtist"]
+	}
+	md.Composer, _ = id3tag.GetComposer()
+	md.Track, _, _ = id3tag.GetTrack()
+	md.Disc, _, _ = id3tag.GetDisc()
+	md.Genres, _ = id3tag.GetGenres()
+	md.RecordingDate, _ = id3tag.GetRecordingDate()
+	md.Duration, _ = id3tag.GetLength()
+	return md
+}
+
+// with_v23_frameids returns a copy of a v2.2 tag as a v2.3 tag whose frames carry the IDs v23_frameid gives
+// them, so that the getters, which look frames up by their v2.3 IDs, find them. The frame bodies of the
+// text frames the getters read are the same in both versions.
+func (id3tag ID3Tag) with_v23_frameids() ID3Tag {
+	frames := make([]ID3Frame, len(id3tag.Frames))
+	for j, frame := range id3tag.Frames {
+		frame.FrameID = v23_frameid(id3tag.Version, frame.FrameID)
+		frames[j] = frame
+	}
+	id3tag.Frames = frames
+	id3tag.Version = Version23
+	return id3tag
+}
+
+// metadata_json is the JSON form of Metadata, shared by everything in the module that serves metadata
+type metadata_json struct {
+	Title         string            `json:"title,omitempty"`
+	Artist        string            `json:"artist,omitempty"`
+	Album         string            `json:"album,omitempty"`
+	AlbumArtist   string            `json:"album_artist,omitempty"`
+	Composer      string            `json:"composer,omitempty"`
+	Track         int               `json:"track,omitempty"`
+	Disc          int               `json:"disc,omitempty"`
+	Genres        []string          `json:"genres,omitempty"`
+	RecordingDate string            `json:"recording_date,omitempty"` //RFC 3339
+	DurationMs    int64             `json:"duration_ms,omitempty"`
+	Extra         map[string]string `json:"extra,omitempty"`
+}
+
+// MarshalJSON encodes the metadata as an object with snake_case members, leaving out fields that are not
+// set. The RecordingDate is given in RFC 3339 form as "recording_date" and the Duration in milliseconds
+// as "duration_ms".
+func (md Metadata) MarshalJSON() ([]byte, error) {
+	ret := metadata_json{md.Title, md.Artist, md.Album, md.AlbumArtist, md.Composer, md.Track, md.Disc, md.Genres, "", md.Duration.Milliseconds(), md.Extra}
+	if !md.RecordingDate.IsZero() {
+		ret.RecordingDate = md.RecordingDate.Format(time.RFC3339)
+	}
+	return json.Marshal(ret)
+}
+
+// UnmarshalJSON decodes metadata encoded by MarshalJSON
+func (md *Metadata) UnmarshalJSON(data []byte) error {
+	var in metadata_json
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	ret := Metadata{in.Title, in.Artist, in.Album, in.AlbumArtist, in.Composer, in.Track, in.Disc, in.Genres, time.Time{}, time.Duration(in.DurationMs) * time.Millisecond, in.Extra}
+	if in.RecordingDate != "" {
+		date, err := time.Parse(time.RFC3339, in.RecordingDate)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Recording date %q is not in RFC 3339 form", in.RecordingDate))
+		}
+		ret.RecordingDate = date
+	}
+	*md = ret
+	return nil
+}
+
+// Tag returns a tag of version ver holding the fields of md that are set. Only the year of the
+// RecordingDate is kept.
+func (md Metadata) Tag(ver Version) ID3Tag {
+	id3tag := ID3Tag{Version: ver}
+	for _, field := range []struct{ frameid, value string }{
+		{"TIT2", md.Title}, {"TPE1", md.Artist}, {"TALB", md.Album}, {"TPE2", md.AlbumArtist}, {"TCOM", md.Composer},
+	} {
+		if field.value != "" {
+			id3tag = id3tag.SetText(field.frameid, field.value)
+		}
+	}
+	if md.Track > 0 {
+		id3tag = id3tag.SetText("TRCK", strconv.Itoa(md.Track))
+	}
+	if md.Disc > 0 {
+		id3tag = id3tag.SetText("TPOS", strconv.Itoa(md.Disc))
+	}
+	if len(md.Genres) > 0 {
+		id3tag = id3tag.SetGenres(md.Genres)
+	}
+	if !md.RecordingDate.IsZero() && ver == Version23 {
+		id3tag = id3tag.SetText("TYER", strconv.Itoa(md.RecordingDate.Year()))
+	} else if !md.RecordingDate.IsZero() {
+		id3tag = id3tag.SetText("TDRC", strconv.Itoa(md.RecordingDate.Year()))
+	}
+	if md.Duration > 0 {
+		id3tag = id3tag.SetText("TLEN", strconv.FormatInt(md.Duration.Milliseconds(), 10))
+	}
+	return id3tag
+}
+
+// GetLength returns the length of the audio given in milliseconds by the TLEN frame
+func (id3tag ID3Tag) GetLength() (time.Duration, error) {
+	txt, err := id3tag.GetTextFrameData("TLEN")
+	if err != nil {
+		return 0, err
+	}
+	ms, err := strconv.ParseUint(strings.TrimSpace(txt), 10, 32)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("Length %q is not a number of milliseconds", txt))
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// CommonArtistSeparators are delimiters taggers commonly place between several artists within a single value,
+// for passing to GetArtists
+var CommonArtistSeparators = []string{"; ", " / ", " feat. ", " ft. "}
+
+// GetArtists returns the artists credited in the TPE1 frame. v2.4 frames holding several null separated
+// values yield one artist per value, and each value is further split wherever one of separators occurs,
+// compared without regard to case. Surrounding whitespace and empty names are dropped. nil is returned
+// when the tag has no TPE1 frame or it cannot be decoded.
+func (id3tag ID3Tag) GetArtists(separators ...string) []string {
+	values, err := id3tag.get_text_values("TPE1")
+	if err != nil {
+		return nil
+	}
+	var artists []string
+	for _, value := range values {
+		for _, name := range split_separators(value, separators) {
+			if name = strings.TrimSpace(name); name != "" {
+				artists = append(artists, name)
+			}
+		}
+	}
+	return artists
+}
+
+// split_separators splits str around every occurrence of any of separators, ignoring case
+func split_separators(str string, separators []string) []string {
+	lower := strings.ToLower(str)
+	if len(lower) != len(str) {
+		lower = str //lowercasing moved byte offsets, so match case sensitively
+	}
+	ret := make([]string, 0, 1)
+	start := 0
+	for pos := 0; pos < len(str); {
+		matched := 0
+		for _, sep := range separators {
+			if sep != "" && strings.HasPrefix(lower[pos:], strings.ToLower(sep)) {
+				matched = len(sep)
+				break
+			}
+		}
+		if matched == 0 {
+			pos++
+			continue
+		}
+		ret = append(ret, str[start:pos])
+		pos += matched
+		start = pos
+	}
+	return append(ret, str[start:])
+}
+
+// GetTrack returns the track number and, if the TRCK frame gives it as in "3/12", the number of tracks
+func (id3tag ID3Tag) GetTrack() (track, total int, err error) {
+	return id3tag.get_position("TRCK")
+}
+
+// GetDisc returns the disc number and, if the TPOS frame gives it, the number of discs
+func (id3tag ID3Tag) GetDisc() (disc, total int, err error) {
+	return id3tag.get_position("TPOS")
+}
+
+func (id3tag ID3Tag) get_position(frameid string) (int, int, error) {
+	txt, err := id3tag.GetTextFrameData(frameid)
+	if err != nil {
+		return 0, 0, err
+	}
+	pos, count, has_count := strings.Cut(strings.TrimSpace(txt), "/")
+	n, err := strconv.Atoi(strings.TrimSpace(pos))
+	if err != nil {
+		return 0, 0, errors.New(fmt.Sprintf("Frame %v %q is not a number", frameid, txt))
+	}
+	total := 0
+	if has_count {
+		if total, err = strconv.Atoi(strings.TrimSpace(count)); err != nil {
+			return n, 0, errors.New(fmt.Sprintf("Frame %v %q has an invalid total", frameid, txt))
+		}
+	}
+	return n, total, nil
+}
@@ -0,0 +1,57 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetadataJSON(t *testing.T) {
+	md := Metadata{
+		Title: "Title", AlbumArtist: "Various", Track: 3, Genres: []string{"Rock"},
+		RecordingDate: time.Date(2013, 5, 1, 0, 0, 0, 0, time.UTC), Duration: 215500 * time.Millisecond,
+		Extra: map[string]string{"Mood": "Calm"},
+	}
+	buf, err := json.Marshal(md)
+	if err != nil {
+		t.Fatalf("Error in encoding: %v", err)
+	}
+	want := `{"title":"Title","album_artist":"Various","track":3,"genres":["Rock"],"recording_date":"2013-05-01T00:00:00Z","duration_ms":215500,"extra":{"Mood":"Calm"}}`
+	if string(buf) != want {
+		t.Errorf("Expected %s, got %s", want, buf)
+	}
+	var decoded Metadata
+	if err := json.Unmarshal(buf, &decoded); err != nil || !reflect.DeepEqual(decoded, md) {
+		t.Errorf("Expected %+v, got %+v, %v", md, decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"recording_date":"May 2013"}`), &decoded); err == nil {
+		t.Errorf("Expected an error for a malformed date")
+	}
+}
+
+func TestGetMetadataV22(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Title")),
+		make_frame(2, "TP1", 0, []byte("\x00Artist")),
+		make_frame(2, "TAL", 0, []byte("\x00Album")),
+		make_frame(2, "TCO", 0, []byte("\x00(17)")),
+		make_frame(2, "TRK", 0, []byte("\x003/12")),
+		make_frame(2, "TYE", 0, []byte("\x001999")),
+		make_frame(2, "TDA", 0, []byte("\x000105")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := id3tag.GetMetadata()
+	want := Metadata{Title: "Title", Artist: "Artist", Album: "Album", Track: 3, Genres: []string{"Rock"},
+		RecordingDate: time.Date(1999, 5, 1, 0, 0, 0, 0, time.UTC), Extra: map[string]string{}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("Expected %+v, got %+v", want, md)
+	}
+	if id3tag.Version != Version22 || id3tag.Frames[0].FrameID != "TT2" {
+		t.Errorf("GetMetadata changed the tag")
+	}
+}
@@ -0,0 +1,35 @@
+// Package mmap reads ID3v2 tags out of memory mapped files. Frames returned by File.ReadID3 alias the
+// mapping, so multi-megabyte APIC or GEOB payloads are paged in lazily by the operating system as they
+// are touched instead of being copied into heap slices while a library is being scanned.
+package mmap
+
+import (
+	"github.com/srinathh/id3v2reader"
+)
+
+// A File is an audio file mapped read-only into memory.
+type File struct {
+	data []byte
+}
+
+// Open maps the file at path into memory.
+func Open(path string) (*File, error) {
+	data, err := map_file(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{data}, nil
+}
+
+// ReadID3 reads the ID3v2 tag at the start of the mapped file. The Data of every returned frame points
+// into the mapping and must not be used after Close.
+func (f *File) ReadID3() (id3v2reader.ID3Tag, error) {
+	return id3v2reader.ReadID3Bytes(f.data)
+}
+
+// Close releases the mapping.
+func (f *File) Close() error {
+	data := f.data
+	f.data = nil
+	return unmap_file(data)
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package mmap
+
+import (
+	"os"
+)
+
+// map_file falls back to reading the whole file on platforms without mmap support
+func map_file(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func unmap_file(data []byte) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package mmap
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+func Test(t *testing.T) {
+	filnames := []string{"../testdata/test-v23.mp3", "../testdata/test-v24.mp3"}
+
+	for _, filname := range filnames {
+		mapped, err := Open(filname)
+		if err != nil {
+			t.Fatalf("Error: could not map file %v: %v", filname, err)
+		}
+		mapped_tag, err := mapped.ReadID3()
+		if err != nil {
+			t.Errorf("Error in reading mapped tag: %v", err)
+		}
+
+		fil, err := os.Open(filname)
+		if err != nil {
+			t.Fatalf("Error: could not open file %v: %v", filname, err)
+		}
+		read_tag, err := id3v2reader.ReadID3(fil)
+		fil.Close()
+		if err != nil {
+			t.Errorf("Error in reading tag: %v", err)
+		}
+
+		if len(mapped_tag.Frames) != len(read_tag.Frames) {
+			t.Errorf("%v: mapped tag has %v frames, read tag has %v", filname, len(mapped_tag.Frames), len(read_tag.Frames))
+		} else {
+			for j := range read_tag.Frames {
+				if mapped_tag.Frames[j].FrameID != read_tag.Frames[j].FrameID || !bytes.Equal(mapped_tag.Frames[j].Data, read_tag.Frames[j].Data) {
+					t.Errorf("%v: frame %v differs between mapped and read tags", filname, j)
+				}
+			}
+		}
+		if err := mapped.Close(); err != nil {
+			t.Errorf("Error closing mapping: %v", err)
+		}
+	}
+}
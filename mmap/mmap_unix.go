@@ -0,0 +1,39 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+)
+
+func map_file(path string) ([]byte, error) {
+	fil, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fil.Close()
+
+	info, err := fil.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, nil
+	}
+	if int64(int(info.Size())) != info.Size() {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: syscall.EFBIG}
+	}
+	data, err := syscall.Mmap(int(fil.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return data, nil
+}
+
+func unmap_file(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return syscall.Munmap(data)
+}
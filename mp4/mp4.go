@@ -0,0 +1,268 @@
+// Package mp4 reads the iTunes style metadata of MP4 and M4A files into the Metadata of the id3v2reader
+// package, so libraries mixing .mp3 and .m4a files can be handled through one type. Importing the
+// package registers the "mp4" format with id3v2reader.ReadMetadata.
+package mp4
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/srinathh/id3v2reader"
+	"github.com/srinathh/id3v2reader/internal/dates"
+)
+
+// Info is what ReadInfo finds in the metadata of an MP4 file
+type Info struct {
+	Metadata id3v2reader.Metadata
+	Pictures []id3v2reader.Picture //the cover art of the covr item, as FrontCover
+}
+
+// max_item_size caps how much of a metadata list is read into memory, which is mostly cover art
+const max_item_size = 64 << 20
+
+func init() {
+	id3v2reader.RegisterFormat("mp4", "????ftyp", ReadMetadata, ".m4a", ".m4b", ".mp4")
+}
+
+type atom struct {
+	kind   string
+	offset int64 //offset of the atom body
+	size   int64 //size of the atom body
+}
+
+// ReadMetadata reads the Metadata of an MP4 file
+func ReadMetadata(r io.ReadSeeker) (id3v2reader.Metadata, error) {
+	info, err := ReadInfo(r)
+	return info.Metadata, err
+}
+
+// ReadInfo reads the title, artist, album, composer, track and disc numbers, genres, release date and
+// cover art from the ilst atom of an MP4 file and the duration from its mvhd atom. The media data is
+// skipped over, not read.
+func ReadInfo(r io.ReadSeeker) (Info, error) {
+	info := Info{Pictures: make([]id3v2reader.Picture, 0)}
+	md := &info.Metadata
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return info, err
+	}
+	top, err := read_atoms(r, 0, end)
+	if err != nil {
+		return info, err
+	}
+	if len(top) == 0 || top[0].kind != "ftyp" {
+		return info, errors.New("Not an MP4 file")
+	}
+	moov, ok := find(top, "moov")
+	if !ok {
+		return info, errors.New("MP4 file has no moov atom")
+	}
+	children, err := read_atoms(r, moov.offset, moov.offset+moov.size)
+	if err != nil {
+		return info, err
+	}
+	if mvhd, ok := find(children, "mvhd"); ok {
+		md.Duration, _ = read_duration(r, mvhd)
+	}
+
+	udta, ok := find(children, "udta")
+	if !ok {
+		return info, nil
+	}
+	if children, err = read_atoms(r, udta.offset, udta.offset+udta.size); err != nil {
+		return info, err
+	}
+	meta, ok := find(children, "meta")
+	if !ok || meta.size < 4 {
+		return info, nil
+	}
+	//meta is a full atom with four bytes of version and flags before its children
+	if children, err = read_atoms(r, meta.offset+4, meta.offset+meta.size); err != nil {
+		return info, err
+	}
+	ilst, ok := find(children, "ilst")
+	if !ok {
+		return info, nil
+	}
+	items, err := read_atoms(r, ilst.offset, ilst.offset+ilst.size)
+	if err != nil {
+		return info, err
+	}
+	for _, item := range items {
+		if item.kind == "covr" {
+			info.Pictures = append(info.Pictures, read_covers(r, item)...)
+			continue
+		}
+		value, err := read_item(r, item)
+		if err != nil {
+			continue
+		}
+		switch item.kind {
+		case "\xa9nam":
+			md.Title = string(value)
+		case "\xa9ART":
+			md.Artist = string(value)
+		case "\xa9alb":
+			md.Album = string(value)
+		case "aART":
+			md.AlbumArtist = string(value)
+		case "\xa9wrt":
+			md.Composer = string(value)
+		case "\xa9gen":
+			md.Genres = append(md.Genres, string(value))
+		case "gnre":
+			//ID3v1 genre numbers plus one
+			if len(value) == 2 {
+				if name, ok := id3v2reader.GenreName(int(binary.BigEndian.Uint16(value)) - 1); ok {
+					md.Genres = append(md.Genres, name)
+				}
+			}
+		case "trkn", "disk":
+			//two reserved bytes, then the number and the total as 16 bit integers
+			if len(value) >= 4 && item.kind == "trkn" {
+				md.Track = int(binary.BigEndian.Uint16(value[2:4]))
+			} else if len(value) >= 4 {
+				md.Disc = int(binary.BigEndian.Uint16(value[2:4]))
+			}
+		case "\xa9day":
+			md.RecordingDate, _ = dates.Parse(string(value))
+		}
+	}
+	return info, nil
+}
+
+// read_atoms returns the atoms between start and end
+func read_atoms(r io.ReadSeeker, start, end int64) ([]atom, error) {
+	ret := make([]atom, 0)
+	header := make([]byte, 8)
+	for pos := start; pos+8 <= end; {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		kind := string(header[4:8])
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		headersize := int64(8)
+		switch size {
+		case 0:
+			//the atom runs to the end of its parent
+			size = end - pos
+		case 1:
+			//a 64 bit size follows the type
+			if _, err := io.ReadFull(r, header); err != nil {
+				return nil, err
+			}
+			size = int64(binary.BigEndian.Uint64(header))
+			headersize = 16
+		}
+		if size < headersize || size > end-pos {
+			return nil, errors.New(fmt.Sprintf("Malformed MP4 atom at offset %d", pos))
+		}
+		ret = append(ret, atom{kind, pos + headersize, size - headersize})
+		pos += size
+	}
+	return ret, nil
+}
+
+func find(atoms []atom, kind string) (atom, bool) {
+	for _, a := range atoms {
+		if a.kind == kind {
+			return a, true
+		}
+	}
+	return atom{}, false
+}
+
+// read_item returns the value of the first data atom of a metadata item
+func read_item(r io.ReadSeeker, item atom) ([]byte, error) {
+	children, err := read_atoms(r, item.offset, item.offset+item.size)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := find(children, "data")
+	if !ok {
+		return nil, errors.New("Metadata item has no usable data atom")
+	}
+	_, value, err := read_data(r, data)
+	return value, err
+}
+
+// read_data returns the type and the value of a data atom, whose value follows four bytes of type and
+// four of locale
+func read_data(r io.ReadSeeker, data atom) (uint32, []byte, error) {
+	if data.size < 8 || data.size > max_item_size {
+		return 0, nil, errors.New("Metadata item has no usable data atom")
+	}
+	buf := make([]byte, data.size)
+	if _, err := r.Seek(data.offset, io.SeekStart); err != nil {
+		return 0, nil, err
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint32(buf[0:4]) & 0xFFFFFF, buf[8:len(buf)], nil
+}
+
+// read_covers returns the images of the data atoms of a covr item, skipping those that cannot be read
+func read_covers(r io.ReadSeeker, item atom) []id3v2reader.Picture {
+	ret := make([]id3v2reader.Picture, 0)
+	children, err := read_atoms(r, item.offset, item.offset+item.size)
+	if err != nil {
+		return ret
+	}
+	for _, data := range children {
+		if data.kind != "data" {
+			continue
+		}
+		kind, value, err := read_data(r, data)
+		if err != nil {
+			continue
+		}
+		mime := "image/jpeg"
+		switch kind {
+		case 14:
+			mime = "image/png"
+		case 27:
+			mime = "image/bmp"
+		}
+		ret = append(ret, id3v2reader.Picture{MIMEType: mime, Type: id3v2reader.FrontCover, Data: value})
+	}
+	return ret
+}
+
+// read_duration returns the duration given by the movie header
+func read_duration(r io.ReadSeeker, mvhd atom) (time.Duration, error) {
+	buf := make([]byte, 32)
+	if mvhd.size < 20 {
+		return 0, errors.New("mvhd atom is too short")
+	}
+	if mvhd.size < 32 {
+		buf = buf[0:mvhd.size]
+	}
+	if _, err := r.Seek(mvhd.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+	var timescale, duration uint64
+	if buf[0] == 1 {
+		if len(buf) < 32 {
+			return 0, errors.New("mvhd atom is too short")
+		}
+		timescale = uint64(binary.BigEndian.Uint32(buf[20:24]))
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(buf[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+	if timescale == 0 {
+		return 0, errors.New("mvhd atom has no timescale")
+	}
+	return time.Duration(duration/timescale)*time.Second + time.Duration(duration%timescale*uint64(time.Second)/timescale), nil
+}
@@ -0,0 +1,84 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+func make_atom(kind string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	buf := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(8+len(body)))
+	copy(buf[4:8], kind)
+	return append(buf, body...)
+}
+
+func make_item(kind string, value []byte) []byte {
+	return make_atom(kind, make_atom("data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, value))
+}
+
+func TestReadMetadata(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 215500)
+	file := bytes.Join([][]byte{
+		make_atom("ftyp", []byte("M4A \x00\x00\x00\x00")),
+		make_atom("moov",
+			make_atom("mvhd", mvhd),
+			make_atom("udta", make_atom("meta", []byte{0, 0, 0, 0}, make_atom("hdlr", make([]byte, 25)), make_atom("ilst",
+				make_item("\xa9nam", []byte("Title")),
+				make_item("\xa9ART", []byte("Artist")),
+				make_item("\xa9alb", []byte("Album")),
+				make_item("\xa9wrt", []byte("Composer")),
+				make_item("gnre", []byte{0, 18}),
+				make_item("\xa9day", []byte("2013-05-01T00:00:00Z")),
+				make_item("trkn", []byte{0, 0, 0, 3, 0, 12, 0, 0}),
+				make_item("disk", []byte{0, 0, 0, 2, 0, 2}),
+				make_atom("covr",
+					make_atom("data", []byte{0, 0, 0, 14, 0, 0, 0, 0}, []byte("\x89PNG")),
+					make_atom("data", []byte{0, 0, 0, 13, 0, 0, 0, 0}, []byte("\xff\xd8")),
+				),
+			))),
+		),
+		make_atom("mdat", make([]byte, 1000)),
+	}, nil)
+
+	info, err := ReadInfo(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("Error in reading metadata: %v", err)
+	}
+	md := info.Metadata
+	if md.Title != "Title" || md.Artist != "Artist" || md.Album != "Album" || md.Composer != "Composer" || md.Track != 3 || md.Disc != 2 ||
+		!reflect.DeepEqual(md.Genres, []string{"Rock"}) || md.RecordingDate.Year() != 2013 || md.Duration != 215500*time.Millisecond {
+		t.Errorf("Unexpected metadata %+v", md)
+	}
+	want := []id3v2reader.Picture{
+		{MIMEType: "image/png", Type: id3v2reader.FrontCover, Data: []byte("\x89PNG")},
+		{MIMEType: "image/jpeg", Type: id3v2reader.FrontCover, Data: []byte("\xff\xd8")},
+	}
+	if !reflect.DeepEqual(info.Pictures, want) {
+		t.Errorf("Unexpected pictures %+v", info.Pictures)
+	}
+
+	if _, err := ReadMetadata(bytes.NewReader([]byte("ID3\x04\x00\x00\x00\x00\x00\x00"))); err == nil {
+		t.Errorf("Expected an error for a file that is not MP4")
+	}
+	if _, err := ReadMetadata(bytes.NewReader(file[0 : len(file)-1])); err == nil {
+		t.Errorf("Expected an error for a truncated file")
+	}
+}
+
+func TestRegisteredFormat(t *testing.T) {
+	file := bytes.Join([][]byte{
+		make_atom("ftyp", []byte("M4A \x00\x00\x00\x00")),
+		make_atom("moov", make_atom("udta", make_atom("meta", []byte{0, 0, 0, 0}, make_atom("ilst", make_item("\xa9nam", []byte("Title")))))),
+	}, nil)
+	if md, format, err := id3v2reader.ReadMetadata(bytes.NewReader(file)); err != nil || format != "mp4" || md.Title != "Title" {
+		t.Errorf("Unexpected result %v %q, %v", format, md.Title, err)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		txt  string
+		n    Normalization
+		want string
+	}{
+		{"Bjo\u0308rk", 0, "Bj\u00f6rk"},
+		{"Bj\u00f6rk", 0, "Bj\u00f6rk"},
+		{"Bjo\u0308rk", StripDiacritics, "Bjork"},
+		{"Bj\u00d6RK", FoldCase | StripDiacritics, "bjork"},
+		{"Stra\u00dfe", FoldCase, "strasse"},
+		{"Sigur Ro\u0301s \u00c6gis \u00d8", StripDiacritics, "Sigur Ros AEgis O"},
+		{"Vie\u0323\u0302t", 0, "Vi\u1ec7t"},
+		{"Vi\u1ec7t", StripDiacritics, "Viet"},
+		{"\u0438\u0306", 0, "\u0438\u0306"}, //only Latin letters are composed
+	}
+	for _, c := range cases {
+		if got := Normalize(c.txt, c.n); got != c.want {
+			t.Errorf("Normalize(%q, %v) = %q, expected %q", c.txt, c.n, got, c.want)
+		}
+	}
+}
+
+func TestNormalizedText(t *testing.T) {
+	raw := make_tag(4, make_frame(4, "TPE1", 0, []byte("\x03Bjo\xcc\x88rk")))
+	id3tag, err := ReadID3Bytes(raw)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if id3tag.NormalizedText() != nil {
+		t.Errorf("Expected no normalized text without the option")
+	}
+	id3tag, err = ReadID3Bytes(raw, WithNormalizedText(FoldCase|StripDiacritics))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if got := id3tag.NormalizedText()["TPE1"]; len(got) != 1 || got[0] != "bjork" {
+		t.Errorf("Unexpected normalized artist %q", got)
+	}
+	if artist, _ := id3tag.GetArtist(); artist != "Bjo\u0308rk" {
+		t.Errorf("Expected the original text to be kept, got %q", artist)
+	}
+
+	data, err := id3tag.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ID3Tag
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.NormalizedText()["TPE1"]; len(got) != 1 || got[0] != "bjork" {
+		t.Errorf("Unexpected normalized artist %q after unmarshaling", got)
+	}
+}
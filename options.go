@@ -0,0 +1,159 @@
+package id3v2reader
+
+import "strings"
+
+// An Option changes how ReadID3 and its variants parse a tag.
+type Option func(*read_config)
+
+type read_config struct {
+	progress           func(Progress)
+	no_utf16_guess     bool
+	allow_experimental bool
+	trim_text          bool
+	lenient_frameids   bool
+	on_frame           func(ID3Frame) error //set by ReadID3Func
+	only_frames        []string
+	single_read        bool
+	strict             bool
+	normalize          bool
+	normalization      Normalization
+	alias_tag_data     bool
+	scan_limit         int
+	header_offset      int                                 //set when the header was found by scanning
+	on_truncated       func(frame ID3Frame, offset uint64) //set by ReadPartialID3, offset is where the frame content starts within the tag
+}
+
+func new_read_config(opts []Option) read_config {
+	var cfg read_config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// wants_frame reports whether frames with the given ID are to be read
+func (cfg read_config) wants_frame(frameid string) bool {
+	if cfg.only_frames == nil {
+		return true
+	}
+	for _, pattern := range cfg.only_frames {
+		if match_frameid(pattern, frameid) {
+			return true
+		}
+	}
+	return false
+}
+
+// match_frameid reports whether frameid is pattern, or starts with pattern less its "*" if it ends in one
+func match_frameid(pattern, frameid string) bool {
+	return pattern == frameid || strings.HasSuffix(pattern, "*") && strings.HasPrefix(frameid, pattern[0:len(pattern)-1])
+}
+
+// Progress reports how far parsing of a tag has got.
+type Progress struct {
+	BytesRead uint32 //tag bytes consumed so far, not counting the 10 byte tag header
+	TagSize   uint32 //tag size declared in the tag header
+	Frames    int    //frames read so far
+}
+
+// WithProgress calls fn after every frame is read, so that applications reading very large tags
+// (audiobooks with hundreds of chapters and images) can report progress while ReadID3 runs.
+// fn is called on the goroutine calling ReadID3.
+func WithProgress(fn func(Progress)) Option {
+	return func(cfg *read_config) {
+		cfg.progress = fn
+	}
+}
+
+// WithUTF16Guessing controls what happens to UTF-16 text (encoding byte 1) that lacks the byte order mark
+// the standard requires. By default the byte order is guessed from where the zero bytes fall so that such
+// text still decodes; disabling the guess makes decoding it fail instead.
+func WithUTF16Guessing(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.no_utf16_guess = !enabled
+	}
+}
+
+// WithExperimentalTags controls whether tags whose header sets the experimental indicator are read.
+// They are rejected by default, but experimental tags are structurally identical to ordinary ones and
+// several encoder betas set the bit on perfectly ordinary tags, so allowing them reads such tags
+// normally with Experimental set on the result.
+func WithExperimentalTags(allowed bool) Option {
+	return func(cfg *read_config) {
+		cfg.allow_experimental = allowed
+	}
+}
+
+// WithTextTrimming controls whether decoded text is cleaned up: trailing whitespace and null padding, which
+// many old taggers fill fixed size fields with, is removed and every run of other control characters
+// within the text is replaced by a single space. Line breaks are kept for the sake of lyrics and comments.
+// Text is returned exactly as stored by default.
+func WithTextTrimming(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.trim_text = enabled
+	}
+}
+
+// WithLenientFrameIDs controls whether frames whose IDs contain lowercase letters, as a few broken taggers
+// write them, are read. By default such a frame ends the tag like padding would. The IDs are kept as
+// written; the Lenient lookup option matches them regardless of case.
+func WithLenientFrameIDs(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.lenient_frameids = enabled
+	}
+}
+
+// OnlyFrames restricts reading to the frames whose IDs match one of patterns, each a FrameID or a prefix
+// ending in "*" as taken by Reorder. The payloads of all other frames are skipped without being copied into
+// memory, by seeking past them when the reader implements io.Seeker, which saves most of the I/O of scans
+// for text frames over files carrying large pictures.
+func OnlyFrames(patterns ...string) Option {
+	return func(cfg *read_config) {
+		cfg.only_frames = append(make([]string, 0, len(patterns)), patterns...)
+	}
+}
+
+// WithSingleRead controls how ReadID3 and ReadID3Func read from their reader. By default every header
+// and frame is read separately, so that no more than one frame is held in memory beyond the result and
+// skipped payloads are never read; readers with costly Read calls should then be wrapped in a
+// bufio.Reader. In single read mode the whole tag the header declares is read into one buffer with a
+// single call and parsed from memory as ReadID3Bytes does, so that the frames returned share that buffer.
+func WithSingleRead(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.single_read = enabled
+	}
+}
+
+// WithStrictParsing controls what happens when a tag is malformed in a way the parser can work around. By
+// default the parser works around the problem and describes it in the tag's Warnings: frames too short for
+// the fields their flags declare are dropped, v2.4 frame sizes that are not synchsafe, as a few taggers
+// wrote them, are read as plain integers, frame sizes that lead nowhere are read the other way when that
+// leads to the next frame, frames declaring more bytes than remain in the tag are cut short at its end,
+// undefined tag header flags are ignored, and tags cut short by the end of the input yield the frames
+// before the cut. Other errors of the reader always fail the read. Checking where a frame leads needs reading ahead, which is
+// only done when the reader implements io.Seeker or the tag is read with WithSingleRead or ReadID3Bytes.
+// In strict mode such problems fail the read instead.
+func WithStrictParsing(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.strict = enabled
+	}
+}
+
+// WithAliasedTagData controls whether GetTagData, GetPictures and GetCoverPic return the frame data of the
+// tag itself rather than copies of it, which saves copying large frames for callers that only read the data. Changing data
+// obtained this way changes the tag and every copy of it.
+func WithAliasedTagData(enabled bool) Option {
+	return func(cfg *read_config) {
+		cfg.alias_tag_data = enabled
+	}
+}
+
+// ScanForHeader makes reading look for the tag header within the first maxOffset bytes when the data does
+// not start with one, to recover the tags of files with a few bytes of junk or an APE tag in front. The
+// junk skipped is noted in the Warnings of the tag. Readers that cannot seek lose the ability to skip
+// frames without reading them once junk was skipped.
+func ScanForHeader(maxOffset int) Option {
+	return func(cfg *read_config) {
+		cfg.scan_limit = maxOffset
+	}
+}
@@ -0,0 +1,305 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	fil, err := os.Open("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fil.Close()
+
+	reports := make([]Progress, 0)
+	if _, err := ReadID3(fil, WithProgress(func(p Progress) { reports = append(reports, p) })); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if len(reports) != 11 {
+		t.Fatalf("Expected a progress report for each of the 11 frames, got %v", len(reports))
+	}
+	for j, report := range reports {
+		if report.Frames != j+1 || report.TagSize != 6134 {
+			t.Errorf("Unexpected progress report %+v", report)
+		}
+		if j > 0 && report.BytesRead <= reports[j-1].BytesRead {
+			t.Errorf("Progress went backwards: %+v after %+v", report, reports[j-1])
+		}
+	}
+}
+
+func TestUTF16Guessing(t *testing.T) {
+	cases := map[string][]byte{
+		"little endian": []byte("\x01T\x00i\x00t\x00l\x00\xe9\x00\x00\x00"),
+		"big endian":    []byte("\x01\x00T\x00i\x00t\x00l\x00\xe9\x00\x00"),
+	}
+	for name, data := range cases {
+		raw := make_tag(3, make_frame(3, "TIT2", 0, data))
+		id3tag, err := ReadID3(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", name, err)
+		}
+		if title, err := id3tag.GetTitle(); err != nil || title != "Titl\u00e9" {
+			t.Errorf("%v: expected the title to decode without a BOM, got %q, %v", name, title, err)
+		}
+
+		id3tag, err = ReadID3(bytes.NewReader(raw), WithUTF16Guessing(false))
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", name, err)
+		}
+		if _, err := id3tag.GetTitle(); err == nil {
+			t.Errorf("%v: expected decoding to fail with guessing disabled", name)
+		}
+	}
+
+	//an empty description has no BOM to miss
+	raw := make_tag(3, make_frame(3, "COMM", 0, []byte("\x01eng\x00\x00\xff\xfeh\x00i\x00")))
+	id3tag, err := ReadID3(bytes.NewReader(raw), WithUTF16Guessing(false))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if comments, err := id3tag.GetComments(); err != nil || len(comments) != 1 || comments[0].Text != "hi" || comments[0].Description != "" {
+		t.Errorf("Expected the comment with an empty description, got %+v, %v", comments, err)
+	}
+}
+
+func TestExperimentalTags(t *testing.T) {
+	raw := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")))
+	raw[5] |= 0x20
+
+	if _, err := ReadID3Bytes(raw); err == nil {
+		t.Errorf("Expected experimental tags to be rejected by default")
+	}
+	id3tag, err := ReadID3Bytes(raw, WithExperimentalTags(true))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if !id3tag.Experimental {
+		t.Errorf("Expected the tag to be flagged experimental")
+	}
+	if title, _ := id3tag.GetTitle(); title != "Title" {
+		t.Errorf("Unexpected title %q", title)
+	}
+}
+
+func TestTextTrimming(t *testing.T) {
+	tag := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title   ")),
+		make_frame(4, "TPE1", 0, []byte("\x01\xff\xfeA\x00\x09\x00\x0b\x00B\x00 \x00\x00\x00\x00\x00")),
+		make_frame(4, "USLT", 0, []byte("\x03eng\x00Line one\nLine two\n\n")),
+	)
+	for _, trim := range []bool{false, true} {
+		id3tag, err := ReadID3Bytes(tag, WithTextTrimming(trim))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		title, _ := id3tag.GetTitle()
+		artist, _ := id3tag.GetArtist()
+		lyrics, _ := id3tag.GetLyricsByLanguage("eng")
+		if trim && (title != "Title" || artist != "A B" || lyrics != "Line one\nLine two") {
+			t.Errorf("Unexpected trimmed text %q %q %q", title, artist, lyrics)
+		}
+		if !trim && (title != "Title   " || artist != "A\t\vB " || lyrics != "Line one\nLine two\n\n") {
+			t.Errorf("Text changed without trimming: %q %q %q", title, artist, lyrics)
+		}
+	}
+}
+
+// read_counter counts the bytes read through it. It does not seek even if rd does.
+type read_counter struct {
+	rd    io.Reader
+	count int
+}
+
+func (rc *read_counter) Read(p []byte) (int, error) {
+	n, err := rc.rd.Read(p)
+	rc.count += n
+	return n, err
+}
+
+// seek_counter is a read_counter that seeks
+type seek_counter struct {
+	read_counter
+	seeker io.Seeker
+}
+
+func (sc *seek_counter) Seek(offset int64, whence int) (int64, error) {
+	return sc.seeker.Seek(offset, whence)
+}
+
+func TestOnlyFrames(t *testing.T) {
+	raw, err := os.ReadFile("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := &read_counter{rd: bytes.NewReader(raw)}
+	id3tag, err := ReadID3(plain, OnlyFrames("TIT2", "TP*"))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID != "TIT2" && frame.FrameID[0:2] != "TP" {
+			t.Errorf("Unexpected frame %v read", frame.FrameID)
+		}
+	}
+	if title, _ := id3tag.GetTitle(); title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" {
+		t.Errorf("Unexpected title %q", title)
+	}
+
+	rd := bytes.NewReader(raw)
+	seeking := &seek_counter{read_counter{rd: rd}, rd}
+	if _, err := ReadID3(seeking, OnlyFrames("TIT2", "TP*")); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if seeking.count >= plain.count/2 {
+		t.Errorf("Expected seeking to skip the picture, read %v bytes against %v", seeking.count, plain.count)
+	}
+}
+
+func TestSingleRead(t *testing.T) {
+	raw, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ReadID3(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	rd := bytes.NewReader(raw)
+	got, err := ReadID3(rd, WithSingleRead(true))
+	if err != nil {
+		t.Fatalf("Error in reading tag in single read mode: %v", err)
+	}
+	if !reflect.DeepEqual(got.Frames, want.Frames) || got.PaddingBytes != want.PaddingBytes {
+		t.Errorf("Single read mode read a different tag")
+	}
+	if rest := rd.Len(); rest != len(raw)-10-int(want.Size) {
+		t.Errorf("Expected the reader to be left after the tag, %v bytes remain", rest)
+	}
+
+	truncated, err := ReadID3(bytes.NewReader(raw[0:200]), WithSingleRead(true))
+	if err != nil || truncated.UnparsedBytes == 0 {
+		t.Errorf("Expected a truncated tag to be read partially, got %v unparsed bytes, %v", truncated.UnparsedBytes, err)
+	}
+	if _, err := ReadID3(bytes.NewReader([]byte("RIFF")), WithSingleRead(true)); err == nil {
+		t.Errorf("Expected an error for a file without a tag")
+	}
+}
+
+func TestStrictParsing(t *testing.T) {
+	plain_size := make_frame(4, "TIT2", 0, []byte("\x03Title"))
+	copy(plain_size[4:8], []byte{0, 0, 0, 0x80}) //a 128 byte size written as a plain integer
+	plain_size = append(plain_size, make([]byte, 128-6)...)
+	oversized := make_frame(4, "TIT2", 0, []byte("\x03Title"))
+	copy(oversized[4:8], synchsafe(100))
+	cases := map[string][]byte{
+		"oversized frame":  make_tag(4, oversized),
+		"short frame":      make_tag(4, make_frame(4, "GEOB", 0x01, []byte{0}), make_frame(4, "TIT2", 0, []byte("\x03Title"))),
+		"plain frame size": make_tag(4, plain_size),
+	}
+	for name, raw := range cases {
+		id3tag, err := ReadID3Bytes(raw)
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", name, err)
+		}
+		if len(id3tag.Warnings) != 1 {
+			t.Errorf("%v: expected a warning, got %q", name, id3tag.Warnings)
+		}
+		if title, _ := id3tag.GetTitle(); title != "Title" {
+			t.Errorf("%v: unexpected title %q", name, title)
+		}
+		if strict, err := ReadID3Bytes(raw, WithStrictParsing(true)); err == nil {
+			if artist, _ := strict.GetArtist(); artist == "Artist" {
+				t.Errorf("%v: expected no correction in strict mode", name)
+			}
+		}
+	}
+
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title"))), WithStrictParsing(true))
+	if err != nil || len(id3tag.Warnings) != 0 {
+		t.Errorf("Expected a well formed tag to read cleanly, got %q, %v", id3tag.Warnings, err)
+	}
+}
+
+func TestFrameSizeHeuristic(t *testing.T) {
+	long := []byte("\x00" + strings.Repeat("a", 255))
+	v23 := make_frame(3, "TIT2", 0, long)
+	copy(v23[4:8], synchsafe(256))
+	v24 := make_frame(4, "TIT2", 0, append([]byte{3}, long[1:]...))
+	copy(v24[4:8], []byte{0, 0, 1, 0}) //256 as a plain integer, 128 read as synchsafe
+	cases := map[string][]byte{
+		"v2.3 synchsafe size": make_tag(3, v23, make_frame(3, "TPE1", 0, []byte("\x00Artist"))),
+		"v2.4 plain size":     make_tag(4, v24, make_frame(4, "TPE1", 0, []byte("\x03Artist"))),
+	}
+	for name, raw := range cases {
+		for _, rd := range []io.Reader{bytes.NewReader(raw), &seek_counter{read_counter{rd: bytes.NewReader(raw)}, nil}} {
+			if sc, ok := rd.(*seek_counter); ok {
+				sc.seeker = sc.rd.(io.Seeker)
+			}
+			id3tag, err := ReadID3(rd)
+			if err != nil {
+				t.Fatalf("%v: error in reading tag: %v", name, err)
+			}
+			title, _ := id3tag.GetTitle()
+			artist, _ := id3tag.GetArtist()
+			if title != string(long[1:]) || artist != "Artist" || len(id3tag.Warnings) != 1 {
+				t.Errorf("%v: unexpected title %q, artist %q, warnings %q", name, title, artist, id3tag.Warnings)
+			}
+		}
+		if id3tag, err := ReadID3(&read_counter{rd: bytes.NewReader(raw)}); err == nil && len(id3tag.Warnings) == 0 {
+			if artist, _ := id3tag.GetArtist(); artist == "Artist" {
+				t.Errorf("%v: expected no correction without reading ahead", name)
+			}
+		}
+		if strict, err := ReadID3Bytes(raw, WithStrictParsing(true)); err == nil {
+			if artist, _ := strict.GetArtist(); artist == "Artist" {
+				t.Errorf("%v: expected no correction in strict mode", name)
+			}
+		}
+	}
+}
+
+func TestScanForHeader(t *testing.T) {
+	tag, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := append([]byte("APETAGEX junk before the ID3 header"), tag...)
+	junk := len(raw) - len(tag)
+
+	if _, err := ReadID3Bytes(raw); err != ErrNoTag {
+		t.Errorf("Expected ErrNoTag without scanning, got %v", err)
+	}
+	if _, err := ReadID3Bytes(raw, ScanForHeader(junk-1)); err != ErrNoTag {
+		t.Errorf("Expected ErrNoTag when scanning too little, got %v", err)
+	}
+	seeking := bytes.NewReader(raw)
+	for name, read := range map[string]func() (ID3Tag, error){
+		"bytes":  func() (ID3Tag, error) { return ReadID3Bytes(raw, ScanForHeader(1024)) },
+		"seeker": func() (ID3Tag, error) { return ReadID3(seeking, ScanForHeader(1024)) },
+		"plain":  func() (ID3Tag, error) { return ReadID3(&read_counter{rd: bytes.NewReader(raw)}, ScanForHeader(1024)) },
+		"single read": func() (ID3Tag, error) {
+			return ReadID3(&read_counter{rd: bytes.NewReader(raw)}, ScanForHeader(1024), WithSingleRead(true))
+		},
+	} {
+		id3tag, err := read()
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", name, err)
+		}
+		if title, _ := id3tag.GetTitle(); title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" || len(id3tag.Warnings) != 1 {
+			t.Errorf("%v: unexpected title %q, warnings %q", name, title, id3tag.Warnings)
+		}
+	}
+	if rest := seeking.Len(); rest != len(tag)-10-6134 {
+		t.Errorf("Expected the reader to be left after the tag, %v bytes remain", rest)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+// A TruncatedFrame is a frame of which only the start was available.
+type TruncatedFrame struct {
+	FrameID   string
+	Length    uint32 //content size declared in the frame header
+	Available uint32 //bytes of the content that were available
+}
+
+// A PartialTag is what ReadPartialID3 found of a tag in the first bytes of a file. Tag holds the frames that
+// were fully available. Truncated is the frame the data ended in, if it ended within the content of one;
+// the frames after it are unknown. Needed is the number of bytes from the start of the data that the whole
+// tag, footer included, takes up, and Available the number of bytes there were.
+type PartialTag struct {
+	Tag       ID3Tag
+	Truncated *TruncatedFrame
+	Needed    int64
+	Available int64
+}
+
+// Complete reports whether the whole tag was available.
+func (partial PartialTag) Complete() bool {
+	return partial.Available >= partial.Needed
+}
+
+// ReadPartialID3 reads the tag at the start of buf, which may hold only the first bytes of a file as a
+// partial download does, so that metadata can be shown before the transfer completes. Frames are read as
+// far as buf goes, like ReadID3Bytes reads truncated tags, and the result tells which frame was cut short
+// and how much of the file the tag needs. It fails only when buf does not hold a whole tag header, or
+// the extended header where there is one.
+func ReadPartialID3(buf []byte, opts ...Option) (PartialTag, error) {
+	cfg := new_read_config(opts)
+	if cfg.header_offset = find_header(buf, cfg.scan_limit); cfg.header_offset > 0 {
+		buf = buf[cfg.header_offset:len(buf)]
+	}
+
+	partial := PartialTag{Available: int64(cfg.header_offset + len(buf))}
+	cfg.on_truncated = func(frame ID3Frame, offset uint64) {
+		truncated := TruncatedFrame{FrameID: frame.FrameID, Length: frame.Length}
+		if offset < uint64(len(buf)) {
+			truncated.Available = uint32(uint64(len(buf)) - offset)
+		}
+		partial.Truncated = &truncated
+	}
+	id3tag, err := read_tag(&bytes_source{buf}, cfg)
+	if err != nil {
+		return PartialTag{}, err
+	}
+	partial.Tag = id3tag
+	partial.Needed = int64(cfg.header_offset) + 10 + int64(id3tag.Size)
+	if id3tag.Footer {
+		partial.Needed += 10
+	}
+	return partial, nil
+}
@@ -0,0 +1,56 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadPartialID3(t *testing.T) {
+	tag := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "TPE1", 0, []byte("\x03Artist")),
+		make_frame(4, "APIC", 0, append([]byte("\x00image/png\x00\x03\x00"), make([]byte, 100)...)),
+		make_frame(4, "TALB", 0, []byte("\x03Album")),
+	)
+	file := append(tag, "audio"...)
+	apic_content := 10 + 16 + 17 + 10
+
+	cases := []struct {
+		name      string
+		length    int
+		frames    []string
+		truncated *TruncatedFrame
+		complete  bool
+	}{
+		{"whole file", len(file), []string{"TIT2", "TPE1", "APIC", "TALB"}, nil, true},
+		{"whole tag", len(tag), []string{"TIT2", "TPE1", "APIC", "TALB"}, nil, true},
+		{"within a picture", apic_content + 40, []string{"TIT2", "TPE1"}, &TruncatedFrame{"APIC", 113, 40}, false},
+		{"within a frame header", apic_content - 4, []string{"TIT2", "TPE1"}, nil, false},
+		{"header only", 10, []string{}, nil, false},
+	}
+	for _, c := range cases {
+		partial, err := ReadPartialID3(file[0:c.length])
+		if err != nil {
+			t.Fatalf("%v: error in reading tag: %v", c.name, err)
+		}
+		if got := partial.Tag.Order(); !reflect.DeepEqual(got, c.frames) {
+			t.Errorf("%v: expected frames %v, got %v", c.name, c.frames, got)
+		}
+		if !reflect.DeepEqual(partial.Truncated, c.truncated) {
+			t.Errorf("%v: expected truncated frame %+v, got %+v", c.name, c.truncated, partial.Truncated)
+		}
+		if partial.Complete() != c.complete || partial.Needed != int64(len(tag)) || partial.Available != int64(c.length) {
+			t.Errorf("%v: complete %v, needed %v of %v", c.name, partial.Complete(), partial.Needed, partial.Available)
+		}
+	}
+
+	if _, err := ReadPartialID3(file[0:6]); err == nil {
+		t.Errorf("Expected an error without a whole tag header")
+	}
+	partial, err := ReadPartialID3(append([]byte("junk"), file[0:40]...), ScanForHeader(16))
+	if err != nil || partial.Needed != int64(4+len(tag)) || len(partial.Tag.Frames) != 1 || partial.Truncated == nil || partial.Truncated.Available != 4 {
+		t.Errorf("Unexpected partial tag after junk %+v, %v", partial, err)
+	}
+}
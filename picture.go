@@ -0,0 +1,168 @@
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// PictureType is the type of an attached picture as given in the APIC frame
+type PictureType byte
+
+const (
+	OtherPicture PictureType = iota
+	FileIcon                 //32x32 pixels PNG file icon
+	OtherFileIcon
+	FrontCover
+	BackCover
+	LeafletPage
+	Media //label side of a CD, for example
+	LeadArtist
+	Artist
+	Conductor
+	Band
+	Composer
+	Lyricist
+	RecordingLocation
+	DuringRecording
+	DuringPerformance
+	VideoScreenCapture
+	BrightColouredFish
+	Illustration
+	BandLogotype
+	PublisherLogotype
+)
+
+var picture_type_names = []string{
+	"Other",
+	"32x32 pixels file icon",
+	"Other file icon",
+	"Cover (front)",
+	"Cover (back)",
+	"Leaflet page",
+	"Media",
+	"Lead artist/lead performer/soloist",
+	"Artist/performer",
+	"Conductor",
+	"Band/Orchestra",
+	"Composer",
+	"Lyricist/text writer",
+	"Recording Location",
+	"During recording",
+	"During performance",
+	"Movie/video screen capture",
+	"A bright coloured fish",
+	"Illustration",
+	"Band/artist logotype",
+	"Publisher/Studio logotype",
+}
+
+// String returns the name the standard gives the picture type
+func (pictype PictureType) String() string {
+	if int(pictype) < len(picture_type_names) {
+		return picture_type_names[pictype]
+	}
+	return "Unknown picture type " + strconv.Itoa(int(pictype))
+}
+
+// A Picture is an attached picture from an APIC frame
+type Picture struct {
+	MIMEType    string
+	Type        PictureType
+	Description string
+	Data        []byte
+}
+
+// GetPictures returns all the attached pictures in the tag in the order they were read, from PIC frames in
+// v2.2 tags and APIC frames otherwise. Malformed frames are left out. The image data is copied as
+// GetTagData copies frame data.
+func (id3tag ID3Tag) GetPictures() ([]Picture, error) {
+	pics, err := id3tag.pictures()
+	for j := range pics {
+		pics[j].Data = id3tag.own_data(pics[j].Data)
+	}
+	return pics, err
+}
+
+// pictures returns the pictures GetPictures returns without copying their data
+func (id3tag ID3Tag) pictures() ([]Picture, error) {
+	frameid := "APIC"
+	if id3tag.Version == Version22 {
+		frameid = "PIC"
+	}
+	ret := make([]Picture, 0)
+	for _, framedata := range id3tag.tag_data(frameid) {
+		if pic, err := id3tag.parse_picture(framedata); err == nil {
+			ret = append(ret, pic)
+		}
+	}
+	if len(ret) == 0 {
+		return ret, errors.New("No such frame " + frameid + " found in the taglist")
+	}
+	return ret, nil
+}
+
+// parse_picture walks an APIC frame body field by field: text encoding, null terminated ISO-8859-1 MIME
+// type, picture type, description terminated as its encoding requires, and the image data. The PIC frames
+// of v2.2 tags have a three character image format in place of the MIME type.
+func (id3tag ID3Tag) parse_picture(framedata []byte) (Picture, error) {
+	var pic Picture
+	if len(framedata) == 0 {
+		return pic, errors.New("APIC frame is empty")
+	}
+	pos := 0
+	encoding := framedata[pos]
+	pos++
+
+	if id3tag.Version == Version22 {
+		if len(framedata) < pos+3 {
+			return pic, errors.New("PIC frame has no image format")
+		}
+		pic.MIMEType = image_format_mime_type(decodeISO88591(framedata[pos : pos+3]))
+		pos += 3
+	} else {
+		mime_end := bytes.IndexByte(framedata[pos:len(framedata)], 0)
+		if mime_end == -1 {
+			return pic, errors.New("APIC MIME type is not terminated")
+		}
+		pic.MIMEType = decodeISO88591(framedata[pos : pos+mime_end])
+		pos += mime_end + 1
+	}
+
+	if pos >= len(framedata) {
+		return pic, errors.New("APIC frame has no picture type")
+	}
+	pic.Type = PictureType(framedata[pos])
+	pos++
+
+	desc, data := split_text(encoding, framedata[pos:len(framedata)])
+	if data == nil {
+		//split_text returns no rest only when the description is not terminated
+		return pic, errors.New("APIC description is not terminated")
+	}
+	var err error
+	if pic.Description, err = id3tag.decodetext(encoding, desc); err != nil {
+		return pic, err
+	}
+	pic.Data = data
+	return pic, nil
+}
+
+// image_format_mime_type maps the image format of a v2.2 PIC frame to a MIME type. "-->", which marks data
+// holding a URL to the image, is kept as is, just as it is in APIC frames.
+func image_format_mime_type(format string) string {
+	switch strings.ToUpper(format) {
+	case "JPG":
+		return "image/jpeg"
+	case "PNG":
+		return "image/png"
+	case "GIF":
+		return "image/gif"
+	case "BMP":
+		return "image/bmp"
+	case "-->":
+		return format
+	}
+	return "image/" + strings.ToLower(strings.TrimRight(format, " \x00"))
+}
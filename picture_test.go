@@ -0,0 +1,141 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestPictureType(t *testing.T) {
+	cases := map[PictureType]string{
+		OtherPicture:       "Other",
+		FrontCover:         "Cover (front)",
+		BackCover:          "Cover (back)",
+		BrightColouredFish: "A bright coloured fish",
+		PublisherLogotype:  "Publisher/Studio logotype",
+		PictureType(21):    "Unknown picture type 21",
+	}
+	for pictype, want := range cases {
+		if got := pictype.String(); got != want {
+			t.Errorf("Picture type %d: expected %q, got %q", byte(pictype), want, got)
+		}
+	}
+	if FrontCover != 3 || PublisherLogotype != 0x14 {
+		t.Errorf("Picture type values do not match the standard")
+	}
+}
+
+func make_apic(pictype PictureType, image string) []byte {
+	return make_frame(3, "APIC", 0, append([]byte("\x00image/png\x00"+string(rune(pictype))+"\x00"), image...))
+}
+
+func TestCoverPreference(t *testing.T) {
+	cases := []struct {
+		pics       [][]byte
+		preference []PictureType
+		want       string
+	}{
+		{[][]byte{make_apic(BackCover, "back"), make_apic(FrontCover, "front")}, nil, "front"},
+		{[][]byte{make_apic(BackCover, "back"), make_apic(OtherPicture, "other")}, nil, "other"},
+		{[][]byte{make_apic(Artist, "artist"), make_apic(BackCover, "back")}, nil, "back"},
+		{[][]byte{make_apic(Artist, "artist"), make_apic(Band, "band")}, nil, "artist"},
+		{[][]byte{make_apic(FrontCover, "front"), make_apic(Band, "band")}, []PictureType{Band}, "band"},
+		{[][]byte{make_apic(FrontCover, "front1"), make_apic(FrontCover, "front2")}, nil, "front1"},
+	}
+	for j, c := range cases {
+		id3tag, err := ReadID3Bytes(make_tag(3, c.pics...))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		if got, err := id3tag.GetCoverPic(c.preference...); err != nil || !bytes.Equal(got, []byte(c.want)) {
+			t.Errorf("Case %d: expected %q, got %q, %v", j, c.want, got, err)
+		}
+	}
+}
+
+func TestGetPictures(t *testing.T) {
+	utf16desc := []byte("\x01image/jpeg\x00\x04\xff\xfe\x00\x01B\x00\x00\x00")
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "APIC", 0, append(utf16desc, "\x00\x00jpeg"...)),
+		make_frame(4, "APIC", 0, []byte("\x03image/png\x00\x03Fr\xc3\xb6nt\x00\x00png")),
+		make_frame(4, "APIC", 0, []byte("\x00image/png\x00\x03unterminated")),
+		make_frame(4, "APIC", 0, []byte("\x00image/png")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := []Picture{
+		{"image/jpeg", BackCover, "\u0100B", []byte("\x00\x00jpeg")},
+		{"image/png", FrontCover, "Fr\u00f6nt", []byte("\x00png")},
+	}
+	if got, err := id3tag.GetPictures(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q, %v", want, got, err)
+	}
+	if got, _ := id3tag.GetCoverPic(); !bytes.Equal(got, []byte("\x00png")) {
+		t.Errorf("Unexpected cover pic %q", got)
+	}
+
+	//changing the image data returned leaves the tag as it was, unless the data is aliased
+	pics, _ := id3tag.GetPictures()
+	pics[0].Data[2] = 'X'
+	cover, _ := id3tag.GetCoverPic()
+	cover[2] = 'X'
+	if got, _ := id3tag.GetPictures(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changing returned image data changed the tag: %q", got)
+	}
+	aliased, _ := ReadID3Bytes(make_tag(4, make_frame(4, "APIC", 0, []byte("\x00image/png\x00\x03\x00png"))), WithAliasedTagData(true))
+	cover, _ = aliased.GetCoverPic()
+	cover[0] = 'P'
+	if got, _ := aliased.GetCoverPic(); !bytes.Equal(got, []byte("Png")) {
+		t.Errorf("Expected aliased image data, got %q", got)
+	}
+
+	fil, err := os.Open("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fil.Close()
+	if id3tag, err = ReadID3(fil); err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if pics, err := id3tag.GetPictures(); err != nil || len(pics) != 1 || pics[0].MIMEType != "image/png" || !bytes.HasPrefix(pics[0].Data, []byte("\x89PNG")) {
+		t.Errorf("Unexpected pictures in test file: %v", err)
+	}
+}
+
+func TestV22Pictures(t *testing.T) {
+	frame := func(frameid string, data string) []byte {
+		return append([]byte{frameid[0], frameid[1], frameid[2], 0, byte(len(data) >> 8), byte(len(data))}, data...)
+	}
+	frames := append(frame("TT2", "\x00Title"), frame("PIC", "\x00JPG\x04Back\x00jpeg")...)
+	frames = append(frames, frame("PIC", "\x00PNG\x03\x00png")...)
+	buf := append([]byte{'I', 'D', '3', 2, 0, 0}, synchsafe(uint32(len(frames)+4))...)
+	buf = append(append(buf, frames...), 0, 0, 0, 0)
+
+	id3tag, err := ReadID3Bytes(buf)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if got := id3tag.Order(); !reflect.DeepEqual(got, []string{"TT2", "PIC", "PIC"}) || id3tag.PaddingBytes != 4 {
+		t.Errorf("Unexpected frames %v and padding %d", got, id3tag.PaddingBytes)
+	}
+	want := []Picture{
+		{"image/jpeg", BackCover, "Back", []byte("jpeg")},
+		{"image/png", FrontCover, "", []byte("png")},
+	}
+	if got, err := id3tag.GetPictures(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q, %v", want, got, err)
+	}
+	if got, _ := id3tag.GetCoverPic(); string(got) != "png" {
+		t.Errorf("Unexpected cover pic %q", got)
+	}
+
+	//v2.2 compression was never defined
+	buf[5] = 0x40
+	if _, err := ReadID3Bytes(buf); err == nil {
+		t.Errorf("Expected an error for a compressed v2.2 tag")
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// GetPlayCount returns the play counter of the PCNT frame, or CNT frame of v2.2 tags
+func (id3tag ID3Tag) GetPlayCount() (uint64, error) {
+	framedatas := id3tag.tag_data(native_frameid(id3tag.Version, "PCNT"))
+	if len(framedatas) == 0 {
+		return 0, errors.New("No such frame PCNT found in the taglist")
+	}
+	return decode_counter(framedatas[0]), nil
+}
+
+// SetPlayCount returns a copy of the tag whose PCNT frame, or CNT frame of v2.2 tags, holds count
+func (id3tag ID3Tag) SetPlayCount(count uint64) ID3Tag {
+	data := encode_counter(count)
+	frameid := native_frameid(id3tag.Version, "PCNT")
+	return id3tag.ReplaceFrames(frameid, ID3Frame{FrameID: frameid, Length: uint32(len(data)), Data: data})
+}
+
+// IncrementPlayCount adds one play to the file at path: the PCNT counter, which is created if missing,
+// and the counters of all POPM frames are incremented and the tag is written back with WriteFile, so
+// usually only the tag region of the file is rewritten. A file without a tag gets a new v2.4 tag, and
+// v2.2 tags keep their version with their CNT and POP frames incremented. Tags that could not be read
+// in full, with UnparsedBytes or Warnings, are refused, as Compact refuses them. opts are passed on to
+// WriteFile, so WithBackup saves the tag before the counters change.
+func IncrementPlayCount(path string, opts ...WriteOption) error {
+	fil, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, 10)
+	n, err := io.ReadFull(fil, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		fil.Close()
+		return err
+	}
+	id3tag := ID3Tag{Version: Version24}
+	if _, ok := stream_tag_length(header[0:n]); ok {
+		if _, err := fil.Seek(0, io.SeekStart); err != nil {
+			fil.Close()
+			return err
+		}
+		if id3tag, err = ReadID3(fil); err != nil {
+			fil.Close()
+			return err
+		}
+	}
+	fil.Close()
+	if err := check_complete(id3tag); err != nil {
+		return err
+	}
+
+	count, _ := id3tag.GetPlayCount()
+	id3tag = id3tag.SetPlayCount(count + 1)
+	popms, _ := id3tag.GetPopularimeters()
+	for _, popm := range popms {
+		popm.Counter++
+		id3tag = id3tag.setpopm(popm)
+	}
+	return WriteFile(path, id3tag, opts...)
+}
@@ -0,0 +1,131 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIncrementPlayCount(t *testing.T) {
+	data, err := os.ReadFile("testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(data)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	audio := data[10+id3tag.Size : len(data)]
+	dir := t.TempDir()
+
+	//the test file has padding to grow into, so the tag is rewritten in place
+	path := filepath.Join(dir, "padded.mp3")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 3; j++ {
+		if err := IncrementPlayCount(path); err != nil {
+			t.Fatalf("Error in incrementing play count: %v", err)
+		}
+	}
+	check := func(path string, count uint64) {
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id3tag, err := ReadID3Bytes(written)
+		if err != nil {
+			t.Fatalf("Error in reading written tag: %v", err)
+		}
+		if got, err := id3tag.GetPlayCount(); err != nil || got != count {
+			t.Errorf("%v: expected %d plays, got %d, %v", path, count, got, err)
+		}
+		if !bytes.Equal(written[10+id3tag.Size:len(written)], audio) {
+			t.Errorf("%v: audio changed", path)
+		}
+	}
+	check(path, 3)
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(data)) {
+		t.Errorf("In place update changed the file size")
+	}
+
+	//without padding and without a tag the file has to be rewritten
+	for name, content := range map[string][]byte{"unpadded.mp3": nil, "untagged.mp3": audio} {
+		path := filepath.Join(dir, name)
+		if content == nil {
+			var buf bytes.Buffer
+			if err := WriteID3(&buf, id3tag, WithPadding(0)); err != nil {
+				t.Fatalf("Error in writing tag: %v", err)
+			}
+			content = append(buf.Bytes(), audio...)
+		}
+		if err := os.WriteFile(path, content, 0640); err != nil {
+			t.Fatal(err)
+		}
+		if err := IncrementPlayCount(path); err != nil {
+			t.Fatalf("Error in incrementing play count: %v", err)
+		}
+		check(path, 1)
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0640 {
+			t.Errorf("%v: rewrite did not keep the file mode", name)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 3 {
+		t.Errorf("Expected no temporary files to be left, found %d files", len(entries))
+	}
+}
+
+func TestIncrementPlayCountV22(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v22.mp3")
+	tag := make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Title")),
+		make_frame(2, "CNT", 0, []byte{0, 0, 0, 41}),
+		make_frame(2, "POP", 0, []byte("a@b\x00\x80\x00\x00\x00\x07")),
+	)
+	if err := os.WriteFile(path, append(tag, "audio"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := IncrementPlayCount(path); err != nil {
+		t.Fatalf("Error in incrementing play count: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(written)
+	if err != nil {
+		t.Fatalf("Error in reading written tag: %v", err)
+	}
+	if id3tag.Version != Version22 || len(id3tag.GetTagData("CNT")) != 1 || len(id3tag.GetTagData("PCNT")) != 0 {
+		t.Errorf("Expected the tag to stay v2.2 with a CNT frame, got v%v", id3tag.Version)
+	}
+	popms, _ := id3tag.GetPopularimeters()
+	if count, err := id3tag.GetPlayCount(); err != nil || count != 42 || len(popms) != 1 || popms[0].Counter != 8 {
+		t.Errorf("Unexpected counters %v %+v, %v", count, popms, err)
+	}
+	if title, _ := id3tag.GetTextFrameData("TT2"); title != "Title" || !bytes.HasSuffix(written, []byte("audio")) {
+		t.Errorf("Tag or audio was lost: %q", title)
+	}
+}
+
+func TestIncrementPlayCountIncomplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incomplete.mp3")
+	tag := make_tag(3,
+		make_frame(3, "PCNT", 0, []byte{0, 0, 0, 5}),
+		make_frame(3, "t!t!", 0, []byte("junk")),
+		make_frame(3, "TALB", 0, []byte("\x00Album")),
+	)
+	content := append(tag, "audio"...)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := IncrementPlayCount(path); err == nil {
+		t.Errorf("Expected an error for a tag that was not read in full")
+	}
+	if written, _ := os.ReadFile(path); !bytes.Equal(written, content) {
+		t.Errorf("File was changed")
+	}
+}
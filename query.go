@@ -0,0 +1,300 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Query selects scan results by their metadata. Queries compare fields with values and combine the
+// comparisons with AND, OR, NOT and parentheses, as in
+//
+//	artist = "Queen" AND year >= 1975 AND NOT genre ~ "live"
+//
+// The fields are title, artist, albumartist, album, composer, genre, year, duration (in seconds), path
+// and ext, and any key of ID3Tag.Fields such as TBPM or "TXXX:Mood" written in quotes. The operators are
+// =, !=, <, <=, >, >= and ~, which tests whether the field contains the value. Values that are numbers on
+// both sides are compared as numbers, and all others as strings without regard to case. A comparison on a
+// field with several values, like genre, holds if it holds for any of them. Files that failed to read
+// match no comparison.
+type Query struct {
+	root query_node
+}
+
+type query_node interface {
+	match(result *query_result) bool
+}
+
+// query_result is a result being matched, with the fields of its tag decoded once for all comparisons
+type query_result struct {
+	Result
+	fields map[string]string
+}
+
+func (result *query_result) tag_fields() map[string]string {
+	if result.fields == nil {
+		result.fields = result.Tag.Fields("\x00")
+	}
+	return result.fields
+}
+
+type query_and struct{ left, right query_node }
+type query_or struct{ left, right query_node }
+type query_not struct{ node query_node }
+type query_cmp struct{ field, op, value string }
+
+func (q query_and) match(result *query_result) bool {
+	return q.left.match(result) && q.right.match(result)
+}
+func (q query_or) match(result *query_result) bool {
+	return q.left.match(result) || q.right.match(result)
+}
+func (q query_not) match(result *query_result) bool { return !q.node.match(result) }
+
+// ParseQuery compiles a query
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := tokenize_query(query)
+	if err != nil {
+		return nil, err
+	}
+	p := query_parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New(fmt.Sprintf("Unexpected %q in query", p.tokens[p.pos].text))
+	}
+	return &Query{root}, nil
+}
+
+// Match reports whether result satisfies the query
+func (q *Query) Match(result Result) bool {
+	return q.root.match(&query_result{Result: result})
+}
+
+// Filter returns the results that satisfy the query, in order
+func (q *Query) Filter(results []Result) []Result {
+	ret := make([]Result, 0)
+	for _, result := range results {
+		if q.Match(result) {
+			ret = append(ret, result)
+		}
+	}
+	return ret
+}
+
+type query_token struct {
+	text   string
+	quoted bool
+}
+
+// query_comparisons are the comparison operators, longest first so that tokenizing finds "<=" before "<"
+var query_comparisons = []string{"<=", ">=", "!=", "=", "<", ">", "~"}
+
+func tokenize_query(query string) ([]query_token, error) {
+	tokens := make([]query_token, 0)
+	for pos := 0; pos < len(query); {
+		c := query[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			pos++
+		case c == '"':
+			end := strings.IndexByte(query[pos+1:len(query)], '"')
+			if end < 0 {
+				return nil, errors.New("Unterminated string in query")
+			}
+			tokens = append(tokens, query_token{query[pos+1 : pos+1+end], true})
+			pos += end + 2
+		default:
+			matched := false
+			for _, op := range append(query_comparisons, "(", ")") {
+				if strings.HasPrefix(query[pos:len(query)], op) {
+					tokens = append(tokens, query_token{op, false})
+					pos += len(op)
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			end := pos
+			for end < len(query) && !strings.ContainsRune(" \t\n\"<>=!~()", rune(query[end])) {
+				end++
+			}
+			if end == pos {
+				return nil, errors.New(fmt.Sprintf("Unexpected character %q in query", query[pos]))
+			}
+			tokens = append(tokens, query_token{query[pos:end], false})
+			pos = end
+		}
+	}
+	return tokens, nil
+}
+
+// query_parser is a recursive descent parser in which AND binds tighter than OR
+type query_parser struct {
+	tokens []query_token
+	pos    int
+}
+
+func (p *query_parser) peek_keyword(keyword string) bool {
+	return p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *query_parser) next() (query_token, error) {
+	if p.pos >= len(p.tokens) {
+		return query_token{}, errors.New("Unexpected end of query")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *query_parser) or() (query_node, error) {
+	left, err := p.and()
+	for err == nil && p.peek_keyword("OR") {
+		p.pos++
+		var right query_node
+		if right, err = p.and(); err == nil {
+			left = query_or{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *query_parser) and() (query_node, error) {
+	left, err := p.unary()
+	for err == nil && p.peek_keyword("AND") {
+		p.pos++
+		var right query_node
+		if right, err = p.unary(); err == nil {
+			left = query_and{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *query_parser) unary() (query_node, error) {
+	if p.peek_keyword("NOT") {
+		p.pos++
+		node, err := p.unary()
+		return query_not{node}, err
+	}
+	if p.peek_keyword("(") {
+		p.pos++
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek_keyword(")") {
+			return nil, errors.New("Missing ) in query")
+		}
+		p.pos++
+		return node, nil
+	}
+
+	field, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	valid_op := false
+	for _, candidate := range query_comparisons {
+		valid_op = valid_op || !op.quoted && op.text == candidate
+	}
+	if !valid_op {
+		return nil, errors.New(fmt.Sprintf("Expected a comparison after %q, got %q", field.text, op.text))
+	}
+	return query_cmp{field.text, op.text, value.text}, nil
+}
+
+func (q query_cmp) match(result *query_result) bool {
+	if result.Err != nil {
+		return false
+	}
+	for _, actual := range query_field(result, q.field) {
+		if compare_query_values(actual, q.op, q.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// query_field returns the values of the named field of result
+func query_field(result *query_result, field string) []string {
+	md := result.Metadata
+	switch strings.ToLower(field) {
+	case "title":
+		return []string{md.Title}
+	case "artist":
+		return []string{md.Artist}
+	case "albumartist":
+		return []string{md.AlbumArtist}
+	case "album":
+		return []string{md.Album}
+	case "composer":
+		return []string{md.Composer}
+	case "genre":
+		return md.Genres
+	case "year":
+		if md.RecordingDate.IsZero() {
+			return nil
+		}
+		return []string{strconv.Itoa(md.RecordingDate.Year())}
+	case "duration":
+		return []string{strconv.FormatFloat(md.Duration.Seconds(), 'f', -1, 64)}
+	case "path":
+		return []string{result.Path}
+	case "ext":
+		return []string{strings.TrimPrefix(path.Ext(result.Path), ".")}
+	}
+	if value, ok := result.tag_fields()[field]; ok {
+		return strings.Split(value, "\x00")
+	}
+	return nil
+}
+
+func compare_query_values(actual, op, value string) bool {
+	if op == "~" {
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(value))
+	}
+	cmp := 0
+	a, aerr := strconv.ParseFloat(strings.TrimFunc(actual, unicode.IsSpace), 64)
+	b, berr := strconv.ParseFloat(value, 64)
+	if aerr == nil && berr == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(strings.ToLower(actual), strings.ToLower(value))
+	}
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
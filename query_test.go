@@ -0,0 +1,68 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	results := []Result{
+		{Path: "a.mp3", Metadata: Metadata{Artist: "Queen", Title: "Bohemian Rhapsody", Genres: []string{"Rock"},
+			RecordingDate: time.Date(1975, 10, 31, 0, 0, 0, 0, time.UTC)}},
+		{Path: "b.mp3", Metadata: Metadata{Artist: "Queen", Title: "Live Killers", Genres: []string{"Rock", "Live"},
+			RecordingDate: time.Date(1979, 6, 22, 0, 0, 0, 0, time.UTC)}},
+		{Path: "c.mp3", Metadata: Metadata{Artist: "Abba", Title: "Waterloo", RecordingDate: time.Date(1974, 3, 4, 0, 0, 0, 0, time.UTC)}},
+		{Path: "d.mp3", Err: ErrNoTag},
+	}
+	cases := map[string][]string{
+		`artist = "Queen" AND year >= 1975`:                 {"a.mp3", "b.mp3"},
+		`artist = "queen" AND NOT genre ~ "live"`:           {"a.mp3"},
+		`year < 1975 OR title ~ "killers"`:                  {"b.mp3", "c.mp3"},
+		`(artist = Abba OR artist = Queen) AND year > 1976`: {"b.mp3"},
+		`path != "a.mp3" AND ext = mp3`:                     {"b.mp3", "c.mp3"},
+	}
+	for query, expected := range cases {
+		q, err := ParseQuery(query)
+		if err != nil {
+			t.Errorf("%v: %v", query, err)
+			continue
+		}
+		paths := make([]string, 0)
+		for _, result := range q.Filter(results) {
+			paths = append(paths, result.Path)
+		}
+		if len(paths) != len(expected) {
+			t.Errorf("%v: expected %v, got %v", query, expected, paths)
+			continue
+		}
+		for j := range paths {
+			if paths[j] != expected[j] {
+				t.Errorf("%v: expected %v, got %v", query, expected, paths)
+			}
+		}
+	}
+
+	tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TBPM", 0, []byte("\x03120")),
+		make_frame(4, "TXXX", 0, []byte("\x03Mood\x00calm")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := ParseQuery(`TBPM > 100 AND "TXXX:Mood" = calm AND NOT "TXXX:Mood" ~ up`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Match(Result{Path: "e.mp3", Tag: tag}) {
+		t.Errorf("Query on tag fields did not match")
+	}
+
+	for _, bad := range []string{`artist =`, `artist "Queen"`, `(year > 1970`, `title = "open`, `year > 1970 extra`,
+		`artist ! "Queen"`, `!`, `title = a!`} {
+		if _, err := ParseQuery(bad); err == nil {
+			t.Errorf("Expected an error parsing %v", bad)
+		}
+	}
+}
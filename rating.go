@@ -0,0 +1,166 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Popularimeter is the content of a POPM frame: the rating a user, identified by an email address or
+// the name of their player, gave the file on a scale of 1 to 255 with 0 meaning unrated, and how often
+// they played it
+type Popularimeter struct {
+	Email   string
+	Rating  byte
+	Counter uint64
+}
+
+// GetPopularimeters returns the POPM frames, or POP frames of v2.2 tags, in the order they were read. Counters too large
+// for a uint64 saturate. Malformed frames are left out.
+func (id3tag ID3Tag) GetPopularimeters() ([]Popularimeter, error) {
+	ret := make([]Popularimeter, 0)
+	for _, framedata := range id3tag.tag_data(native_frameid(id3tag.Version, "POPM")) {
+		email, rest := split_text(0, framedata)
+		if len(rest) == 0 {
+			continue
+		}
+		popm := Popularimeter{Email: decodeISO88591(email), Rating: rest[0]}
+		popm.Counter = decode_counter(rest[1:len(rest)])
+		ret = append(ret, popm)
+	}
+	if len(ret) == 0 {
+		return ret, errors.New("No such frame POPM found in the taglist")
+	}
+	return ret, nil
+}
+
+// decode_counter decodes the big endian play counters of PCNT and POPM frames, which grow beyond four
+// bytes as needed
+func decode_counter(data []byte) uint64 {
+	var counter uint64
+	for _, b := range data {
+		if counter > 0x00FFFFFFFFFFFFFF {
+			return ^uint64(0)
+		}
+		counter = counter<<8 | uint64(b)
+	}
+	return counter
+}
+
+// encode_counter encodes a play counter in at least four bytes
+func encode_counter(counter uint64) []byte {
+	buf := make([]byte, 0, 8)
+	for j := 7; j >= 0; j-- {
+		if b := byte(counter >> uint(8*j)); b != 0 || len(buf) > 0 || j < 4 {
+			buf = append(buf, b)
+		}
+	}
+	return buf
+}
+
+// setpopm returns a copy of the tag in which the POPM frame of popm.Email holds popm
+func (id3tag ID3Tag) setpopm(popm Popularimeter) ID3Tag {
+	data := append(encode_string(0, popm.Email), 0, popm.Rating)
+	if popm.Counter > 0 {
+		data = append(data, encode_counter(popm.Counter)...)
+	}
+	frameid := native_frameid(id3tag.Version, "POPM")
+	frame := ID3Frame{FrameID: frameid, Length: uint32(len(data)), Data: data}
+
+	frames := make([]ID3Frame, 0, len(id3tag.Frames)+1)
+	placed := false
+	for _, old := range id3tag.Frames {
+		if old.FrameID == frameid {
+			if email, _ := split_text(0, old.Data); strings.EqualFold(decodeISO88591(email), popm.Email) {
+				if !placed {
+					frames = append(frames, frame)
+					placed = true
+				}
+				continue
+			}
+		}
+		frames = append(frames, old)
+	}
+	if !placed {
+		frames = append(frames, frame)
+	}
+	id3tag.Frames = frames
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// A RatingProfile maps POPM ratings to stars the way a particular player does. Values holds the rating
+// the player writes for one to five stars. Email is the identity the player writes its POPM frame under,
+// compared without regard to case; an empty Email reads and writes the POPM frame without one, so that
+// each profile only ever sees the ratings it wrote itself.
+type RatingProfile struct {
+	Email  string
+	Values [5]byte
+}
+
+var (
+	// WindowsMediaPlayerRating is the mapping of Windows Media Player, which Winamp also follows
+	WindowsMediaPlayerRating = RatingProfile{"Windows Media Player 9 Series", [5]byte{1, 64, 128, 196, 255}}
+	// MediaMonkeyRating is the mapping MediaMonkey uses for whole stars
+	MediaMonkeyRating = RatingProfile{"no@email", [5]byte{1, 64, 128, 196, 255}}
+	// LinearRating spreads the stars evenly over the rating scale as foobar2000 does
+	LinearRating = RatingProfile{"", [5]byte{51, 102, 153, 204, 255}}
+)
+
+// Stars returns the number of stars, 0 for unrated, that the profile's player shows for rating. Each
+// rating maps to the star whose value is nearest to it, so ratings written by other players map sensibly.
+func (profile RatingProfile) Stars(rating byte) int {
+	if rating == 0 {
+		return 0
+	}
+	stars := 1
+	for j := 1; j < len(profile.Values); j++ {
+		if 2*int(rating) >= int(profile.Values[j-1])+int(profile.Values[j]) {
+			stars = j + 1
+		}
+	}
+	return stars
+}
+
+// Rating returns the POPM rating the profile's player writes for stars, which is limited to 0 to 5
+func (profile RatingProfile) Rating(stars int) byte {
+	if stars <= 0 {
+		return 0
+	}
+	if stars > len(profile.Values) {
+		stars = len(profile.Values)
+	}
+	return profile.Values[stars-1]
+}
+
+// GetRating returns the stars of the profile's POPM frame, the one SetRating writes
+func (id3tag ID3Tag) GetRating(profile RatingProfile) (int, error) {
+	popms, err := id3tag.GetPopularimeters()
+	if err != nil {
+		return 0, err
+	}
+	for _, popm := range popms {
+		if strings.EqualFold(popm.Email, profile.Email) {
+			return profile.Stars(popm.Rating), nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("No POPM frame for %q found in the taglist", profile.Email))
+}
+
+// SetRating returns a copy of the tag in which the profile's POPM frame holds stars. The play counter of
+// an existing frame is kept.
+func (id3tag ID3Tag) SetRating(profile RatingProfile, stars int) ID3Tag {
+	popm := Popularimeter{Email: profile.Email}
+	popms, _ := id3tag.GetPopularimeters()
+	for _, old := range popms {
+		if strings.EqualFold(old.Email, profile.Email) {
+			popm.Counter = old.Counter
+			break
+		}
+	}
+	popm.Rating = profile.Rating(stars)
+	return id3tag.setpopm(popm)
+}
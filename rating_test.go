@@ -0,0 +1,71 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRatingProfiles(t *testing.T) {
+	for _, profile := range []RatingProfile{WindowsMediaPlayerRating, MediaMonkeyRating, LinearRating} {
+		for stars := 0; stars <= 5; stars++ {
+			if got := profile.Stars(profile.Rating(stars)); got != stars {
+				t.Errorf("%v: %d stars read back as %d", profile.Email, stars, got)
+			}
+		}
+	}
+	//a linear 4 star rating shows as 4 stars in Windows Media Player too
+	if got := WindowsMediaPlayerRating.Stars(LinearRating.Rating(4)); got != 4 {
+		t.Errorf("Expected 4 stars, got %d", got)
+	}
+	if got := LinearRating.Stars(WindowsMediaPlayerRating.Rating(1)); got != 1 {
+		t.Errorf("Expected 1 star, got %d", got)
+	}
+}
+
+func TestPopularimeter(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "POPM", 0, []byte("Windows Media Player 9 Series\x00\xc4\x00\x00\x00\x07")),
+		make_frame(4, "POPM", 0, []byte("big@example.com\x00\x01\x01\x00\x00\x00\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := []Popularimeter{{"Windows Media Player 9 Series", 196, 7}, {"big@example.com", 1, 1 << 32}}
+	if got, err := id3tag.GetPopularimeters(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v, %v", want, got, err)
+	}
+	if stars, err := id3tag.GetRating(WindowsMediaPlayerRating); err != nil || stars != 4 {
+		t.Errorf("Expected 4 stars, got %d, %v", stars, err)
+	}
+	if _, err := id3tag.GetRating(MediaMonkeyRating); err == nil {
+		t.Errorf("Expected an error for a missing POPM frame")
+	}
+
+	id3tag = id3tag.SetRating(WindowsMediaPlayerRating, 2).SetRating(MediaMonkeyRating, 5)
+	popms := id3tag.GetTagData("POPM")
+	if len(popms) != 3 || !bytes.Equal(popms[0], []byte("Windows Media Player 9 Series\x00\x40\x00\x00\x00\x07")) || !bytes.Equal(popms[2], []byte("no@email\x00\xff")) {
+		t.Errorf("Unexpected POPM frames %q", popms)
+	}
+}
+
+func TestMixedRatingProfiles(t *testing.T) {
+	var id3tag ID3Tag
+	id3tag = id3tag.SetRating(WindowsMediaPlayerRating, 4).SetRating(LinearRating, 2).SetRating(MediaMonkeyRating, 1)
+	for profile, want := range map[*RatingProfile]int{&WindowsMediaPlayerRating: 4, &LinearRating: 2, &MediaMonkeyRating: 1} {
+		if stars, err := id3tag.GetRating(*profile); err != nil || stars != want {
+			t.Errorf("%q: expected %d stars, got %d, %v", profile.Email, want, stars, err)
+		}
+	}
+
+	//frames of other players are not read as the profile's own
+	other := ID3Tag{}.SetRating(WindowsMediaPlayerRating, 5)
+	if _, err := other.GetRating(LinearRating); err == nil {
+		t.Errorf("Expected no rating for a profile without a POPM frame")
+	}
+	if popms := id3tag.SetRating(LinearRating, 3).GetTagData("POPM"); len(popms) != 3 {
+		t.Errorf("Expected rating again to replace the frame, got %q", popms)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"regexp"
+	"sync"
+)
+
+// A lazy_regexp is compiled the first time it is used rather than when the package is initialised, so that
+// programs never calling the functions that use it, such as firmware built with TinyGo, do not link regexp.
+type lazy_regexp struct {
+	expr string
+	once sync.Once
+	re   *regexp.Regexp
+}
+
+func (lr *lazy_regexp) get() *regexp.Regexp {
+	lr.once.Do(func() {
+		lr.re = regexp.MustCompile(lr.expr)
+	})
+	return lr.re
+}
@@ -0,0 +1,212 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A RenameOption changes how RenameByTag names files.
+type RenameOption func(*rename_config)
+
+type rename_config struct {
+	directories bool
+}
+
+// WithDirectories lets the template of RenameByTag hold "/", creating the directories it names below the
+// directory of the file, as in "{albumartist}/{album}/{track} - {title}".
+func WithDirectories() RenameOption {
+	return func(cfg *rename_config) {
+		cfg.directories = true
+	}
+}
+
+// RenameByTag renames the file at path to a name formatted from its tag by template, keeping the file in
+// its directory and keeping its extension, and returns the new path. The template placeholders are
+// {title}, {artist}, {albumartist}, which falls back to the artist, {album}, {genre}, {year}, {track} and
+// {disc}, the last two zero padded to two digits. Placeholders of fields the tag lacks become "Unknown".
+// Characters that cannot appear in file names on the operating system are replaced by "_". If another
+// file has the name already, " (2)", " (3)" and so on are added before the extension.
+func RenameByTag(path, template string, opts ...RenameOption) (string, error) {
+	var cfg rename_config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	fil, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	id3tag, err := ReadID3(fil)
+	fil.Close()
+	if err != nil {
+		return "", err
+	}
+
+	segments := strings.Split(template, "/")
+	if len(segments) > 1 && !cfg.directories {
+		return "", errors.New("Template names directories, which needs WithDirectories")
+	}
+	for j, segment := range segments {
+		if segments[j] = sanitize_filename(id3tag.expand_template(segment)); segments[j] == "" {
+			return "", errors.New(fmt.Sprintf("Template %q gives an empty name", template))
+		}
+	}
+
+	dir, ext := filepath.Dir(path), filepath.Ext(path)
+	target := filepath.Join(append([]string{dir}, segments...)...)
+	if target+ext == path {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	candidate := target + ext
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		candidate = target + " (" + strconv.Itoa(n) + ")" + ext
+	}
+	if err := os.Rename(path, candidate); err != nil {
+		return "", err
+	}
+	return candidate, nil
+}
+
+// expand_template replaces the placeholders of template with the fields of the tag
+func (id3tag ID3Tag) expand_template(template string) string {
+	md := id3tag.GetMetadata()
+	text := func(value string) string {
+		if value = strings.TrimSpace(value); value == "" {
+			return "Unknown"
+		}
+		return value
+	}
+	number := func(n int) string {
+		if n == 0 {
+			return "Unknown"
+		}
+		return fmt.Sprintf("%02d", n)
+	}
+	album_artist := md.AlbumArtist
+	if album_artist == "" {
+		album_artist = md.Artist
+	}
+	genre, year := "", ""
+	if len(md.Genres) > 0 {
+		genre = md.Genres[0]
+	}
+	if !md.RecordingDate.IsZero() {
+		year = strconv.Itoa(md.RecordingDate.Year())
+	}
+	//values are made safe before substitution so that a "/" in a title cannot name a directory
+	return strings.NewReplacer(
+		"{title}", filename_part(text(md.Title)),
+		"{artist}", filename_part(text(md.Artist)),
+		"{albumartist}", filename_part(text(album_artist)),
+		"{album}", filename_part(text(md.Album)),
+		"{genre}", filename_part(text(genre)),
+		"{year}", text(year),
+		"{track}", number(md.Track),
+		"{disc}", number(md.Disc),
+	).Replace(template)
+}
+
+func filename_part(value string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+}
+
+// sanitize_filename makes name safe as a file name on the operating system
+func sanitize_filename(name string) string {
+	illegal := "/\x00"
+	if runtime.GOOS == "windows" {
+		illegal = "<>:\"/\\|?*\x00"
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(illegal, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimRight(name, ". ")
+		base := strings.ToUpper(strings.SplitN(name, ".", 2)[0])
+		switch base {
+		case "CON", "PRN", "AUX", "NUL", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+			"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9":
+			name = "_" + name
+		}
+	}
+	if name == "." || name == ".." {
+		return "_"
+	}
+	return strings.TrimSpace(name)
+}
+
+var template_placeholder = &lazy_regexp{expr: `\{[a-z]+\}`}
+
+// InferFromFilename reads the fields of a file lacking a tag from its path, the reverse of RenameByTag.
+// pattern takes the placeholders RenameByTag takes and is matched against the file name less its
+// extension, and against as many of the directories above it as pattern holds "/", so that
+// "{artist}/{album}/{track} - {title}" reads "Artist/Album/03 - Title.mp3". {track}, {disc} and {year}
+// only match digits. The result can be written as a tag with Metadata.Tag.
+func InferFromFilename(path, pattern string) (Metadata, error) {
+	var md Metadata
+	fields := template_placeholder.get().FindAllString(pattern, -1)
+	literals := template_placeholder.get().Split(pattern, -1)
+	expr := "^" + regexp.QuoteMeta(literals[0])
+	for j, field := range fields {
+		switch field {
+		case "{track}", "{disc}", "{year}":
+			expr += `(\d+)`
+		case "{title}", "{artist}", "{albumartist}", "{album}", "{genre}":
+			expr += `([^/]+?)`
+		default:
+			return md, errors.New(fmt.Sprintf("Unknown placeholder %v in pattern %q", field, pattern))
+		}
+		expr += regexp.QuoteMeta(literals[j+1])
+	}
+	re, err := regexp.Compile(expr + "$")
+	if err != nil {
+		return md, err
+	}
+
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	depth := strings.Count(pattern, "/") + 1
+	if len(parts) < depth {
+		return md, errors.New(fmt.Sprintf("Path %q has fewer directories than pattern %q", path, pattern))
+	}
+	name := strings.Join(parts[len(parts)-depth:], "/")
+	match := re.FindStringSubmatch(strings.TrimSuffix(name, filepath.Ext(name)))
+	if match == nil {
+		return md, errors.New(fmt.Sprintf("Path %q does not match pattern %q", path, pattern))
+	}
+
+	values := make(map[string]string)
+	for j, field := range fields {
+		value := strings.TrimSpace(match[j+1])
+		if prev, ok := values[field]; ok && prev != value {
+			return md, errors.New(fmt.Sprintf("Path %q gives %v two values", path, field))
+		}
+		values[field] = value
+	}
+	md.Title, md.Artist, md.Album = values["{title}"], values["{artist}"], values["{album}"]
+	md.AlbumArtist = values["{albumartist}"]
+	if genre := values["{genre}"]; genre != "" {
+		md.Genres = []string{genre}
+	}
+	md.Track, _ = strconv.Atoi(values["{track}"])
+	md.Disc, _ = strconv.Atoi(values["{disc}"])
+	if year, err := strconv.Atoi(values["{year}"]); err == nil {
+		md.RecordingDate = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return md, nil
+}
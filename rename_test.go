@@ -0,0 +1,79 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameByTag(t *testing.T) {
+	id3tag, err := NewTagBuilder().Title("Hello/World").Artist("Artist").Album("Album").Track(3, 10).Build(Version24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, id3tag); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	renamed, err := RenameByTag(write("a.mp3"), "{track} - {artist} - {title}")
+	if err != nil || renamed != filepath.Join(dir, "03 - Artist - Hello_World.mp3") {
+		t.Errorf("Unexpected rename to %v, %v", renamed, err)
+	}
+	if again, err := RenameByTag(renamed, "{track} - {artist} - {title}"); err != nil || again != renamed {
+		t.Errorf("Expected renaming to the same name to do nothing, got %v, %v", again, err)
+	}
+	collided, err := RenameByTag(write("b.mp3"), "{track} - {artist} - {title}")
+	if err != nil || collided != filepath.Join(dir, "03 - Artist - Hello_World (2).mp3") {
+		t.Errorf("Unexpected rename on collision to %v, %v", collided, err)
+	}
+
+	if _, err := RenameByTag(write("c.mp3"), "{albumartist}/{album}/{title}"); err == nil {
+		t.Errorf("Expected directories to need WithDirectories")
+	}
+	nested, err := RenameByTag(filepath.Join(dir, "c.mp3"), "{albumartist}/{album} ({year})/{title}", WithDirectories())
+	if err != nil || nested != filepath.Join(dir, "Artist", "Album (Unknown)", "Hello_World.mp3") {
+		t.Errorf("Unexpected rename into directories to %v, %v", nested, err)
+	}
+	if _, err := os.Stat(nested); err != nil {
+		t.Errorf("Renamed file missing: %v", err)
+	}
+}
+
+func TestInferFromFilename(t *testing.T) {
+	md, err := InferFromFilename("/music/Some Artist/Album - Part 2 (1999)/03 - Title - Remix.mp3", "{artist}/{album} ({year})/{track} - {title}")
+	if err != nil {
+		t.Fatalf("Error in inferring fields: %v", err)
+	}
+	if md.Artist != "Some Artist" || md.Album != "Album - Part 2" || md.Track != 3 || md.Title != "Title - Remix" || md.RecordingDate.Year() != 1999 {
+		t.Errorf("Unexpected fields %+v", md)
+	}
+
+	id3tag := md.Tag(Version23)
+	if title, _ := id3tag.GetTitle(); title != "Title - Remix" {
+		t.Errorf("Unexpected title %q in the inferred tag", title)
+	}
+	if track, _, err := id3tag.GetTrack(); track != 3 || err != nil {
+		t.Errorf("Unexpected track %v, %v in the inferred tag", track, err)
+	}
+	if year, _ := id3tag.GetTextFrameData("TYER"); year != "1999" {
+		t.Errorf("Unexpected year %q in the inferred tag", year)
+	}
+
+	for _, pattern := range []string{"{track} - {title}", "{artist}/{album}/{artist}/{title}", "{bpm} - {title}"} {
+		if _, err := InferFromFilename("a/b/Artist - Title.mp3", pattern); err == nil {
+			t.Errorf("Expected %q not to match", pattern)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// repair_slack is how far past the size its header declares Repair looks for frames of a tag whose size
+// was understated
+const repair_slack = 1 << 20
+
+// Repair reads the ID3v2.3 or v2.4 tag at the start of r, fixing the damage buggy taggers commonly do,
+// and returns the repaired tag along with a description of each change made. Frame sizes written
+// synchsafe in v2.3 tags or as plain integers in v2.4 tags, as old iTunes and LAME versions did, are read
+// the way that leads to the next frame. A frame whose size fits neither way is dropped along with the rest
+// of the tag. Frames running past the declared tag size are kept and the size recomputed, and the
+// extended header, whose CRC cannot be right after repairs, is dropped. Unsynchronisation of v2.3 tags
+// is undone before frame sizes, which count the bytes before unsynchronisation, are checked. r is read
+// from its start; v2.2 tags are read as they are.
+func Repair(r io.ReadSeeker) (ID3Tag, []string, error) {
+	changes := make([]string, 0)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return ID3Tag{}, nil, err
+	}
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ID3Tag{}, nil, wrap_error("Could not read the tag header", err)
+	}
+	if string(header[0:3]) != "ID3" {
+		return ID3Tag{}, nil, ErrNoTag
+	}
+	ver := header[3]
+	if ver == 2 {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return ID3Tag{}, nil, err
+		}
+		id3tag, err := ReadID3(r)
+		return id3tag, changes, err
+	}
+	if ver != 3 && ver != 4 {
+		return ID3Tag{}, nil, errors.New(fmt.Sprintf("Unsupported ID3v2 version %v", ver))
+	}
+	declared, err := convert_synchsafe_int(header[6:10])
+	if err != nil {
+		declared, _ = convert_regular_int(header[6:10])
+		changes = append(changes, fmt.Sprintf("Tag size % X was not synchsafe", header[6:10]))
+	}
+	buf, err := io.ReadAll(io.LimitReader(r, int64(declared)+repair_slack))
+	if err != nil {
+		return ID3Tag{}, nil, err
+	}
+	if uint64(len(buf)) < uint64(declared) {
+		changes = append(changes, fmt.Sprintf("Tag size %v exceeds the %v bytes left in the file", declared, len(buf)))
+		declared = uint32(len(buf))
+	}
+	flags := header[5] &^ 0x50
+	if header[5]&0x80 != 0 && ver == 3 {
+		//what follows the declared size may be audio, so it is resynchronised apart from the tag
+		tag := resynchronise(buf[0:declared])
+		buf = append(tag, resynchronise(buf[declared:len(buf)])...)
+		declared = uint32(len(tag))
+		flags &^= 0x80
+		changes = append(changes, "Unsynchronisation undone")
+	}
+
+	pos := uint64(0)
+	if header[5]&0x40 != 0 {
+		changes = append(changes, "Extended header dropped")
+		if len(buf) >= 4 {
+			exthdr_size, _ := convert_regular_int(buf[0:4])
+			if ver == 4 {
+				exthdr_size, _ = convert_synchsafe_int(buf[0:4])
+			} else {
+				exthdr_size += 4
+			}
+			pos = uint64(exthdr_size)
+		}
+	}
+	if header[5]&0x10 != 0 && ver == 4 {
+		changes = append(changes, "Footer dropped")
+	}
+
+	body := make([]byte, 0, len(buf))
+	dropped := false
+	for pos+10 <= uint64(len(buf)) && valid_frameid_bytes(buf[pos:pos+4], false) {
+		frameid := string(buf[pos : pos+4])
+		plain, _ := convert_regular_int(buf[pos+4 : pos+8])
+		safe, safe_err := convert_synchsafe_int(buf[pos+4 : pos+8])
+		size, other := plain, safe
+		if ver == 4 {
+			size, other = safe, plain
+		}
+		end := pos + 10 + uint64(size)
+		if ver == 4 && safe_err != nil || !plausible_frame_end(buf, end, uint64(declared)) {
+			other_end := pos + 10 + uint64(other)
+			if ver == 3 && safe_err != nil || !plausible_frame_end(buf, other_end, uint64(declared)) {
+				changes = append(changes, fmt.Sprintf("Frame %v of impossible size %v dropped along with the rest of the tag", frameid, size))
+				dropped = true
+				break
+			}
+			if ver == 3 {
+				changes = append(changes, fmt.Sprintf("Frame %v size was written synchsafe", frameid))
+			} else {
+				changes = append(changes, fmt.Sprintf("Frame %v size was written as a plain integer", frameid))
+			}
+			size, end = other, other_end
+		}
+		body = append(body, buf[pos:pos+4]...)
+		if ver == 3 {
+			body = append(body, encode_regular_int(size)...)
+		} else {
+			body = append(body, encode_synchsafe_int(size)...)
+		}
+		body = append(body, buf[pos+8:end]...)
+		pos = end
+	}
+
+	padding := 0
+	for pos < uint64(declared) && buf[pos] == 0 {
+		pos++
+		padding++
+	}
+	if pos < uint64(declared) && !dropped {
+		changes = append(changes, fmt.Sprintf("%v bytes of garbage after the last frame dropped", uint64(declared)-pos))
+	}
+	size := uint32(len(body) + padding)
+	if size != declared {
+		changes = append(changes, fmt.Sprintf("Tag size corrected from %v to %v", declared, size))
+	}
+
+	fixed := append([]byte{'I', 'D', '3', ver, header[4], flags}, encode_synchsafe_int(size)...)
+	fixed = append(append(fixed, body...), make([]byte, padding)...)
+	id3tag, err := ReadID3Bytes(fixed)
+	return id3tag, changes, err
+}
+
+// plausible_frame_end reports whether a frame ending at end of buf, which holds a tag of size tag_length
+// and possibly what follows it, is followed by another frame, by padding or by the end of the tag, or
+// past the end of the tag by an MPEG frame sync
+func plausible_frame_end(buf []byte, end, tag_length uint64) bool {
+	switch {
+	case end == tag_length || end == uint64(len(buf)):
+		return true
+	case end > uint64(len(buf)):
+		return false
+	case end < tag_length && buf[end] == 0:
+		return true
+	case end > tag_length && end+1 < uint64(len(buf)) && buf[end] == 0xFF && buf[end+1]&0xE0 == 0xE0:
+		return true
+	}
+	return end+4 <= uint64(len(buf)) && valid_frameid_bytes(buf[end:end+4], false)
+}
+
+// resynchronise undoes unsynchronisation, which follows every 0xFF byte that a zero byte or a byte whose
+// top three bits are set would follow with an extra zero byte
+func resynchronise(data []byte) []byte {
+	ret := make([]byte, 0, len(data))
+	for j := 0; j < len(data); j++ {
+		ret = append(ret, data[j])
+		if data[j] == 0xFF && j+1 < len(data) && data[j+1] == 0 {
+			j++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,56 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	long := []byte("\x00" + strings.Repeat("a", 199))
+	itunes := make_frame(3, "TIT2", 0, long)
+	copy(itunes[4:8], synchsafe(200))
+	raw := make_tag(3, itunes, make_frame(3, "TPE1", 0, []byte("\x00Artist")))
+	copy(raw[6:10], synchsafe(150)) //understates the size
+	raw = append(raw, 0xFF, 0xFB, 0x90, 0x00)
+
+	id3tag, changes, err := Repair(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Error in repairing tag: %v", err)
+	}
+	if title, _ := id3tag.GetTitle(); title != string(long[1:]) {
+		t.Errorf("Unexpected title %q", title)
+	}
+	if artist, _ := id3tag.GetArtist(); artist != "Artist" {
+		t.Errorf("Unexpected artist %q", artist)
+	}
+	want := []string{"Frame TIT2 size was written synchsafe", "Tag size corrected from 150 to 227"}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+
+	broken := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")), make_frame(4, "TPE1", 0, []byte("\x03Artist")))
+	copy(broken[10+16+4:10+16+8], []byte{0x7F, 0x7F, 0x7F, 0x7F})
+	id3tag, changes, err = Repair(bytes.NewReader(broken))
+	if err != nil || len(id3tag.Frames) != 1 || len(changes) != 2 {
+		t.Errorf("Expected the broken frame to be dropped, got %v frames, changes %q, %v", len(id3tag.Frames), changes, err)
+	}
+
+	//the frame size counts the bytes before unsynchronisation added a zero after the 0xFF
+	unsynced := bytes.ReplaceAll(make_frame(3, "TIT2", 0, []byte("\x00\xff\xe0x")), []byte{0xFF, 0xE0}, []byte{0xFF, 0x00, 0xE0})
+	raw = make_tag(3, unsynced, make_frame(3, "TPE1", 0, []byte("\x00Artist")))
+	raw[5] |= 0x80
+	id3tag, changes, err = Repair(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Error in repairing unsynchronised tag: %v", err)
+	}
+	if title, _ := id3tag.GetTitle(); title != "\u00ff\u00e0x" || len(id3tag.Frames) != 2 || len(changes) == 0 || changes[0] != "Unsynchronisation undone" {
+		t.Errorf("Unexpected title %q, %v frames, changes %q", title, len(id3tag.Frames), changes)
+	}
+
+	if _, _, err := Repair(bytes.NewReader([]byte("RIFF0000WAVE"))); err != ErrNoTag {
+		t.Errorf("Expected ErrNoTag, got %v", err)
+	}
+}
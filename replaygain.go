@@ -0,0 +1,74 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"math"
+	"strconv"
+)
+
+// GainInfo is the result of a ReplayGain analysis: the gain to apply in dB and the peak sample amplitude,
+// where 1.0 is digital full scale
+type GainInfo struct {
+	Gain float64
+	Peak float64
+}
+
+// SetReplayGain returns a copy of the tag holding the track and album ReplayGain values, written both as
+// the replaygain_track_gain, replaygain_track_peak, replaygain_album_gain and replaygain_album_peak TXXX
+// frames most players read and, in v2.4 tags, as RVA2 frames identified "track" and "album". v2.2 and v2.3
+// tags only get the TXXX frames, since their RVA and RVAD frames hold a single relative adjustment rather
+// than a gain in dB. A GainInfo with a zero Peak is taken as not measured and its frames are removed.
+func (id3tag ID3Tag) SetReplayGain(track, album GainInfo) ID3Tag {
+	for _, scope := range []struct {
+		name string
+		info GainInfo
+	}{{"track", track}, {"album", album}} {
+		if scope.info.Peak == 0 {
+			id3tag = id3tag.SetUserText("replaygain_" + scope.name + "_gain").SetUserText("replaygain_" + scope.name + "_peak")
+			id3tag = id3tag.without_rva2(scope.name)
+			continue
+		}
+		id3tag = id3tag.SetUserText("replaygain_"+scope.name+"_gain", strconv.FormatFloat(scope.info.Gain, 'f', 2, 64)+" dB")
+		id3tag = id3tag.SetUserText("replaygain_"+scope.name+"_peak", strconv.FormatFloat(scope.info.Peak, 'f', 6, 64))
+		id3tag = id3tag.without_rva2(scope.name)
+		if id3tag.Version == Version24 || id3tag.Version == 0 {
+			data := encode_rva2(scope.name, scope.info)
+			id3tag.Frames = append(id3tag.Frames, ID3Frame{FrameID: "RVA2", Length: uint32(len(data)), Data: data})
+		}
+	}
+	return id3tag
+}
+
+// without_rva2 returns a copy of the tag without the RVA2 frames with the given identification, which is
+// only altered if it had any
+func (id3tag ID3Tag) without_rva2(identification string) ID3Tag {
+	frames := make([]ID3Frame, 0, len(id3tag.Frames))
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID == "RVA2" {
+			if id, _ := split_text(0, frame.Data); decodeISO88591(id) == identification {
+				continue
+			}
+		}
+		frames = append(frames, frame)
+	}
+	if len(frames) < len(id3tag.Frames) {
+		id3tag.Frames = frames
+		id3tag.altered = true
+		id3tag.cache = id3tag.fresh_cache()
+	}
+	return id3tag
+}
+
+// encode_rva2 returns an RVA2 frame body adjusting the master volume by info.Gain, stored in 1/512 dB,
+// with info.Peak stored as a 16 bit fraction of full scale
+func encode_rva2(identification string, info GainInfo) []byte {
+	adjustment := int16(clamp(math.Round(info.Gain*512), math.MinInt16, math.MaxInt16))
+	peak := uint16(clamp(math.Round(info.Peak*0x7FFF), 0, math.MaxUint16))
+	buf := append([]byte(identification), 0)
+	return append(buf, 1, byte(uint16(adjustment)>>8), byte(adjustment), 16, byte(peak>>8), byte(peak))
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
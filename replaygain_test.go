@@ -0,0 +1,54 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSetReplayGain(t *testing.T) {
+	for _, ver := range []byte{3, 4} {
+		id3tag, err := ReadID3Bytes(make_tag(ver,
+			make_frame(ver, "TXXX", 0, []byte("\x00REPLAYGAIN_TRACK_GAIN\x00+1.00 dB")),
+			make_frame(ver, "TXXX", 0, []byte("\x00replaygain_album_gain\x00+2.00 dB")),
+		))
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+		id3tag = id3tag.SetReplayGain(GainInfo{-6.5, 0.988547}, GainInfo{})
+
+		want := map[string][]string{
+			"TXXX:replaygain_track_gain": {"-6.50 dB"},
+			"TXXX:replaygain_track_peak": {"0.988547"},
+		}
+		if got := id3tag.AllText(); !reflect.DeepEqual(got, want) {
+			t.Errorf("v2.%d: expected %v, got %v", ver, want, got)
+		}
+		rva2 := id3tag.GetTagData("RVA2")
+		if ver == 3 && len(rva2) != 0 {
+			t.Errorf("v2.3 tags have no RVA2 frames")
+		}
+		//-6.5 dB is -3328/512, the peak 0.988547 of 32767 is 32392
+		if ver == 4 && (len(rva2) != 1 || !bytes.Equal(rva2[0], []byte("track\x00\x01\xf3\x00\x10\x7e\x88"))) {
+			t.Errorf("Unexpected RVA2 frames %q", rva2)
+		}
+	}
+
+	v22 := ID3Tag{Version: Version22}.SetReplayGain(GainInfo{-6.5, 0.988547}, GainInfo{})
+	if rva2 := v22.GetTagData("RVA2"); len(rva2) != 0 {
+		t.Errorf("v2.2 tags have no RVA2 frames")
+	}
+
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "RVA2", 0, []byte("album\x00\x01\x00\x00\x00"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if id3tag.without_rva2("track").altered {
+		t.Errorf("Tag without a track RVA2 frame marked altered")
+	}
+	if removed := id3tag.without_rva2("album"); !removed.altered || len(removed.Frames) != 0 {
+		t.Errorf("Expected the album RVA2 frame to be removed")
+	}
+}
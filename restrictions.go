@@ -0,0 +1,104 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"unicode/utf8"
+)
+
+// Tag restrictions are declared in the extended header of v2.4 tags, as the Restrictions byte of
+// ExtendedHeader, by OR-ing together at most one tag size, one text field size and one image size
+// restriction with the encoding restrictions. A tag declaring restrictions holds no more than 128 frames
+// and 1MB even when no tag size restriction is set.
+const (
+	RestrictTagSize64Frames128KB byte = 0x40
+	RestrictTagSize32Frames40KB  byte = 0x80
+	RestrictTagSize32Frames4KB   byte = 0xC0
+	RestrictTextEncoding         byte = 0x20 //text is only ISO-8859-1 or UTF-8
+	RestrictText1024Chars        byte = 0x08
+	RestrictText128Chars         byte = 0x10
+	RestrictText30Chars          byte = 0x18
+	RestrictImageEncoding        byte = 0x04 //pictures are only PNG or JPEG
+	RestrictImage256             byte = 0x01 //pictures are at most 256x256 pixels
+	RestrictImage64              byte = 0x02 //pictures are at most 64x64 pixels
+	RestrictImageExactly64       byte = 0x03 //pictures are exactly 64x64 pixels
+)
+
+// WithRestrictions makes WriteID3 declare restrictions in the extended header of the v2.4 tag it writes,
+// replacing any the tag declares already, and fail if the tag does not keep to them. Restrictions cannot
+// be declared in v2.3 tags.
+func WithRestrictions(restrictions byte) WriteOption {
+	return func(cfg *write_config) {
+		cfg.restrictions = restrictions
+		cfg.has_restrictions = true
+	}
+}
+
+// check_restrictions returns an error describing the first way in which frames, written as a tag of size
+// bytes in all, do not keep to restrictions
+func (id3tag ID3Tag) check_restrictions(restrictions byte, frames []ID3Frame, size int64) error {
+	max_frames, max_size := [4]int{128, 64, 32, 32}[restrictions>>6], [4]int64{1 << 20, 128 << 10, 40 << 10, 4 << 10}[restrictions>>6]
+	if len(frames) > max_frames || size > max_size {
+		return errors.New(fmt.Sprintf("Tag of %v frames and %v bytes exceeds the restriction to %v frames and %v bytes", len(frames), size, max_frames, max_size))
+	}
+
+	max_chars := [4]int{0, 1024, 128, 30}[restrictions>>3&0x03]
+	for _, frame := range frames {
+		info, _ := LookupFrame(frame.FrameID)
+		if info.Type == BinaryValue || info.Type == URLValue || len(frame.Data) == 0 {
+			continue
+		}
+		encoding := frame.Data[0]
+		if restrictions&RestrictTextEncoding != 0 && encoding != 0 && encoding != 3 {
+			return errors.New(fmt.Sprintf("Frame %v uses a text encoding other than ISO-8859-1 or UTF-8", frame.FrameID))
+		}
+		if max_chars > 0 && info.Type == TextValue {
+			values, _ := id3tag.decodetext_values(encoding, frame.Data[1:len(frame.Data)])
+			for _, value := range values {
+				if utf8.RuneCountInString(value) > max_chars {
+					return errors.New(fmt.Sprintf("Frame %v holds text longer than %v characters", frame.FrameID, max_chars))
+				}
+			}
+		}
+		if info.Type == PictureValue {
+			if err := id3tag.check_picture_restrictions(restrictions, frame); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (id3tag ID3Tag) check_picture_restrictions(restrictions byte, frame ID3Frame) error {
+	if restrictions&0x07 == 0 {
+		return nil
+	}
+	pic, err := id3tag.parse_picture(frame.Data)
+	if err != nil {
+		return err
+	}
+	if restrictions&RestrictImageEncoding != 0 && pic.MIMEType != "image/png" && pic.MIMEType != "image/jpeg" {
+		return errors.New(fmt.Sprintf("Picture of type %v is neither PNG nor JPEG", pic.MIMEType))
+	}
+	limit := restrictions & 0x03
+	if limit == 0 {
+		return nil
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(pic.Data))
+	if err != nil {
+		return errors.New(fmt.Sprintf("Could not determine the size of a %v picture: %v", pic.MIMEType, err))
+	}
+	switch {
+	case limit == RestrictImage256 && (config.Width > 256 || config.Height > 256),
+		limit == RestrictImage64 && (config.Width > 64 || config.Height > 64),
+		limit == RestrictImageExactly64 && (config.Width != 64 || config.Height != 64):
+		return errors.New(fmt.Sprintf("Picture of %vx%v pixels exceeds the image size restriction", config.Width, config.Height))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRestrictions(t *testing.T) {
+	id3tag := ID3Tag{Version: Version24}.SetText("TIT2", "Short title")
+
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, id3tag, WithRestrictions(RestrictText30Chars|RestrictTextEncoding)); err != nil {
+		t.Fatalf("Error in writing tag: %v", err)
+	}
+	restricted, err := ReadID3Bytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	exthdr := restricted.ExtendedHeader
+	if exthdr == nil || !exthdr.HasRestrictions || exthdr.Restrictions != RestrictText30Chars|RestrictTextEncoding {
+		t.Fatalf("Expected the restrictions to be declared, got %+v", exthdr)
+	}
+	if title, _ := restricted.GetTitle(); title != "Short title" {
+		t.Errorf("Unexpected title %q", title)
+	}
+
+	//the restrictions the tag declares hold for edits
+	if err := WriteID3(&bytes.Buffer{}, restricted.SetText("TIT2", strings.Repeat("x", 31))); err == nil {
+		t.Errorf("Expected a 31 character title to break the restrictions")
+	}
+	utf16_title := []byte("\x01\xff\xfeT\x00")
+	if err := WriteID3(&bytes.Buffer{}, restricted.ReplaceFrames("TIT2", ID3Frame{FrameID: "TIT2", Data: utf16_title})); err == nil {
+		t.Errorf("Expected UTF-16 text to break the restrictions")
+	}
+	if err := WriteID3(&bytes.Buffer{}, ID3Tag{Version: Version23}, WithRestrictions(0)); err == nil {
+		t.Errorf("Expected restrictions to be refused for v2.3 tags")
+	}
+
+	var img bytes.Buffer
+	png.Encode(&img, image.NewGray(image.Rect(0, 0, 100, 80)))
+	apic := append([]byte("\x03image/png\x00\x03\x00"), img.Bytes()...)
+	pictured := id3tag.WithFrame(ID3Frame{FrameID: "APIC", Data: apic})
+	if err := WriteID3(&bytes.Buffer{}, pictured, WithRestrictions(RestrictImage256|RestrictImageEncoding)); err != nil {
+		t.Errorf("Expected a 100x80 PNG to keep to the restrictions: %v", err)
+	}
+	if err := WriteID3(&bytes.Buffer{}, pictured, WithRestrictions(RestrictImage64)); err == nil {
+		t.Errorf("Expected a 100x80 PNG to break the 64x64 restriction")
+	}
+	if err := WriteID3(&bytes.Buffer{}, pictured, WithRestrictions(RestrictTagSize32Frames4KB), WithPadding(4096)); err == nil {
+		t.Errorf("Expected 4KB of padding to break the tag size restriction")
+	}
+}
@@ -0,0 +1,154 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A Policy tells Sanitize what to keep of a tag. Allow and Deny hold FrameIDs or prefixes ending in "*" as
+// Reorder takes them. A frame is kept if it matches no Deny entry and, when Allow is not empty, matches an
+// Allow entry. The frames of v2.2 tags are matched by the IDs of their v2.3 equivalents, so "COMM" also
+// matches COM frames.
+type Policy struct {
+	Allow          []string
+	Deny           []string
+	DropURLs       bool //drop all URL link frames, W*
+	MaxArtworkSize int  //drop pictures with more image data than this many bytes, if not 0
+}
+
+// UploadPolicy suits tags of files uploaded by untrusted users and served to others: it drops frames
+// that carry private data, binary payloads, identifiers of users or stores, links and encrypted content,
+// and pictures over 1MB.
+var UploadPolicy = Policy{
+	Deny: []string{
+		"PRIV", "GEOB", "UFID", "POPM", "PCNT", "OWNE", "COMR", "USER", "ENCR", "GRID", "SIGN", "AENC",
+		"LINK", "TOWN", "TXXX", "CRM",
+	},
+	DropURLs:       true,
+	MaxArtworkSize: 1 << 20,
+}
+
+// Sanitize returns a copy of id3tag holding only what policy allows. Text frames, TXXX, COMM and USLT are
+// decoded and written back in a clean encoding without control characters, other than line breaks in
+// comments and lyrics. Frames that are compressed, encrypted or fail to decode are dropped as their
+// content cannot be checked. The extended header and the experimental indicator are removed.
+func Sanitize(id3tag ID3Tag, policy Policy) ID3Tag {
+	ret := id3tag
+	ret.Frames = make([]ID3Frame, 0, len(id3tag.Frames))
+	ret.ExtendedHeader = nil
+	ret.Experimental = false
+	ret.altered = true
+	ret.cache = id3tag.fresh_cache()
+
+	for _, frame := range id3tag.Frames {
+		frameid := v23_frameid(id3tag.Version, frame.FrameID)
+		if !policy.allows(frameid) || frame.Compression || frame.Encryption || frame.Unsynchronisation {
+			continue
+		}
+		switch {
+		case frameid == "TXXX":
+			values, err := id3tag.decodetext_values(first_byte(frame.Data), rest_bytes(frame.Data))
+			if err != nil || len(values) < 2 {
+				continue
+			}
+			for j := range values {
+				values[j] = strip_controls(values[j], false)
+			}
+			if id3tag.Version == Version22 {
+				data := user_text_data(id3tag.Version, values[0], values[1:len(values)])
+				ret.Frames = append(ret.Frames, ID3Frame{FrameID: frame.FrameID, Length: uint32(len(data)), Data: data})
+			} else {
+				ret = ret.SetUserText(values[0], values[1:len(values)]...)
+			}
+		case strings.HasPrefix(frameid, "T"):
+			values, err := id3tag.decodetext_values(first_byte(frame.Data), rest_bytes(frame.Data))
+			if err != nil || len(values) == 0 {
+				continue
+			}
+			for j := range values {
+				values[j] = strip_controls(values[j], false)
+			}
+			data := encodetext(id3tag.Version, values...)
+			ret.Frames = append(ret.Frames, ID3Frame{FrameID: frame.FrameID, Length: uint32(len(data)), Data: data})
+		case frameid == "COMM" || frameid == "USLT":
+			if len(frame.Data) < 4 {
+				continue
+			}
+			desc, text := split_text(frame.Data[0], frame.Data[4:len(frame.Data)])
+			desctxt, err := id3tag.decodetext(frame.Data[0], desc)
+			if err != nil {
+				continue
+			}
+			texttxt, err := id3tag.decodetext(frame.Data[0], text)
+			if err != nil {
+				continue
+			}
+			desctxt, texttxt = strip_controls(desctxt, false), strip_controls(texttxt, true)
+			encoding := text_encoding(id3tag.Version, desctxt, texttxt)
+			data := append([]byte{encoding}, frame.Data[1:4]...)
+			data = append(append(data, encode_string(encoding, desctxt)...), string_terminator(encoding)...)
+			data = append(data, encode_string(encoding, texttxt)...)
+			ret.Frames = append(ret.Frames, ID3Frame{FrameID: frame.FrameID, Length: uint32(len(data)), Data: data})
+		case frameid == "APIC":
+			pic, err := id3tag.parse_picture(frame.Data)
+			if err != nil || policy.MaxArtworkSize > 0 && len(pic.Data) > policy.MaxArtworkSize {
+				continue
+			}
+			ret.Frames = append(ret.Frames, clean_frame(frame))
+		default:
+			ret.Frames = append(ret.Frames, clean_frame(frame))
+		}
+	}
+	return ret
+}
+
+func (policy Policy) allows(frameid string) bool {
+	if policy.DropURLs && strings.HasPrefix(frameid, "W") {
+		return false
+	}
+	for _, pattern := range policy.Deny {
+		if match_frameid(pattern, frameid) {
+			return false
+		}
+	}
+	if len(policy.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range policy.Allow {
+		if match_frameid(pattern, frameid) {
+			return true
+		}
+	}
+	return false
+}
+
+// clean_frame returns a copy of frame without flags or the prefix fields they call for
+func clean_frame(frame ID3Frame) ID3Frame {
+	data := append([]byte(nil), frame.Data...)
+	return ID3Frame{FrameID: frame.FrameID, Length: uint32(len(data)), Data: data}
+}
+
+func strip_controls(txt string, keep_newlines bool) string {
+	return strings.Map(func(r rune) rune {
+		if r == unicode.ReplacementChar || unicode.IsControl(r) && !(keep_newlines && (r == '\n' || r == '\r' || r == '\t')) {
+			return -1
+		}
+		return r
+	}, txt)
+}
+
+func first_byte(data []byte) byte {
+	if len(data) == 0 {
+		return 0
+	}
+	return data[0]
+}
+
+func rest_bytes(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	return data[1:len(data)]
+}
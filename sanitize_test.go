@@ -0,0 +1,82 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	big := append([]byte("\x00image/png\x00\x03\x00"), make([]byte, 2<<20)...)
+	id3tag, err := ReadID3Bytes(make_tag(3,
+		make_frame(3, "TIT2", 0, []byte("\x00Bad\x07 title\x1b")),
+		make_frame(3, "TPE1", 0, []byte("\x05Artist")),
+		make_frame(3, "PRIV", 0, []byte("store@example.com\x00purchase")),
+		make_frame(3, "WOAR", 0, []byte("http://example.com")),
+		make_frame(3, "COMM", 0, []byte("\x00eng\x00Line one\nLine\x00 two")),
+		make_frame(3, "APIC", 0, []byte("\x00image/png\x00\x03\x00small")),
+		make_frame(3, "APIC", 0, big),
+		make_frame(3, "TALB", 0x40, []byte("\x00Encrypted")),
+		make_frame(3, "TCON", 0x20, []byte("\x01\x00Rock")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	clean := Sanitize(id3tag, UploadPolicy)
+	if got := clean.Order(); !reflect.DeepEqual(got, []string{"TIT2", "COMM", "APIC", "TCON"}) {
+		t.Fatalf("Unexpected frames %v", got)
+	}
+	if title, _ := clean.GetTitle(); title != "Bad title" {
+		t.Errorf("Control characters not removed: %q", title)
+	}
+	if comm := clean.GetTagData("COMM")[0]; !bytes.Equal(comm, []byte("\x00eng\x00Line one\nLine")) {
+		t.Errorf("Unexpected comment %q", comm)
+	}
+	if tcon := clean.Frames[3]; tcon.Grouping || tcon.FormatFlags != 0 || string(tcon.Data) != "\x00Rock" {
+		t.Errorf("Frame flags not cleared: %+v", tcon)
+	}
+
+	only := Sanitize(id3tag, Policy{Allow: []string{"T*"}, Deny: []string{"TCON"}})
+	if got := only.Order(); !reflect.DeepEqual(got, []string{"TIT2"}) {
+		t.Errorf("Unexpected frames %v", got)
+	}
+	if !reflect.DeepEqual(id3tag.Order(), []string{"TIT2", "TPE1", "PRIV", "WOAR", "COMM", "APIC", "APIC", "TALB", "TCON"}) {
+		t.Errorf("Sanitize changed the original tag")
+	}
+}
+
+func TestSanitizeV22(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(2,
+		make_frame(2, "TT2", 0, []byte("\x00Bad\x07 title")),
+		make_frame(2, "POP", 0, []byte("someone@example.com\x00\x80")),
+		make_frame(2, "CNT", 0, []byte("\x00\x00\x00\x07")),
+		make_frame(2, "UFI", 0, []byte("owner\x00id")),
+		make_frame(2, "GEO", 0, []byte("\x00\x00file\x00\x00data")),
+		make_frame(2, "TXX", 0, []byte("\x00mood\x00calm")),
+		make_frame(2, "LNK", 0, []byte("TT2http://example.com\x00")),
+		make_frame(2, "CRM", 0, []byte("owner\x00\x00secret")),
+		make_frame(2, "WAR", 0, []byte("http://example.com")),
+		make_frame(2, "COM", 0, []byte("\x00eng\x00Line\x1b one")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	clean := Sanitize(id3tag, UploadPolicy)
+	if got := clean.Order(); !reflect.DeepEqual(got, []string{"TT2", "COM"}) {
+		t.Fatalf("Unexpected frames %v", got)
+	}
+	if comm := clean.GetTagData("COM")[0]; !bytes.Equal(comm, []byte("\x00eng\x00Line one")) {
+		t.Errorf("Unexpected comment %q", comm)
+	}
+	if title := clean.GetTagData("TT2")[0]; !bytes.Equal(title, []byte("\x00Bad title")) {
+		t.Errorf("Unexpected title %q", title)
+	}
+
+	kept := Sanitize(id3tag, Policy{Allow: []string{"TXXX"}})
+	if got := kept.GetTagData("TXX"); len(got) != 1 || !bytes.Equal(got[0], []byte("\x00mood\x00calm")) {
+		t.Errorf("Unexpected TXX frames %q", got)
+	}
+}
@@ -0,0 +1,48 @@
+package id3v2reader
+
+import (
+	"bytes"
+	"io"
+)
+
+// find_header returns the offset of the first plausible ID3v2 header within the first limit bytes of buf,
+// and 0 if there is none
+func find_header(buf []byte, limit int) int {
+	for j := 0; j <= limit && j+10 <= len(buf); j++ {
+		if buf[j] != 'I' || !bytes.HasPrefix(buf[j:len(buf)], []byte("ID3")) {
+			continue
+		}
+		if _, ok := stream_tag_length(buf[j : j+10]); ok && buf[j+3] >= 2 && buf[j+3] <= 4 {
+			return j
+		}
+	}
+	return 0
+}
+
+// scan_reader returns a reader positioned at the first plausible ID3v2 header within the first limit bytes
+// of rd, and the offset of that header. rd is returned as it is when limit is zero or no header is found.
+// Readers that can seek are seeked back to the header; others are wrapped to put the bytes read back.
+func scan_reader(rd io.Reader, limit int) (io.Reader, int) {
+	if limit <= 0 {
+		return rd, 0
+	}
+	prefix := make([]byte, limit+10)
+	n, _ := io.ReadFull(rd, prefix)
+	prefix = prefix[0:n]
+	if len(prefix) >= 3 && string(prefix[0:3]) == "ID3" {
+		//a header at the start is taken as it is, even if it turns out to be invalid
+		return rewind(rd, prefix, 0), 0
+	}
+	offset := find_header(prefix, limit)
+	return rewind(rd, prefix, offset), offset
+}
+
+// rewind returns a reader continuing from offset within prefix, which was read from rd
+func rewind(rd io.Reader, prefix []byte, offset int) io.Reader {
+	if seeker, ok := rd.(io.Seeker); ok {
+		if _, err := seeker.Seek(int64(offset-len(prefix)), io.SeekCurrent); err == nil {
+			return rd
+		}
+	}
+	return io.MultiReader(bytes.NewReader(prefix[offset:len(prefix)]), rd)
+}
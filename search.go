@@ -0,0 +1,69 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+)
+
+// A FrameMatch is a value of a frame that contains the text searched for by Search. Index is the
+// position of the frame in the Frames of the tag.
+type FrameMatch struct {
+	Index   int
+	FrameID string
+	Value   string
+}
+
+// Search decodes every frame of the tag that holds text and returns the values containing text, compared
+// without regard to case, in tag order, to find out where a string a player shows comes from. Values
+// are the text of text frames, one per value, the descriptions and text of TXXX, COMM, USLT and SYLT
+// frames, the descriptions and URLs of URL frames and the descriptions of pictures.
+func (id3tag ID3Tag) Search(text string) []FrameMatch {
+	query := strings.ToLower(text)
+	ret := make([]FrameMatch, 0)
+	for j, frame := range id3tag.Frames {
+		for _, value := range id3tag.frame_values(frame) {
+			if strings.Contains(strings.ToLower(value), query) {
+				ret = append(ret, FrameMatch{Index: j, FrameID: frame.FrameID, Value: value})
+			}
+		}
+	}
+	return ret
+}
+
+// frame_values returns all the text decoded from a frame, or nil for frames holding none
+func (id3tag ID3Tag) frame_values(frame ID3Frame) []string {
+	data := frame.Data
+	if frame.Compression || frame.Encryption || frame.Unsynchronisation || len(data) == 0 {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(frame.FrameID, "T"):
+		values, _ := id3tag.decodetext_values(data[0], data[1:len(data)])
+		return values
+	case frame.FrameID == "WXXX" || frame.FrameID == "WXX":
+		desc, url := split_text(data[0], data[1:len(data)])
+		description, _ := id3tag.decodetext(data[0], desc)
+		return []string{description, decodeISO88591(url)}
+	case strings.HasPrefix(frame.FrameID, "W"):
+		return []string{decodeISO88591(data)}
+	case (frame.FrameID == "COMM" || frame.FrameID == "USLT" || frame.FrameID == "COM" || frame.FrameID == "ULT") && len(data) > 4:
+		desc, text := split_text(data[0], data[4:len(data)])
+		description, _ := id3tag.decodetext(data[0], desc)
+		txt, _ := id3tag.decodetext(data[0], text)
+		return []string{description, txt}
+	case frame.FrameID == "APIC" || frame.FrameID == "PIC":
+		if pic, err := id3tag.parse_picture(data); err == nil {
+			return []string{pic.Description}
+		}
+	case frame.FrameID == "SYLT":
+		if lyrics, err := id3tag.parse_sylt(data); err == nil {
+			values := []string{lyrics.Description}
+			for _, line := range lyrics.Lines {
+				values = append(values, line.Text)
+			}
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "TPE1", 0, []byte("\x03Artist\x00Weird Guest")),
+		make_frame(4, "TXXX", 0, []byte("\x03WEIRD_KEY\x00value")),
+		make_frame(4, "COMM", 0, []byte("\x03eng\x00Ripped by weirdo")),
+		make_frame(4, "WOAR", 0, []byte("http://weird.example.com")),
+		make_frame(4, "PRIV", 0, []byte("weird\x00binary")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := []FrameMatch{
+		{1, "TPE1", "Weird Guest"},
+		{2, "TXXX", "WEIRD_KEY"},
+		{3, "COMM", "Ripped by weirdo"},
+		{4, "WOAR", "http://weird.example.com"},
+	}
+	if got := id3tag.Search("weird"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected matches %+v", got)
+	}
+	if got := id3tag.Search("nowhere"); len(got) != 0 {
+		t.Errorf("Unexpected matches %+v", got)
+	}
+}
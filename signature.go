@@ -0,0 +1,66 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// A Signature is the content of a SIGN frame: a signature over the frames of one group. Frames join a
+// group by carrying the grouping identity flag and the group symbol in their GroupSymbol.
+type Signature struct {
+	GroupSymbol byte
+	Signature   []byte
+}
+
+// A SignatureVerifier checks that signature is valid for signed, typically with the public key of whoever
+// signs the archive's tags, and returns an error if it is not.
+type SignatureVerifier func(signed, signature []byte) error
+
+// GetSignatures returns the content of every SIGN frame in the tag.
+func (id3tag ID3Tag) GetSignatures() []Signature {
+	ret := make([]Signature, 0)
+	for _, framedata := range id3tag.tag_data("SIGN") {
+		if len(framedata) < 1 {
+			continue
+		}
+		ret = append(ret, Signature{framedata[0], framedata[1:len(framedata)]})
+	}
+	return ret
+}
+
+// SignedData returns the bytes a signature for group symbol group covers. The standard leaves this open;
+// this package signs, in tag order, the four character FrameID followed by the Data of every frame in the
+// group, SIGN frames excepted. Signing tools should sign the same bytes.
+func (id3tag ID3Tag) SignedData(group byte) []byte {
+	var signed bytes.Buffer
+	for _, frame := range id3tag.Frames {
+		if frame.Grouping && frame.GroupSymbol == group && frame.FrameID != "SIGN" {
+			signed.WriteString(frame.FrameID)
+			signed.Write(frame.Data)
+		}
+	}
+	return signed.Bytes()
+}
+
+// VerifySignatures checks every SIGN frame of the tag with the verifier registered for its group symbol.
+// It fails if the tag holds no signatures, if a signature's group has no verifier, or if any verifier
+// rejects its signature.
+func (id3tag ID3Tag) VerifySignatures(verifiers map[byte]SignatureVerifier) error {
+	signatures := id3tag.GetSignatures()
+	if len(signatures) == 0 {
+		return errors.New("No signatures found in the taglist")
+	}
+	for _, sig := range signatures {
+		verify, ok := verifiers[sig.GroupSymbol]
+		if !ok {
+			return errors.New(fmt.Sprintf("No verifier for signature group %#x", sig.GroupSymbol))
+		}
+		if err := verify(id3tag.SignedData(sig.GroupSymbol), sig.Signature); err != nil {
+			return errors.New(fmt.Sprintf("Signature for group %#x is invalid: %v", sig.GroupSymbol, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+func TestVerifySignatures(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiers := map[byte]SignatureVerifier{
+		0x80: func(signed, signature []byte) error {
+			if !ed25519.Verify(public, signed, signature) {
+				return errors.New("bad signature")
+			}
+			return nil
+		},
+	}
+
+	//v2.4 frames with the grouping identity flag carry the group symbol before their content
+	grouped := func(frameid, content string) []byte {
+		return make_frame(4, frameid, 0x40, append([]byte{0x80}, content...))
+	}
+	signed := []byte("TIT2\x00TitleTPE1\x00Artist")
+	build := func(title string, signature []byte) []byte {
+		return make_tag(4,
+			grouped("TIT2", "\x00"+title),
+			grouped("TPE1", "\x00Artist"),
+			make_frame(4, "TALB", 0, []byte("\x00Unsigned album")),
+			make_frame(4, "SIGN", 0, append([]byte{0x80}, signature...)),
+		)
+	}
+
+	id3tag, err := ReadID3Bytes(build("Title", ed25519.Sign(private, signed)))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if got := string(id3tag.SignedData(0x80)); got != string(signed) {
+		t.Errorf("Unexpected signed data %q", got)
+	}
+	if err := id3tag.VerifySignatures(verifiers); err != nil {
+		t.Errorf("Expected the signature to verify: %v", err)
+	}
+	if err := id3tag.VerifySignatures(map[byte]SignatureVerifier{}); err == nil {
+		t.Errorf("Expected an error without a verifier for the group")
+	}
+
+	tampered, err := ReadID3Bytes(build("Tampered", ed25519.Sign(private, signed)))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if err := tampered.VerifySignatures(verifiers); err == nil {
+		t.Errorf("Expected a tampered tag to fail verification")
+	}
+
+	if err := (ID3Tag{}).VerifySignatures(verifiers); err == nil {
+		t.Errorf("Expected an error for a tag without signatures")
+	}
+}
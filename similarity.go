@@ -0,0 +1,147 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SimilarityWeights sets how much each field counts towards the similarity of two tracks
+type SimilarityWeights struct {
+	Title    float64
+	Artist   float64
+	Album    float64
+	Duration float64
+}
+
+// DefaultSimilarityWeights counts the title most and the album and duration least
+var DefaultSimilarityWeights = SimilarityWeights{Title: 0.4, Artist: 0.3, Album: 0.15, Duration: 0.15}
+
+// duration_tolerance is the difference in duration at which durations stop counting as similar at all
+const duration_tolerance = 10 * time.Second
+
+// Similarity scores how alike two tracks are from 0 for nothing in common to 1 for identical. Texts are
+// compared after normalizing case, punctuation and spacing, by the edit distance between them relative
+// to their length; durations by their difference relative to a 10 second tolerance. Fields missing from
+// either track are left out and the remaining weights count in proportion.
+func Similarity(a, b Metadata, weights SimilarityWeights) float64 {
+	var score, total float64
+	add := func(weight, similarity float64) {
+		score += weight * similarity
+		total += weight
+	}
+	for _, field := range []struct {
+		weight float64
+		a, b   string
+	}{{weights.Title, a.Title, b.Title}, {weights.Artist, a.Artist, b.Artist}, {weights.Album, a.Album, b.Album}} {
+		na, nb := normalize_for_comparison(field.a), normalize_for_comparison(field.b)
+		if na != "" && nb != "" {
+			add(field.weight, text_similarity(na, nb))
+		}
+	}
+	if a.Duration > 0 && b.Duration > 0 {
+		diff := a.Duration - b.Duration
+		if diff < 0 {
+			diff = -diff
+		}
+		add(weights.Duration, math.Max(0, 1-float64(diff)/float64(duration_tolerance)))
+	}
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
+
+func normalize_for_comparison(txt string) string {
+	fields := strings.FieldsFunc(strings.ToLower(txt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// text_similarity returns 1 less the Levenshtein distance between a and b relative to the longer of them
+func text_similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(ra) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min_int(min_int(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(len(ra))
+}
+
+func min_int(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// FindDuplicates groups the results whose Metadata scores at least threshold against another result in
+// the group, comparing every pair of results, and returns the groups of two or more in the order of their
+// first result. Results with an error are left out.
+func FindDuplicates(results []Result, threshold float64, weights SimilarityWeights) [][]Result {
+	parent := make([]int, len(results))
+	for j := range parent {
+		parent[j] = j
+	}
+	var find func(j int) int
+	find = func(j int) int {
+		if parent[j] != j {
+			parent[j] = find(parent[j])
+		}
+		return parent[j]
+	}
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		for j := i + 1; j < len(results); j++ {
+			if results[j].Err != nil {
+				continue
+			}
+			if Similarity(results[i].Metadata, results[j].Metadata, weights) >= threshold {
+				if ri, rj := find(i), find(j); ri != rj {
+					parent[rj] = ri
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]Result)
+	order := make([]int, 0)
+	for j, result := range results {
+		root := find(j)
+		if _, ok := groups[root]; !ok {
+			order = append(order, root)
+		}
+		groups[root] = append(groups[root], result)
+	}
+	ret := make([][]Result, 0)
+	for _, root := range order {
+		if len(groups[root]) > 1 {
+			ret = append(ret, groups[root])
+		}
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimilarity(t *testing.T) {
+	a := Metadata{Title: "Hey Jude", Artist: "The Beatles", Album: "1", Duration: 431 * time.Second}
+	cases := []struct {
+		b        Metadata
+		min, max float64
+	}{
+		{a, 1, 1},
+		{Metadata{Title: "hey jude!", Artist: "the  BEATLES", Album: "1", Duration: 431 * time.Second}, 1, 1},
+		{Metadata{Title: "Hey Jude (Remastered)", Artist: "The Beatles", Album: "1", Duration: 433 * time.Second}, 0.6, 0.9},
+		{Metadata{Title: "Yesterday", Artist: "The Beatles", Album: "Help!", Duration: 125 * time.Second}, 0.2, 0.5},
+		{Metadata{Title: "Hey Jude"}, 1, 1},
+		{Metadata{}, 0, 0},
+	}
+	for _, c := range cases {
+		if got := Similarity(a, c.b, DefaultSimilarityWeights); got < c.min || got > c.max {
+			t.Errorf("%+v: expected a score in [%v, %v], got %v", c.b, c.min, c.max, got)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	md := func(title, artist string) Result {
+		return Result{Path: title, Metadata: Metadata{Title: title, Artist: artist}}
+	}
+	results := []Result{
+		md("Hey Jude", "The Beatles"),
+		md("Yesterday", "The Beatles"),
+		md("Hey Jude.", "Beatles"),
+		{Path: "broken", Err: &BatchError{}},
+		md("Yesterday!", "The Beatles"),
+		md("Help", "Someone Else"),
+	}
+	groups := FindDuplicates(results, 0.8, DefaultSimilarityWeights)
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[0][1].Path != "Hey Jude." || groups[1][0].Path != "Yesterday" || groups[1][1].Path != "Yesterday!" {
+		t.Errorf("Unexpected duplicate groups %v", groups)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+// A Snapshot is a copy of a tag taken by ID3Tag.Snapshot, which shares nothing with the tag or with the
+// tags restored from it. Interactive editors can keep a stack of them to undo edits without reading the
+// file again:
+//
+//	undo = append(undo, id3tag.Snapshot())
+//	id3tag = id3tag.SetText("TIT2", title)
+//	...
+//	id3tag, undo = undo[len(undo)-1].Restore(), undo[0:len(undo)-1]
+type Snapshot struct {
+	tag ID3Tag
+}
+
+// Snapshot returns a deep copy of the tag to restore later. Changing the Data of the tag's frames in
+// place, which the package otherwise forbids, leaves the snapshot as it was.
+func (id3tag ID3Tag) Snapshot() Snapshot {
+	return Snapshot{deep_copy(id3tag)}
+}
+
+// Restore returns a deep copy of the tag the snapshot was taken of, with the options it was read with
+// and the changes reported by Changes at the time. A snapshot can be restored any number of times.
+func (snapshot Snapshot) Restore() ID3Tag {
+	return deep_copy(snapshot.tag)
+}
+
+// deep_copy returns a Clone of the tag that also copies its warnings and original frames
+func deep_copy(id3tag ID3Tag) ID3Tag {
+	unchanged := len(id3tag.original) > 0 && len(id3tag.original) == len(id3tag.Frames) && &id3tag.original[0] == &id3tag.Frames[0]
+	id3tag = id3tag.Clone()
+	if id3tag.Warnings != nil {
+		id3tag.Warnings = append(make([]string, 0, len(id3tag.Warnings)), id3tag.Warnings...)
+	}
+	if unchanged {
+		id3tag.original = id3tag.Frames
+	} else if id3tag.original != nil {
+		id3tag.original = copy_frames(id3tag.original)
+	}
+	return id3tag
+}
@@ -0,0 +1,53 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	buf := make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "TPE1", 0, []byte("\x03Artist")),
+	)
+	id3tag, err := ReadID3Bytes(buf)
+	if err != nil {
+		t.Fatalf("Error in reading the tag: %v", err)
+	}
+
+	undo := []Snapshot{id3tag.Snapshot()}
+	id3tag = id3tag.SetText("TIT2", "Edited")
+	undo = append(undo, id3tag.Snapshot())
+	id3tag = id3tag.WithoutFrames("TPE1")
+
+	//data shared with the buffer read from is changed in place, which snapshots must not see
+	buf[len(buf)-1] = 'X'
+
+	id3tag, undo = undo[len(undo)-1].Restore(), undo[0:len(undo)-1]
+	if txt, _ := id3tag.GetTextFrameData("TPE1"); txt != "Artist" {
+		t.Errorf("Restored TPE1 is %q", txt)
+	}
+	if txt, _ := id3tag.GetTextFrameData("TIT2"); txt != "Edited" {
+		t.Errorf("Restored TIT2 is %q", txt)
+	}
+	if changes := id3tag.Changes(); len(changes) != 1 || changes[0].Kind != FrameModified || changes[0].FrameID != "TIT2" {
+		t.Errorf("Restored tag has changes %v", changes)
+	}
+
+	first := undo[0].Restore()
+	if txt, _ := first.GetTextFrameData("TIT2"); txt != "Title" {
+		t.Errorf("First restored TIT2 is %q", txt)
+	}
+	if first.Changed() {
+		t.Errorf("First restored tag has changes %v", first.Changes())
+	}
+
+	//each restore is a copy of its own
+	first.Frames[0].Data[1] = 'X'
+	again := undo[0].Restore()
+	if !reflect.DeepEqual(again.Frames[0].Data, []byte("\x03Title")) {
+		t.Errorf("Restoring again gave %q", again.Frames[0].Data)
+	}
+}
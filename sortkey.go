@@ -0,0 +1,64 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A Collator returns keys that sort byte by byte in the order texts sort in a language.
+type Collator interface {
+	Key(txt string) []byte
+}
+
+var collation struct {
+	sync.Mutex
+	collator func(locale string) (Collator, error)
+}
+
+// RegisterCollation sets the function SortKey gets the Collator for a locale from. Building with the
+// collate build tag registers collators from golang.org/x/text/collate, which take BCP 47 locales such
+// as "sv" or "de-AT".
+func RegisterCollation(collator func(locale string) (Collator, error)) {
+	collation.Lock()
+	defer collation.Unlock()
+	collation.collator = collator
+}
+
+// sort_frames maps the fields SortKey takes to their sort order frame and their text frame
+var sort_frames = map[string][2]string{
+	"title":       {"TSOT", "TIT2"},
+	"artist":      {"TSOP", "TPE1"},
+	"albumartist": {"TSO2", "TPE2"},
+	"album":       {"TSOA", "TALB"},
+	"composer":    {"TSOC", "TCOM"},
+}
+
+// SortKey returns a key for ordering tags by field, one of title, artist, albumartist, album and composer,
+// the way the language of locale orders text. The sort order frame of the field, such as TSOP for the
+// artist, is used over the text when the tag has it. Without a registered collation locale is ignored and
+// the key orders text case folded and without diacritics, then by the text itself.
+func (id3tag ID3Tag) SortKey(field, locale string) ([]byte, error) {
+	frameids, ok := sort_frames[field]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("Cannot sort by %q", field))
+	}
+	txt, err := id3tag.GetTextFrameData(frameids[0])
+	if err != nil || txt == "" {
+		txt, _ = id3tag.GetTextFrameData(frameids[1])
+	}
+
+	collation.Lock()
+	collator := collation.collator
+	collation.Unlock()
+	if collator == nil {
+		return []byte(Normalize(txt, FoldCase|StripDiacritics) + "\x00" + txt), nil
+	}
+	c, err := collator(locale)
+	if err != nil {
+		return nil, err
+	}
+	return c.Key(txt), nil
+}
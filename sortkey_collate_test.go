@@ -0,0 +1,16 @@
+//go:build collate && !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortKeyCollate(t *testing.T) {
+	artists := sorted_artists(t, "bjork", "Zappa", "\u00c5se", "Bj\u00f6rk", "Abba")
+	want := []string{"Abba", "\u00c5se", "bjork", "Bj\u00f6rk", "Zappa"}
+	if !reflect.DeepEqual(artists, want) {
+		t.Errorf("Unexpected order %q", artists)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !collate && !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSortKeyFallback(t *testing.T) {
+	artists := sorted_artists(t, "bjork", "Zappa", "\u00c5se", "Bj\u00f6rk", "Abba")
+	want := []string{"Abba", "\u00c5se", "Bj\u00f6rk", "bjork", "Zappa"}
+	if !reflect.DeepEqual(artists, want) {
+		t.Errorf("Unexpected order %q", artists)
+	}
+
+	id3tag := ID3Tag{Version: Version24}.SetText("TSOP", "Beatles, The")
+	if key, _ := id3tag.SortKey("artist", "en"); !bytes.HasPrefix(key, []byte("beatles, the")) {
+		t.Errorf("Expected a case folded key, got %q", key)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+// sorted_artists returns artists sorted by the SortKey of tags with each as TPE1
+func sorted_artists(t *testing.T, artists ...string) []string {
+	keys := make(map[string][]byte)
+	for _, artist := range artists {
+		id3tag := ID3Tag{Version: Version24}.SetText("TPE1", artist)
+		key, err := id3tag.SortKey("artist", "en")
+		if err != nil {
+			t.Fatalf("Error in getting sort key: %v", err)
+		}
+		keys[artist] = key
+	}
+	sort.Slice(artists, func(a, b int) bool { return bytes.Compare(keys[artists[a]], keys[artists[b]]) < 0 })
+	return artists
+}
+
+func TestSortKey(t *testing.T) {
+	id3tag := ID3Tag{Version: Version24}.SetText("TPE1", "The Beatles").SetText("TSOP", "Beatles, The")
+	key, _ := id3tag.SortKey("artist", "en")
+	want, _ := ID3Tag{Version: Version24}.SetText("TPE1", "Beatles, The").SortKey("artist", "en")
+	if !bytes.Equal(key, want) {
+		t.Errorf("Expected the TSOP frame to be used, got %q", key)
+	}
+	if _, err := id3tag.SortKey("bpm", "en"); err == nil {
+		t.Errorf("Expected an unknown field to fail")
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Stats summarises the tags of a set of scan results
+type Stats struct {
+	Files        int             //number of results
+	Failed       int             //results with an error, which are not counted otherwise
+	Versions     map[Version]int //tags per version
+	Frames       map[string]int  //tags containing each FrameID at least once
+	Encodings    map[byte]int    //text frames per text encoding
+	Pictures     int             //attached pictures in all tags
+	ArtworkBytes int64           //total image data of those pictures
+}
+
+// AverageArtworkSize returns the average size in bytes of the attached pictures, or 0 if there are none
+func (stats Stats) AverageArtworkSize() float64 {
+	if stats.Pictures == 0 {
+		return 0
+	}
+	return float64(stats.ArtworkBytes) / float64(stats.Pictures)
+}
+
+// GetStats gathers statistics over the tags of results, as returned by Scan or ExtractBatch
+func GetStats(results []Result) Stats {
+	stats := Stats{
+		Versions:  make(map[Version]int),
+		Frames:    make(map[string]int),
+		Encodings: make(map[byte]int),
+	}
+	for _, result := range results {
+		stats.Files++
+		if result.Err != nil {
+			stats.Failed++
+			continue
+		}
+		id3tag := result.Tag
+		stats.Versions[id3tag.Version]++
+		seen := make(map[string]bool)
+		for _, frame := range id3tag.Frames {
+			if !seen[frame.FrameID] {
+				seen[frame.FrameID] = true
+				stats.Frames[frame.FrameID]++
+			}
+			if strings.HasPrefix(frame.FrameID, "T") && len(frame.Data) > 0 {
+				stats.Encodings[frame.Data[0]]++
+			}
+		}
+		pics, _ := id3tag.pictures()
+		for _, pic := range pics {
+			stats.Pictures++
+			stats.ArtworkBytes += int64(len(pic.Data))
+		}
+	}
+	return stats
+}
+
+var encoding_names = map[byte]string{0: "ISO-8859-1", 1: "UTF-16", 2: "UTF-16BE", 3: "UTF-8"}
+
+// Report returns the statistics as a plain text report, with frames listed from the most to the least used
+func (stats Stats) Report() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Files: %d (%d failed)\n", stats.Files, stats.Failed)
+
+	buf.WriteString("Versions:\n")
+	versions := make([]Version, 0, len(stats.Versions))
+	for ver := range stats.Versions {
+		versions = append(versions, ver)
+	}
+	sort.Slice(versions, func(a, b int) bool { return versions[a] < versions[b] })
+	for _, ver := range versions {
+		fmt.Fprintf(&buf, "  ID3v%v: %d\n", ver, stats.Versions[ver])
+	}
+
+	buf.WriteString("Text encodings:\n")
+	encodings := make([]byte, 0, len(stats.Encodings))
+	for encoding := range stats.Encodings {
+		encodings = append(encodings, encoding)
+	}
+	sort.Slice(encodings, func(a, b int) bool { return encodings[a] < encodings[b] })
+	for _, encoding := range encodings {
+		name, ok := encoding_names[encoding]
+		if !ok {
+			name = fmt.Sprintf("invalid (%d)", encoding)
+		}
+		fmt.Fprintf(&buf, "  %s: %d frames\n", name, stats.Encodings[encoding])
+	}
+
+	buf.WriteString("Frames:\n")
+	ids := make([]string, 0, len(stats.Frames))
+	for id := range stats.Frames {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		if stats.Frames[ids[a]] != stats.Frames[ids[b]] {
+			return stats.Frames[ids[a]] > stats.Frames[ids[b]]
+		}
+		return ids[a] < ids[b]
+	})
+	for _, id := range ids {
+		fmt.Fprintf(&buf, "  %s: %d tags\n", id, stats.Frames[id])
+	}
+
+	fmt.Fprintf(&buf, "Artwork: %d pictures, %.0f bytes on average\n", stats.Pictures, stats.AverageArtworkSize())
+	return buf.String()
+}
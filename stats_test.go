@@ -0,0 +1,32 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	results, _ := ExtractBatch([]string{"testdata/test-v23.mp3", "testdata/test-v24.mp3", "README.md"}, 2)
+	stats := GetStats(results)
+	if stats.Files != 3 || stats.Failed != 1 || stats.Versions[Version23] != 1 || stats.Versions[Version24] != 1 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+	if stats.Frames["TALB"] != 2 || stats.Frames["TYER"] != 1 || stats.Frames["TDRC"] != 1 || stats.Frames[""] != 0 {
+		t.Errorf("Unexpected frame counts %v", stats.Frames)
+	}
+	if stats.Pictures != 2 || stats.AverageArtworkSize() != float64(stats.ArtworkBytes)/2 {
+		t.Errorf("Unexpected artwork statistics %d pictures of %d bytes", stats.Pictures, stats.ArtworkBytes)
+	}
+
+	report := stats.Report()
+	for _, want := range []string{"Files: 3 (1 failed)\n", "  ID3v2.3: 1\n", "  TALB: 2 tags\n", "Artwork: 2 pictures"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report lacks %q:\n%s", want, report)
+		}
+	}
+	if strings.Index(report, "TALB") > strings.Index(report, "TYER") {
+		t.Errorf("Frames in every tag should be listed first:\n%s", report)
+	}
+}
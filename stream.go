@@ -0,0 +1,62 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+)
+
+// StreamTitle returns the tag's artist and title as Icecast and Shoutcast in-stream metadata, in the form
+// StreamTitle='Artist - Title'; with the artist left out if the tag has none. The string is not padded
+// into a metadata block; that is left to the streaming code.
+func (id3tag ID3Tag) StreamTitle() string {
+	title, _ := id3tag.GetTitle()
+	if artist, _ := id3tag.GetArtist(); artist != "" {
+		title = artist + " - " + title
+	}
+	return "StreamTitle='" + title + "';"
+}
+
+// ParseStreamMetadata splits Icecast or Shoutcast in-stream metadata such as
+// StreamTitle='Artist - Title';StreamUrl='http://example.com'; into its keys and values. Trailing null
+// padding of the metadata block is ignored. Values are taken up to the next "';" so that quotes within
+// titles, which servers do not escape, survive.
+func ParseStreamMetadata(metadata string) map[string]string {
+	ret := make(map[string]string)
+	rest := strings.TrimRight(metadata, "\x00")
+	for {
+		eq := strings.Index(rest, "='")
+		if eq < 0 {
+			return ret
+		}
+		key := strings.TrimSpace(rest[0:eq])
+		rest = rest[eq+2 : len(rest)]
+		end := strings.Index(rest, "';")
+		if end < 0 {
+			ret[key] = strings.TrimSuffix(rest, "'")
+			return ret
+		}
+		ret[key] = rest[0:end]
+		rest = rest[end+2 : len(rest)]
+	}
+}
+
+// TagFromStream returns a minimal tag of version ver for a recording of an internet radio stream, holding
+// the title and the artist found in the StreamTitle of metadata, split at the first " - ", and the
+// station's StreamUrl as a WORS frame.
+func TagFromStream(metadata string, ver Version) ID3Tag {
+	fields := ParseStreamMetadata(metadata)
+	id3tag := ID3Tag{Version: ver}
+	title := strings.TrimSpace(fields["StreamTitle"])
+	if sep := strings.Index(title, " - "); sep >= 0 {
+		id3tag = id3tag.SetText("TPE1", strings.TrimSpace(title[0:sep]))
+		title = strings.TrimSpace(title[sep+3 : len(title)])
+	}
+	if title != "" {
+		id3tag = id3tag.SetText("TIT2", title)
+	}
+	if url := fields["StreamUrl"]; url != "" {
+		id3tag = id3tag.WithFrame(ID3Frame{FrameID: "WORS", Length: uint32(len(url)), Data: []byte(url)})
+	}
+	return id3tag
+}
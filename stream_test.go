@@ -0,0 +1,37 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStreamTitle(t *testing.T) {
+	metadata := "StreamTitle='Artist - Don't Stop';StreamUrl='http://radio.example';\x00\x00\x00"
+	fields := ParseStreamMetadata(metadata)
+	if fields["StreamTitle"] != "Artist - Don't Stop" || fields["StreamUrl"] != "http://radio.example" {
+		t.Errorf("Unexpected fields %q", fields)
+	}
+
+	id3tag := TagFromStream(metadata, Version24)
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, id3tag); err != nil {
+		t.Fatalf("Error in writing tag: %v", err)
+	}
+	read, err := ReadID3Bytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	title, _ := read.GetTitle()
+	artist, _ := read.GetArtist()
+	if title != "Don't Stop" || artist != "Artist" || len(read.GetTagData("WORS")) != 1 {
+		t.Errorf("Unexpected tag with title %q and artist %q", title, artist)
+	}
+	if got := read.StreamTitle(); got != "StreamTitle='Artist - Don't Stop';" {
+		t.Errorf("Unexpected stream title %q", got)
+	}
+	if got := TagFromStream("StreamTitle='Station ID';", Version23).StreamTitle(); got != "StreamTitle='Station ID';" {
+		t.Errorf("Unexpected stream title without artist %q", got)
+	}
+}
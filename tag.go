@@ -0,0 +1,201 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"sort"
+	"strings"
+)
+
+// Find returns every frame for which match returns true, in tag order. For instance all the ReplayGain
+// values stored in TXXX frames can be found with
+//
+//	id3tag.Find(func(f ID3Frame) bool {
+//		return f.FrameID == "TXXX" && strings.HasPrefix(f.Description(), "replaygain_")
+//	})
+func (id3tag ID3Tag) Find(match func(f ID3Frame) bool) []ID3Frame {
+	ret := make([]ID3Frame, 0)
+	for _, frame := range id3tag.Frames {
+		if match(frame) {
+			ret = append(ret, frame)
+		}
+	}
+	return ret
+}
+
+// Description returns the content description of TXXX, WXXX, COMM and USLT frames, which tells apart
+// frames of those types within a tag, and "" for every other frame.
+func (frame ID3Frame) Description() string {
+	data := frame.Data
+	switch {
+	case (frame.FrameID == "TXXX" || frame.FrameID == "WXXX") && len(data) > 0:
+		desc, _ := decodetext(data[0], data[1:len(data)])
+		return desc
+	case (frame.FrameID == "COMM" || frame.FrameID == "USLT") && len(data) > 4:
+		desc, _ := decodetext(data[0], data[4:len(data)])
+		return desc
+	}
+	return ""
+}
+
+// Bytes returns the Data of the frame itself. It is shared with the tag the frame belongs to, every copy
+// of that tag and, for tags read with ReadID3Bytes, the buffer read from, so it must not be changed; use
+// CopyData to get data that can be.
+func (frame ID3Frame) Bytes() []byte {
+	return frame.Data
+}
+
+// CopyData returns a copy of the Data of the frame, which the caller is free to change.
+func (frame ID3Frame) CopyData() []byte {
+	if frame.Data == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(frame.Data)), frame.Data...)
+}
+
+// Order returns the FrameIDs of the tag in the order the frames were read.
+func (id3tag ID3Tag) Order() []string {
+	ids := make([]string, len(id3tag.Frames))
+	for j, frame := range id3tag.Frames {
+		ids[j] = frame.FrameID
+	}
+	return ids
+}
+
+// Reorder returns a copy of the tag with its frames moved into priority order, for writers targeting players
+// that only scan the first few kilobytes of a tag. Each priority entry is a FrameID or a prefix ending in
+// "*", so []string{"APIC", "T*"} puts pictures first followed by all text frames. Frames keep their relative
+// order within an entry, and frames matching no entry follow in their original order. A copy whose order
+// changed is altered, like those of the other mutators, so WriteID3 drops frames flagged DiscardOnTagAlter.
+func (id3tag ID3Tag) Reorder(priority []string) ID3Tag {
+	rank := func(frameid string) int {
+		for j, entry := range priority {
+			if match_frameid(entry, frameid) {
+				return j
+			}
+		}
+		return len(priority)
+	}
+
+	order := make([]int, len(id3tag.Frames))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rank(id3tag.Frames[order[a]].FrameID) < rank(id3tag.Frames[order[b]].FrameID)
+	})
+	frames := make([]ID3Frame, len(order))
+	for j, from := range order {
+		frames[j] = id3tag.Frames[from]
+		//moving a frame alters the tag, as a mutator removing and adding it would
+		if from != j {
+			id3tag.altered = true
+		}
+	}
+	id3tag.Frames = frames
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// Clone returns a deep copy of the tag whose frames share no Data with the original.
+func (id3tag ID3Tag) Clone() ID3Tag {
+	id3tag.Frames = copy_frames(id3tag.Frames)
+	id3tag.cache = id3tag.fresh_cache()
+	if id3tag.ExtendedHeader != nil {
+		exthdr := *id3tag.ExtendedHeader
+		id3tag.ExtendedHeader = &exthdr
+	}
+	return id3tag
+}
+
+func copy_frames(frames []ID3Frame) []ID3Frame {
+	ret := make([]ID3Frame, len(frames))
+	for j, frame := range frames {
+		ret[j] = copy_frame(frame)
+	}
+	return ret
+}
+
+func copy_frame(frame ID3Frame) ID3Frame {
+	frame.Data = frame.CopyData()
+	return frame
+}
+
+// WithFrame returns a copy of the tag with a copy of frame added after the existing frames.
+func (id3tag ID3Tag) WithFrame(frame ID3Frame) ID3Tag {
+	frames := make([]ID3Frame, 0, len(id3tag.Frames)+1)
+	id3tag.Frames = append(append(frames, id3tag.Frames...), copy_frame(frame))
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// WithoutFrames returns a copy of the tag without any of its frames with the given FrameID.
+func (id3tag ID3Tag) WithoutFrames(frameid string) ID3Tag {
+	return id3tag.ReplaceFrames(frameid)
+}
+
+// ReplaceFrames returns a copy of the tag in which all frames with the given FrameID are replaced by copies
+// of frames, placed where the first of the replaced frames was or after the existing frames if there was
+// none. frames need not themselves have the FrameID.
+func (id3tag ID3Tag) ReplaceFrames(frameid string, frames ...ID3Frame) ID3Tag {
+	ret := make([]ID3Frame, 0, len(id3tag.Frames)+len(frames))
+	placed := false
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID != frameid {
+			ret = append(ret, frame)
+		} else if !placed {
+			ret = append(ret, copy_frames(frames)...)
+			placed = true
+		}
+	}
+	if !placed {
+		ret = append(ret, copy_frames(frames)...)
+	}
+	id3tag.Frames = ret
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// SetText returns a copy of the tag in which the text frame frameid holds values, encoded as suits the tag's
+// version. Passing no values removes the frame.
+func (id3tag ID3Tag) SetText(frameid string, values ...string) ID3Tag {
+	if len(values) == 0 {
+		return id3tag.WithoutFrames(frameid)
+	}
+	data := encodetext(id3tag.Version, values...)
+	return id3tag.ReplaceFrames(frameid, ID3Frame{FrameID: frameid, Length: uint32(len(data)), Data: data})
+}
+
+// SetUserText returns a copy of the tag in which the TXXX frame with the given description, compared
+// without regard to case, holds values. Values are encoded as SetText encodes them. Passing no values
+// removes the frame.
+func (id3tag ID3Tag) SetUserText(description string, values ...string) ID3Tag {
+	frames := make([]ID3Frame, 0, len(id3tag.Frames)+1)
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID != "TXXX" || !strings.EqualFold(frame.Description(), description) {
+			frames = append(frames, frame)
+		}
+	}
+	if len(values) > 0 {
+		data := user_text_data(id3tag.Version, description, values)
+		frames = append(frames, ID3Frame{FrameID: "TXXX", Length: uint32(len(data)), Data: data})
+	}
+	id3tag.Frames = frames
+	id3tag.altered = true
+	id3tag.cache = id3tag.fresh_cache()
+	return id3tag
+}
+
+// user_text_data returns the body of a TXXX frame with the given description holding values for a tag of
+// version ver, with values joined as encodetext joins them
+func user_text_data(ver Version, description string, values []string) []byte {
+	encoding := text_encoding(ver, append([]string{description}, values...)...)
+	value := strings.Join(values, "\x00")
+	if ver == Version22 || ver == Version23 {
+		value = strings.Join(values, "/")
+	}
+	data := append([]byte{encoding}, encode_string(encoding, description)...)
+	return append(append(data, string_terminator(encoding)...), encode_string(encoding, value)...)
+}
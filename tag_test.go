@@ -0,0 +1,187 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReorder(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x00Title")),
+		make_frame(4, "COMM", 0, []byte("\x00eng\x00Comment")),
+		make_frame(4, "APIC", 0, []byte("\x00image/png\x00\x03\x00PNG")),
+		make_frame(4, "TALB", 0, []byte("\x00Album")),
+		make_frame(4, "PRIV", 0, []byte("owner\x00data")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	before := id3tag.Order()
+
+	reordered := id3tag.Reorder([]string{"APIC", "T*"})
+	want := []string{"APIC", "TIT2", "TALB", "COMM", "PRIV"}
+	if got := reordered.Order(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(id3tag.Order(), before) {
+		t.Errorf("Reorder should not change the original tag")
+	}
+	if !reordered.altered || id3tag.Reorder([]string{"TIT2"}).altered {
+		t.Errorf("Expected only a changed order to alter the tag")
+	}
+}
+
+func TestFind(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TXXX", 0, []byte("\x00replaygain_track_gain\x00-6.20 dB")),
+		make_frame(4, "TXXX", 0, []byte("\x00CATALOGNUMBER\x00ABC-123")),
+		make_frame(4, "TIT2", 0, []byte("\x00Title")),
+		make_frame(4, "TXXX", 0, []byte("\x01\xff\xfer\x00e\x00p\x00l\x00a\x00y\x00g\x00a\x00i\x00n\x00_\x00a\x00\x00\x00\xff\xfe1\x00")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	found := id3tag.Find(func(f ID3Frame) bool {
+		return f.FrameID == "TXXX" && strings.HasPrefix(f.Description(), "replaygain_")
+	})
+	if len(found) != 2 || found[0].Description() != "replaygain_track_gain" || found[1].Description() != "replaygain_a" {
+		t.Errorf("Unexpected frames found: %+v", found)
+	}
+	if none := id3tag.Find(func(f ID3Frame) bool { return f.FrameID == "APIC" }); len(none) != 0 {
+		t.Errorf("Expected no APIC frames, got %v", len(none))
+	}
+}
+
+func TestAllText(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title\x00")),
+		make_frame(4, "TPE1", 0, []byte("\x03First\x00Second")),
+		make_frame(4, "TCON", 0, []byte("\x01\xff\xfeR\x00o\x00c\x00k\x00\x00\x00P\x00o\x00p\x00")),
+		make_frame(4, "TXXX", 0, []byte("\x00CATALOGNUMBER\x00ABC-123")),
+		make_frame(4, "APIC", 0, []byte("\x00image/png\x00\x03\x00PNG")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	want := map[string][]string{
+		"TIT2":               {"Title"},
+		"TPE1":               {"First", "Second"},
+		"TCON":               {"Rock", "Pop"},
+		"TXXX:CATALOGNUMBER": {"ABC-123"},
+	}
+	if got := id3tag.AllText(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestCopyOnWrite(t *testing.T) {
+	original, err := ReadID3Bytes(make_tag(3,
+		make_frame(3, "TIT2", 0, []byte("\x00Title")),
+		make_frame(3, "TPE1", 0, []byte("\x00Artist")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	before := original.Clone()
+
+	//concurrent edits of a shared tag must neither race nor show through in the original
+	var wg sync.WaitGroup
+	for j := 0; j < 8; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			edited := original.SetText("TIT2", "Bj\u00f6rk \u0416").WithoutFrames("TPE1").WithFrame(ID3Frame{FrameID: "TALB", Data: []byte("\x00Album")})
+			if title, _ := edited.GetTitle(); title != "Bj\u00f6rk \u0416" {
+				t.Errorf("Unexpected edited title %q", title)
+			}
+			if got := edited.Order(); !reflect.DeepEqual(got, []string{"TIT2", "TALB"}) {
+				t.Errorf("Unexpected edited frames %v", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(original.Frames, before.Frames) {
+		t.Errorf("Mutators changed the original tag")
+	}
+
+	//the frames of a copy are its own, but only a Clone has Data of its own
+	copied := original.SetText("TPE2", "Band")
+	copied.Frames[0] = ID3Frame{FrameID: "TIT3", Data: []byte("\x00Subtitle")}
+	copied.Frames = append(copied.Frames, ID3Frame{FrameID: "TCOM", Data: []byte("\x00Composer")})
+	if !reflect.DeepEqual(original.Frames, before.Frames) {
+		t.Errorf("Changing the frames of a copy changed the original tag")
+	}
+	chaptered, _ := original.AddChapter(0, time.Second, "Intro")
+	mutated := map[string]ID3Tag{
+		"AddChapter":      chaptered,
+		"SetGenres":       original.SetGenres([]string{"Rock"}),
+		"SetSyncedLyrics": original.SetSyncedLyrics(SyncedLyrics{Language: "eng"}),
+		"SetPlayCount":    original.SetPlayCount(3),
+		"SetRating":       original.SetRating(LinearRating, 3),
+		"SetReplayGain":   original.SetReplayGain(GainInfo{}, GainInfo{}),
+		"Reorder":         original.Reorder([]string{"TPE1"}),
+		"ReplaceFrames":   original.ReplaceFrames("TPE1"),
+		"SetUserText":     original.SetUserText("mood", "calm"),
+	}
+	if converted, _, err := original.Convert(Version24); err == nil {
+		mutated["Convert"] = converted
+	}
+	for name, mutant := range mutated {
+		for j := range mutant.Frames {
+			mutant.Frames[j] = ID3Frame{FrameID: "XXXX"}
+		}
+		if !reflect.DeepEqual(original.Frames, before.Frames) {
+			t.Errorf("Changing the frames returned by %v changed the original tag", name)
+		}
+	}
+	cloned := original.Clone()
+	cloned.Frames[0].Data[1] = 'X'
+	if !reflect.DeepEqual(original.Frames, before.Frames) {
+		t.Errorf("Changing the data of a clone changed the original tag")
+	}
+
+	frame := ID3Frame{FrameID: "TALB", Data: []byte("\x00Album")}
+	edited := original.WithFrame(frame)
+	frame.Data[1] = 'X'
+	if album, _ := edited.GetAlbum(); album != "Album" {
+		t.Errorf("WithFrame should copy the frame data, got album %q", album)
+	}
+
+	//v2.3 text that does not fit ISO-8859-1 is written as UTF-16, v2.4 text as UTF-8
+	if data := original.SetText("TIT2", "\u0416").GetTagData("TIT2")[0]; data[0] != 1 {
+		t.Errorf("Expected UTF-16 for non Latin-1 v2.3 text, got encoding %v", data[0])
+	}
+	original.Version = Version24
+	if data := original.SetText("TPE1", "A", "B").GetTagData("TPE1")[0]; !bytes.Equal(data, []byte("\x03A\x00B")) {
+		t.Errorf("Unexpected v2.4 text frame %q", data)
+	}
+}
+
+func TestTagDataAliasing(t *testing.T) {
+	raw := make_tag(4, make_frame(4, "TIT2", 0, []byte("\x03Title")))
+	id3tag, err := ReadID3Bytes(raw)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	id3tag.GetTagData("TIT2")[0][1] = 'X'
+	id3tag.Frames[0].CopyData()[2] = 'X'
+	if title, _ := id3tag.GetTitle(); title != "Title" {
+		t.Errorf("Changing copied data changed the tag: %q", title)
+	}
+
+	aliased, err := ReadID3Bytes(raw, WithAliasedTagData(true))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	data := aliased.GetTagData("TIT2")[0]
+	if &data[0] != &aliased.Frames[0].Bytes()[0] {
+		t.Errorf("Expected the tag data to be aliased")
+	}
+}
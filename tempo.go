@@ -0,0 +1,60 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"errors"
+)
+
+// TempoCodes is the content of a SYTC frame: the tempo of the music and the times it changes. Timestamps
+// are milliseconds from the start of the audio unless MPEGFrames is set, in which case they count MPEG
+// frames.
+type TempoCodes struct {
+	MPEGFrames bool
+	Changes    []TempoChange
+}
+
+// A TempoChange gives the tempo from Timestamp on in beats per minute. A BPM of 0 marks music without a
+// beat and 1 a single beat stroke followed by music without a beat.
+type TempoChange struct {
+	Timestamp uint32
+	BPM       int
+}
+
+// GetTempoCodes returns the tempo codes of the SYTC frame of the tag, or of the STC frame of a v2.2 tag.
+func (id3tag ID3Tag) GetTempoCodes() (TempoCodes, error) {
+	for _, frameid := range []string{"SYTC", "STC"} {
+		if framedatas := id3tag.tag_data(frameid); len(framedatas) > 0 {
+			return parse_sytc(framedatas[0])
+		}
+	}
+	return TempoCodes{}, errors.New("No such frame SYTC found in the taglist")
+}
+
+// parse_sytc parses a SYTC frame body: the timestamp format followed by tempos, each one byte with 0xFF
+// adding the byte after it for tempos from 255 to 510 BPM, and the four byte timestamp they start at
+func parse_sytc(framedata []byte) (TempoCodes, error) {
+	var codes TempoCodes
+	if len(framedata) == 0 {
+		return codes, errors.New("SYTC frame is empty")
+	}
+	codes.MPEGFrames = framedata[0] == 1
+	data := framedata[1:len(framedata)]
+	codes.Changes = make([]TempoChange, 0)
+	for len(data) > 0 {
+		var change TempoChange
+		change.BPM = int(data[0])
+		data = data[1:len(data)]
+		if change.BPM == 0xFF && len(data) > 0 {
+			change.BPM += int(data[0])
+			data = data[1:len(data)]
+		}
+		if len(data) < 4 {
+			return codes, errors.New("SYTC frame ends within a tempo code")
+		}
+		change.Timestamp, _ = convert_regular_int(data[0:4])
+		codes.Changes = append(codes.Changes, change)
+		data = data[4:len(data)]
+	}
+	return codes, nil
+}
@@ -0,0 +1,29 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTempoCodes(t *testing.T) {
+	sytc := []byte("\x02\x00\x00\x00\x00\x00\x78\x00\x00\x03\xe8\xff\x05\x00\x00\x07\xd0")
+	id3tag, err := ReadID3Bytes(make_tag(4, make_frame(4, "SYTC", 0, sytc)))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	codes, err := id3tag.GetTempoCodes()
+	want := TempoCodes{Changes: []TempoChange{{0, 0}, {1000, 120}, {2000, 260}}}
+	if err != nil || !reflect.DeepEqual(codes, want) {
+		t.Errorf("Unexpected tempo codes %+v, %v", codes, err)
+	}
+
+	id3tag, err = ReadID3Bytes(make_tag(4, make_frame(4, "SYTC", 0, []byte("\x01\x78\x00\x00"))))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	if _, err := id3tag.GetTempoCodes(); err == nil {
+		t.Errorf("Expected a truncated frame to fail")
+	}
+}
@@ -0,0 +1,61 @@
+package id3v2reader
+
+import (
+	"sync"
+)
+
+// text_cache memoizes the text decoded from frame data so that repeated getter calls decode each frame
+// only once. Entries are keyed by the data itself rather than by frame ID, and the mutators give the copies
+// they return a fresh cache, so that entries never outlive the frames of the tag they were decoded for and
+// changed copies never see each other's frames. The cache is safe for concurrent use, so
+// a tag can be shared by the goroutines of an HTTP server: the map is guarded by mu while each entry is
+// decoded under its own sync.Once, so that a slow decode holds up only the callers wanting that frame.
+type text_cache struct {
+	mu      sync.Mutex
+	entries map[text_cache_key]*text_cache_entry
+}
+
+type text_cache_key struct {
+	data   *byte
+	length int
+	values bool //whether all values were decoded or only the first
+}
+
+type text_cache_entry struct {
+	once   sync.Once
+	values []string
+	err    error
+}
+
+func new_text_cache() *text_cache {
+	return &text_cache{entries: make(map[text_cache_key]*text_cache_entry)}
+}
+
+// fresh_cache returns an empty cache for a changed copy of the tag, or nil if the tag is not cached
+func (id3tag ID3Tag) fresh_cache() *text_cache {
+	if id3tag.cache == nil {
+		return nil
+	}
+	return new_text_cache()
+}
+
+// cached_text returns what decode returns for data, calling it only the first time data is decoded for
+// tags read with caching. The values returned are a copy the caller may change.
+func (id3tag ID3Tag) cached_text(data []byte, values bool, decode func() ([]string, error)) ([]string, error) {
+	cache := id3tag.cache
+	if cache == nil || len(data) == 0 {
+		return decode()
+	}
+	key := text_cache_key{&data[0], len(data), values}
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		entry = new(text_cache_entry)
+		cache.entries[key] = entry
+	}
+	cache.mu.Unlock()
+	entry.once.Do(func() {
+		entry.values, entry.err = decode()
+	})
+	return append([]string(nil), entry.values...), entry.err
+}
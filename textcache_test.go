@@ -0,0 +1,78 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestTextCache(t *testing.T) {
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		make_frame(4, "TIT2", 0, []byte("\x03Title")),
+		make_frame(4, "TCON", 0, []byte("\x03Rock\x00Pop")),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	for j := 0; j < 3; j++ {
+		if title, _ := id3tag.GetTitle(); title != "Title" {
+			t.Errorf("Unexpected title %q", title)
+		}
+		genres, _ := id3tag.GetGenres()
+		if len(genres) != 2 || genres[0] != "Rock" {
+			t.Errorf("Unexpected genres %q", genres)
+		}
+		genres[0] = "changed by the caller"
+	}
+	if n := len(id3tag.cache.entries); n != 2 {
+		t.Errorf("Expected the two frames to be decoded once each, got %v cache entries", n)
+	}
+
+	retitled := id3tag.SetText("TIT2", "Other")
+	if title, _ := retitled.GetTitle(); title != "Other" {
+		t.Errorf("Expected the new title, got %q", title)
+	}
+	if title, _ := id3tag.GetTitle(); title != "Title" {
+		t.Errorf("Expected the original tag to keep its title, got %q", title)
+	}
+	//the changed copy decodes into a cache of its own, which the original does not grow by
+	if retitled.cache == nil || retitled.cache == id3tag.cache || len(id3tag.cache.entries) != 2 {
+		t.Errorf("Expected the changed copy to have a fresh cache")
+	}
+}
+
+// TestConcurrentGetters is meant to be run with -race
+func TestConcurrentGetters(t *testing.T) {
+	raw, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(raw)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	var wg sync.WaitGroup
+	for j := 0; j < 8; j++ {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			for k := 0; k < 50; k++ {
+				if title, _ := id3tag.GetTitle(); title != "Sine Wave at 440 Hz \u00df\u00c4\u00dc" {
+					t.Errorf("Unexpected title %q", title)
+				}
+				id3tag.GetMetadata()
+				id3tag.AllText()
+				if j%2 == 0 {
+					edited := id3tag.SetText("TALB", "Album "+strconv.Itoa(k))
+					if album, _ := edited.GetAlbum(); album != "Album "+strconv.Itoa(k) {
+						t.Errorf("Unexpected album %q", album)
+					}
+				}
+			}
+		}(j)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,79 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+// frame_translations lists the IDs of equivalent frames in v2.2, v2.3 and v2.4, with "" where a version
+// has none. Where several frames of one version map to one frame of another, the first row wins when
+// translating back, so TDRC becomes TYER in v2.3.
+var frame_translations = [][3]string{
+	{"TYE", "TYER", "TDRC"}, {"TDA", "TDAT", "TDRC"}, {"TIM", "TIME", "TDRC"}, {"TRD", "TRDA", "TDRC"},
+	{"TOR", "TORY", "TDOR"}, {"IPL", "IPLS", "TIPL"}, {"IPL", "IPLS", "TMCL"},
+	{"RVA", "RVAD", "RVA2"}, {"EQU", "EQUA", "EQU2"}, {"TSI", "TSIZ", ""}, {"CRM", "", ""},
+	{"BUF", "RBUF", "RBUF"}, {"CNT", "PCNT", "PCNT"}, {"COM", "COMM", "COMM"}, {"CRA", "AENC", "AENC"},
+	{"ETC", "ETCO", "ETCO"}, {"GEO", "GEOB", "GEOB"}, {"LNK", "LINK", "LINK"}, {"MCI", "MCDI", "MCDI"},
+	{"MLL", "MLLT", "MLLT"}, {"PIC", "APIC", "APIC"}, {"POP", "POPM", "POPM"}, {"REV", "RVRB", "RVRB"},
+	{"SLT", "SYLT", "SYLT"}, {"STC", "SYTC", "SYTC"}, {"TAL", "TALB", "TALB"}, {"TBP", "TBPM", "TBPM"},
+	{"TCM", "TCOM", "TCOM"}, {"TCO", "TCON", "TCON"}, {"TCR", "TCOP", "TCOP"}, {"TDY", "TDLY", "TDLY"},
+	{"TEN", "TENC", "TENC"}, {"TFT", "TFLT", "TFLT"}, {"TKE", "TKEY", "TKEY"}, {"TLA", "TLAN", "TLAN"},
+	{"TLE", "TLEN", "TLEN"}, {"TMT", "TMED", "TMED"}, {"TOA", "TOPE", "TOPE"}, {"TOF", "TOFN", "TOFN"},
+	{"TOL", "TOLY", "TOLY"}, {"TOT", "TOAL", "TOAL"}, {"TP1", "TPE1", "TPE1"}, {"TP2", "TPE2", "TPE2"},
+	{"TP3", "TPE3", "TPE3"}, {"TP4", "TPE4", "TPE4"}, {"TPA", "TPOS", "TPOS"}, {"TPB", "TPUB", "TPUB"},
+	{"TRC", "TSRC", "TSRC"}, {"TRK", "TRCK", "TRCK"}, {"TSS", "TSSE", "TSSE"}, {"TT1", "TIT1", "TIT1"},
+	{"TT2", "TIT2", "TIT2"}, {"TT3", "TIT3", "TIT3"}, {"TXT", "TEXT", "TEXT"}, {"TXX", "TXXX", "TXXX"},
+	{"UFI", "UFID", "UFID"}, {"ULT", "USLT", "USLT"}, {"WAF", "WOAF", "WOAF"}, {"WAR", "WOAR", "WOAR"},
+	{"WAS", "WOAS", "WOAS"}, {"WCM", "WCOM", "WCOM"}, {"WCP", "WCOP", "WCOP"}, {"WPB", "WPUB", "WPUB"},
+	{"WXX", "WXXX", "WXXX"},
+}
+
+// TranslateFrameID returns the ID that frames with the ID frameid in a tag of version from take in a
+// tag of version to, following the renaming of v2.2 frames and the frames v2.4 replaced: TYER, TDAT,
+// TIME and TRDA become TDRC, TORY becomes TDOR, IPLS becomes TIPL, RVAD becomes RVA2 and EQUA becomes
+// EQU2, and the other way round. It returns false if version to has no equivalent frame, such as for
+// TSIZ in v2.4 or the sort order frames in v2.3. Frame IDs that no version defines are kept as they are
+// between v2.3 and v2.4, whose IDs have the same form.
+func TranslateFrameID(frameid string, from, to Version) (string, bool) {
+	from_col, to_col := int(from)-2, int(to)-2
+	if from_col < 0 || from_col > 2 || to_col < 0 || to_col > 2 {
+		return "", false
+	}
+	if from == to {
+		return frameid, true
+	}
+	for _, row := range frame_translations {
+		if row[from_col] == frameid {
+			return row[to_col], row[to_col] != ""
+		}
+	}
+	if from == Version22 || to == Version22 {
+		return "", false
+	}
+	if info, ok := LookupFrame(frameid); ok && !info.AllowedIn(to) {
+		return "", false
+	}
+	return frameid, true
+}
+
+// native_frameid returns the ID that frames with the v2.3 ID frameid have in a tag of version ver, so
+// that code adding or replacing frames by their v2.3 ID keeps v2.2 tags valid
+func native_frameid(ver Version, frameid string) string {
+	if ver != Version22 {
+		return frameid
+	}
+	if id, ok := TranslateFrameID(frameid, Version23, Version22); ok {
+		return id
+	}
+	return frameid
+}
+
+// v23_frameid returns the v2.3 ID of frames with the ID frameid in a tag of version ver, so that code
+// matching v2.3 and v2.4 IDs handles the frames of v2.2 tags too. v2.2 frames without a v2.3 equivalent,
+// such as CRM, and the frames of other versions keep their ID.
+func v23_frameid(ver Version, frameid string) string {
+	if ver != Version22 {
+		return frameid
+	}
+	if id, ok := TranslateFrameID(frameid, Version22, Version23); ok {
+		return id
+	}
+	return frameid
+}
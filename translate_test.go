@@ -0,0 +1,38 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestTranslateFrameID(t *testing.T) {
+	cases := []struct {
+		frameid  string
+		from, to Version
+		want     string
+		ok       bool
+	}{
+		{"TYER", Version23, Version24, "TDRC", true},
+		{"TDRC", Version24, Version23, "TYER", true},
+		{"TDAT", Version23, Version24, "TDRC", true},
+		{"EQUA", Version23, Version24, "EQU2", true},
+		{"RVA2", Version24, Version23, "RVAD", true},
+		{"TMCL", Version24, Version23, "IPLS", true},
+		{"IPLS", Version23, Version24, "TIPL", true},
+		{"TT2", Version22, Version24, "TIT2", true},
+		{"TYE", Version22, Version24, "TDRC", true},
+		{"APIC", Version24, Version22, "PIC", true},
+		{"TIT2", Version23, Version24, "TIT2", true},
+		{"XABC", Version24, Version23, "XABC", true},
+		{"TSIZ", Version23, Version24, "", false},
+		{"TSOP", Version24, Version23, "", false},
+		{"TSOP", Version24, Version22, "", false},
+		{"TIT2", Version24, Version(5), "", false},
+	}
+	for _, c := range cases {
+		if got, ok := TranslateFrameID(c.frameid, c.from, c.to); got != c.want || ok != c.ok {
+			t.Errorf("TranslateFrameID(%v, %v, %v) = %v, %v, expected %v, %v", c.frameid, c.from, c.to, got, ok, c.want, c.ok)
+		}
+	}
+}
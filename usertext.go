@@ -0,0 +1,81 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	user_text_mu     sync.Mutex
+	user_text_fields = map[string]string{
+		"album artist":                      "AlbumArtist",
+		"albumartist":                       "AlbumArtist",
+		"album_artist":                      "AlbumArtist",
+		"catalognumber":                     "CatalogNumber",
+		"catalog number":                    "CatalogNumber",
+		"barcode":                           "Barcode",
+		"upc":                               "Barcode",
+		"label":                             "Label",
+		"asin":                              "ASIN",
+		"releasecountry":                    "ReleaseCountry",
+		"musicbrainz album release country": "ReleaseCountry",
+		"releasetype":                       "ReleaseType",
+		"musicbrainz album type":            "ReleaseType",
+		"releasestatus":                     "ReleaseStatus",
+		"musicbrainz album status":          "ReleaseStatus",
+		"originalyear":                      "OriginalYear",
+		"script":                            "Script",
+		"musicbrainz album id":              "MusicBrainzAlbumID",
+		"musicbrainz artist id":             "MusicBrainzArtistID",
+		"musicbrainz album artist id":       "MusicBrainzAlbumArtistID",
+		"musicbrainz release group id":      "MusicBrainzReleaseGroupID",
+		"musicbrainz release track id":      "MusicBrainzReleaseTrackID",
+		"replaygain_track_gain":             "ReplayGainTrackGain",
+		"replaygain_track_peak":             "ReplayGainTrackPeak",
+		"replaygain_album_gain":             "ReplayGainAlbumGain",
+		"replaygain_album_peak":             "ReplayGainAlbumPeak",
+		"mp3gain_minmax":                    "MP3GainMinMax",
+		"mp3gain_album_minmax":              "MP3GainAlbumMinMax",
+		"mp3gain_undo":                      "MP3GainUndo",
+	}
+)
+
+// RegisterUserText maps TXXX frames with the given description, compared without regard to case, to the
+// named field of Metadata.Extra. The conventions of foobar2000, MusicBrainz Picard, MusicBee and MP3Gain
+// for album artists, catalogue numbers, release identifiers and ReplayGain are registered already;
+// registering a description again replaces its field.
+func RegisterUserText(description, field string) {
+	user_text_mu.Lock()
+	defer user_text_mu.Unlock()
+	user_text_fields[strings.ToLower(description)] = field
+}
+
+// GetUserTextFields returns the values of the tag's TXXX frames whose descriptions are registered with
+// RegisterUserText, keyed by field. Where several frames map to the same field the first one wins.
+func (id3tag ID3Tag) GetUserTextFields() map[string]string {
+	user_text_mu.Lock()
+	fields := make(map[string]string, len(user_text_fields))
+	for description, field := range user_text_fields {
+		fields[description] = field
+	}
+	user_text_mu.Unlock()
+
+	ret := make(map[string]string)
+	for _, frame := range id3tag.Frames {
+		if frame.FrameID != "TXXX" || len(frame.Data) == 0 {
+			continue
+		}
+		values, err := id3tag.decodetext_values(frame.Data[0], frame.Data[1:len(frame.Data)])
+		if err != nil || len(values) < 2 {
+			continue
+		}
+		field, ok := fields[strings.ToLower(values[0])]
+		if _, seen := ret[field]; !ok || seen {
+			continue
+		}
+		ret[field] = strings.Join(values[1:len(values)], "; ")
+	}
+	return ret
+}
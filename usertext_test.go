@@ -0,0 +1,41 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"testing"
+)
+
+func TestUserTextFields(t *testing.T) {
+	txxx := func(desc, value string) []byte {
+		return make_frame(4, "TXXX", 0, []byte("\x03"+desc+"\x00"+value))
+	}
+	id3tag, err := ReadID3Bytes(make_tag(4,
+		txxx("ALBUM ARTIST", "Various Artists"),
+		txxx("CATALOGNUMBER", "CAT-001"),
+		txxx("replaygain_track_gain", "-6.50 dB"),
+		txxx("Mood", "Calm"),
+		txxx("AlbumArtist", "Ignored"),
+	))
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+
+	md := id3tag.GetMetadata()
+	if md.AlbumArtist != "Various Artists" || md.Extra["CatalogNumber"] != "CAT-001" || md.Extra["ReplayGainTrackGain"] != "-6.50 dB" {
+		t.Errorf("Unexpected metadata %+v", md)
+	}
+	if _, ok := md.Extra["Mood"]; ok {
+		t.Errorf("Unregistered description surfaced in %v", md.Extra)
+	}
+
+	RegisterUserText("mood", "Mood")
+	defer func() {
+		user_text_mu.Lock()
+		delete(user_text_fields, "mood")
+		user_text_mu.Unlock()
+	}()
+	if fields := id3tag.GetUserTextFields(); fields["Mood"] != "Calm" {
+		t.Errorf("Registered description not surfaced in %v", fields)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build fsnotify
+
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FSNotify is a Notifier using the notifications of the operating system
+type FSNotify struct {
+	watcher *fsnotify.Watcher
+	changes chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewFSNotify watches the tree rooted at root, including directories created in it later
+func NewFSNotify(root string) (*FSNotify, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	n := &FSNotify{watcher: watcher, changes: make(chan string), done: make(chan struct{})}
+	if err := n.add_tree(root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	go n.run()
+	return n, nil
+}
+
+func (n *FSNotify) add_tree(root string) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return n.watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func (n *FSNotify) run() {
+	defer close(n.changes)
+	for {
+		select {
+		case event, ok := <-n.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				n.add_tree(event.Name) //a new directory is watched; for a new file this does nothing
+			}
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				select {
+				case n.changes <- event.Name:
+				case <-n.done:
+					return
+				}
+			}
+		case _, ok := <-n.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (n *FSNotify) Changes() <-chan string {
+	return n.changes
+}
+
+// Close stops watching
+func (n *FSNotify) Close() error {
+	n.once.Do(func() { close(n.done) })
+	return n.watcher.Close()
+}
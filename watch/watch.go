@@ -0,0 +1,142 @@
+// Package watch re-reads the tags of audio files as they change, for media servers keeping their library
+// up to date. Changes are found by a Notifier: the Poller in this package needs nothing beyond the standard
+// library, and building with the fsnotify tag adds NewFSNotify, which uses operating system notifications
+// through github.com/fsnotify/fsnotify.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srinathh/id3v2reader"
+)
+
+// A Notifier reports the paths of files that were created, changed or removed. The channel is closed
+// once the notifier is closed.
+type Notifier interface {
+	Changes() <-chan string
+	Close() error
+}
+
+// An Event reports that the file at Path changed, in which case Result holds its newly read tag, or
+// that it was removed.
+type Event struct {
+	Path    string
+	Removed bool
+	Result  id3v2reader.Result
+}
+
+// Watch reads the tag of every .mp3 file n reports and calls handler with the outcome, until ctx is done
+// or n is closed. handler is called on the goroutine calling Watch, one event at a time.
+func Watch(ctx context.Context, n Notifier, handler func(Event), opts ...id3v2reader.Option) error {
+	changes := n.Changes()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case path, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if !strings.EqualFold(filepath.Ext(path), ".mp3") {
+				continue
+			}
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				handler(Event{Path: path, Removed: true})
+				continue
+			}
+			results, _ := id3v2reader.ExtractBatch([]string{path}, 1, opts...)
+			handler(Event{Path: path, Result: results[0]})
+		}
+	}
+}
+
+// A Poller is a Notifier that walks a directory tree at a fixed interval and reports files whose size or
+// modification time changed, which works on any file system, network shares included.
+type Poller struct {
+	changes chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+type file_state struct {
+	size  int64
+	mtime time.Time
+}
+
+// NewPoller starts polling the tree rooted at root every interval. Files present at the start are not
+// reported.
+func NewPoller(root string, interval time.Duration) *Poller {
+	p := &Poller{changes: make(chan string), done: make(chan struct{})}
+	known := snapshot(root)
+	go func() {
+		defer close(p.changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+			}
+			current := snapshot(root)
+			for path, state := range current {
+				if old, ok := known[path]; !ok || old != state {
+					if !p.send(path) {
+						return
+					}
+				}
+			}
+			for path := range known {
+				if _, ok := current[path]; !ok {
+					if !p.send(path) {
+						return
+					}
+				}
+			}
+			known = current
+		}
+	}()
+	return p
+}
+
+func (p *Poller) send(path string) bool {
+	select {
+	case p.changes <- path:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// snapshot returns the size and modification time of every regular file under root. Parts of the tree
+// that cannot be read are left out.
+func snapshot(root string) map[string]file_state {
+	ret := make(map[string]file_state)
+	filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			ret[path] = file_state{info.Size(), info.ModTime()}
+		}
+		return nil
+	})
+	return ret
+}
+
+func (p *Poller) Changes() <-chan string {
+	return p.changes
+}
+
+// Close stops polling
+func (p *Poller) Close() error {
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
@@ -0,0 +1,53 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	mp3, err := os.ReadFile("../testdata/test-v23.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	poller := NewPoller(dir, 10*time.Millisecond)
+	defer poller.Close()
+
+	events := make(chan Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Watch(ctx, poller, func(event Event) { events <- event })
+
+	next := func() Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+		return Event{}
+	}
+
+	path := filepath.Join(dir, "song.mp3")
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644)
+	//written under another name first so that the poller never sees a partial file
+	if err := os.WriteFile(path+".tmp", mp3, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		t.Fatal(err)
+	}
+	event := next()
+	if event.Path != path || event.Removed || event.Result.Err != nil || event.Result.Metadata.Album != "ID3 Tag Test" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	os.Remove(path)
+	if event := next(); event.Path != path || !event.Removed {
+		t.Errorf("Expected a removal, got %+v", event)
+	}
+}
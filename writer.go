@@ -0,0 +1,317 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// A WriteOption changes how WriteID3 writes a tag.
+type WriteOption func(*write_config)
+
+type write_config struct {
+	tag_altered  bool
+	file_altered bool
+	padding      int64 //-1 keeps the padding the tag was read with
+	backup       bool
+
+	has_restrictions bool
+	restrictions     byte
+}
+
+func new_write_config(opts []WriteOption) write_config {
+	cfg := write_config{padding: -1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// TagAltered tells WriteID3 that the tag was changed since it was read, so frames flagged
+// DiscardOnTagAlter are dropped as the standard requires. Tags changed through the ID3Tag mutator
+// methods are treated as altered without this option.
+func TagAltered() WriteOption {
+	return func(cfg *write_config) {
+		cfg.tag_altered = true
+	}
+}
+
+// FileAltered tells WriteID3 that the audio the tag belongs to was changed, so frames flagged
+// DiscardOnFileAlter are dropped as the standard requires.
+func FileAltered() WriteOption {
+	return func(cfg *write_config) {
+		cfg.file_altered = true
+	}
+}
+
+// WithPadding sets the number of zero bytes written after the last frame. By default the tag's
+// PaddingBytes are kept so there is room to grow the tag in place later.
+func WithPadding(padding uint32) WriteOption {
+	return func(cfg *write_config) {
+		cfg.padding = int64(padding)
+	}
+}
+
+// WriteID3 writes id3tag to w as an ID3v2.2, v2.3 or v2.4 tag according to its Version, or v2.4 if the
+// version is not set; the frames of v2.2 tags must have v2.2 IDs and no flags, which v2.2 does not have. Frames are written in order with their raw StatusFlags and FormatFlags, and the group symbol,
+// encryption method and data length fields those flags call for are put back in front of their Data, so
+// a tag that was read and not altered is written back frame for frame. The extended header is only written
+// to declare the tag restrictions of v2.4 tags, which are those given with WithRestrictions or else those
+// the tag declares, and the tag must keep to them.
+func WriteID3(w io.Writer, id3tag ID3Tag, opts ...WriteOption) error {
+	buf, err := encode_tag(id3tag, new_write_config(opts))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// WriteStream writes id3tag to w as WriteID3 does, followed by the audio read from audio until EOF, and
+// returns the number of bytes written. The tag is built in full before anything is written, so neither
+// w nor audio needs to seek, which suits pipes and network streams. An ID3v2 tag at the start of audio is
+// dropped so that id3tag takes its place.
+func WriteStream(w io.Writer, id3tag ID3Tag, audio io.Reader, opts ...WriteOption) (int64, error) {
+	buf, err := encode_tag(id3tag, new_write_config(opts))
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 10)
+	n, err := io.ReadFull(audio, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, err
+	}
+	header = header[0:n]
+	if skip, ok := stream_tag_length(header); ok {
+		if _, err := io.CopyN(io.Discard, audio, skip); err != nil {
+			return 0, errors.New(fmt.Sprintf("Could not skip the tag at the start of the stream: %v", err))
+		}
+		header = nil
+	}
+
+	written, err := w.Write(append(buf, header...))
+	if err != nil {
+		return int64(written), err
+	}
+	copied, err := io.Copy(w, audio)
+	return int64(written) + copied, err
+}
+
+// valid_frameid reports whether frameid is a well formed v2.3 or v2.4 frame ID
+func valid_frameid(frameid string) bool {
+	return len(frameid) == 4 && valid_frameid_bytes([]byte(frameid), false)
+}
+
+func encode_tag(id3tag ID3Tag, cfg write_config) ([]byte, error) {
+	ver := id3tag.Version
+	if ver == 0 {
+		ver = Version24
+	}
+	if ver != Version22 && ver != Version23 && ver != Version24 {
+		return nil, errors.New(fmt.Sprintf("Cannot write ID3v%v tags", ver))
+	}
+
+	var body bytes.Buffer
+	written := make([]ID3Frame, 0, len(id3tag.Frames))
+	for _, frame := range id3tag.Frames {
+		if (cfg.tag_altered || id3tag.altered) && frame.DiscardOnTagAlter || cfg.file_altered && frame.DiscardOnFileAlter {
+			continue
+		}
+		encoded, err := encode_frame(ver, frame)
+		if err != nil {
+			return nil, err
+		}
+		body.Write(encoded)
+		written = append(written, frame)
+	}
+
+	restricted, restrictions := cfg.has_restrictions, cfg.restrictions
+	if !restricted && id3tag.ExtendedHeader != nil && id3tag.ExtendedHeader.HasRestrictions {
+		restricted, restrictions = true, id3tag.ExtendedHeader.Restrictions
+	}
+	var exthdr []byte
+	if restricted && ver == Version24 {
+		exthdr = encode_restrictions_header(restrictions, id3tag.ExtendedHeader != nil && id3tag.ExtendedHeader.Update)
+	} else if cfg.has_restrictions {
+		return nil, errors.New(fmt.Sprintf("Cannot declare restrictions in ID3v%v tags", ver))
+	}
+
+	padding := int64(id3tag.PaddingBytes)
+	if cfg.padding >= 0 {
+		padding = cfg.padding
+	}
+	size := int64(len(exthdr)) + int64(body.Len()) + padding
+	if size > 0x0FFFFFFF {
+		return nil, errors.New(fmt.Sprintf("Tag size %v exceeds the 256MB an ID3v2 header can declare", size))
+	}
+	if exthdr != nil {
+		if err := id3tag.check_restrictions(restrictions, written, 10+size); err != nil {
+			return nil, err
+		}
+	}
+
+	var flags byte
+	if exthdr != nil {
+		flags |= 0x40
+	}
+	if id3tag.Experimental && ver != Version22 {
+		flags |= 0x20
+	}
+	buf := make([]byte, 0, 10+size)
+	buf = append(buf, 'I', 'D', '3', byte(ver), 0, flags)
+	buf = append(buf, encode_synchsafe_int(uint32(size))...)
+	buf = append(buf, exthdr...)
+	buf = append(buf, body.Bytes()...)
+	return append(buf, make([]byte, padding)...), nil
+}
+
+// encode_restrictions_header returns a v2.4 extended header declaring restrictions, flagged as an update
+// if update is set
+func encode_restrictions_header(restrictions byte, update bool) []byte {
+	flags := byte(0x10)
+	flag_data := []byte{1, restrictions}
+	if update {
+		flags |= 0x40
+		flag_data = append([]byte{0}, flag_data...)
+	}
+	exthdr := encode_synchsafe_int(uint32(6 + len(flag_data)))
+	return append(append(exthdr, 1, flags), flag_data...)
+}
+
+// encode_frame returns the frame header and body for frame in a tag of version ver
+func encode_frame(ver Version, frame ID3Frame) ([]byte, error) {
+	if ver == Version22 {
+		return encode_v22_frame(frame)
+	}
+	if !valid_frameid(frame.FrameID) {
+		return nil, errors.New(fmt.Sprintf("Invalid frame ID %q", frame.FrameID))
+	}
+
+	prefix := make([]byte, 0, 6)
+	if ver == Version23 {
+		if frame.FormatFlags&0x80 != 0 {
+			prefix = append(prefix, encode_regular_int(frame.DataLength)...)
+		}
+		if frame.FormatFlags&0x40 != 0 {
+			prefix = append(prefix, frame.EncryptionMethod)
+		}
+		if frame.FormatFlags&0x20 != 0 {
+			prefix = append(prefix, frame.GroupSymbol)
+		}
+	} else {
+		if frame.FormatFlags&0x40 != 0 {
+			prefix = append(prefix, frame.GroupSymbol)
+		}
+		if frame.FormatFlags&0x04 != 0 {
+			prefix = append(prefix, frame.EncryptionMethod)
+		}
+		if frame.FormatFlags&0x01 != 0 {
+			prefix = append(prefix, encode_synchsafe_int(frame.DataLength)...)
+		}
+	}
+
+	size := uint64(len(prefix)) + uint64(len(frame.Data))
+	if ver == Version24 && size > 0x0FFFFFFF || size > 0xFFFFFFFF {
+		return nil, errors.New(fmt.Sprintf("Frame %v is too large to write", frame.FrameID))
+	}
+	buf := make([]byte, 0, 10+size)
+	buf = append(buf, frame.FrameID...)
+	if ver == Version23 {
+		buf = append(buf, encode_regular_int(uint32(size))...)
+	} else {
+		buf = append(buf, encode_synchsafe_int(uint32(size))...)
+	}
+	buf = append(buf, frame.StatusFlags, frame.FormatFlags)
+	buf = append(buf, prefix...)
+	return append(buf, frame.Data...), nil
+}
+
+// encodetext returns the body of a text frame holding values for a tag of version ver. v2.4 text is written
+// as UTF-8 with values separated by zero bytes. v2.2 and v2.3 have no UTF-8 and no multiple values, so
+// values are joined with "/" and written as ISO-8859-1 when possible and as UTF-16 with a BOM otherwise.
+func encodetext(ver Version, values ...string) []byte {
+	if ver != Version22 && ver != Version23 {
+		return append([]byte{3}, strings.Join(values, "\x00")...)
+	}
+	txt := strings.Join(values, "/")
+	encoding := text_encoding(ver, txt)
+	return append([]byte{encoding}, encode_string(encoding, txt)...)
+}
+
+// text_encoding returns the encoding to write strs in within a tag of version ver: UTF-8 for v2.4, and
+// for v2.2 and v2.3 ISO-8859-1 if all of strs fit in it and UTF-16 otherwise
+func text_encoding(ver Version, strs ...string) byte {
+	if ver != Version22 && ver != Version23 {
+		return 3
+	}
+	for _, str := range strs {
+		for _, r := range str {
+			if r > 0xFF {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// encode_string returns str in encoding 0, 1 or 3 without a terminator. UTF-16 is written little endian
+// with a BOM.
+func encode_string(encoding byte, str string) []byte {
+	switch encoding {
+	case 0:
+		buf := make([]byte, 0, len(str))
+		for _, r := range str {
+			buf = append(buf, byte(r))
+		}
+		return buf
+	case 1:
+		utf16buf := utf16.Encode([]rune(str))
+		buf := make([]byte, 2, 2+2*len(utf16buf))
+		buf[0], buf[1] = 0xFF, 0xFE
+		for _, unit := range utf16buf {
+			buf = append(buf, byte(unit), byte(unit>>8))
+		}
+		return buf
+	}
+	return []byte(str)
+}
+
+// string_terminator returns the terminator of strings in encoding
+func string_terminator(encoding byte) []byte {
+	if encoding == 1 || encoding == 2 {
+		return []byte{0, 0}
+	}
+	return []byte{0}
+}
+
+func encode_synchsafe_int(n uint32) []byte {
+	return []byte{byte(n>>21) & 0x7F, byte(n>>14) & 0x7F, byte(n>>7) & 0x7F, byte(n) & 0x7F}
+}
+
+func encode_regular_int(n uint32) []byte {
+	return []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+}
+
+// encode_v22_frame returns the frame header, a three character ID and a three byte size, and body for
+// frame in a v2.2 tag
+func encode_v22_frame(frame ID3Frame) ([]byte, error) {
+	if len(frame.FrameID) != 3 || !valid_frameid_bytes([]byte(frame.FrameID), false) {
+		return nil, errors.New(fmt.Sprintf("Invalid ID3v2.2 frame ID %q", frame.FrameID))
+	}
+	if frame.StatusFlags != 0 || frame.FormatFlags != 0 {
+		return nil, errors.New(fmt.Sprintf("Frame %v has flags, which ID3v2.2 frames cannot have", frame.FrameID))
+	}
+	size := uint32(len(frame.Data))
+	if len(frame.Data) > 0xFFFFFF {
+		return nil, errors.New(fmt.Sprintf("Frame %v is too large to write", frame.FrameID))
+	}
+	buf := make([]byte, 0, 6+len(frame.Data))
+	buf = append(append(buf, frame.FrameID...), byte(size>>16), byte(size>>8), byte(size))
+	return append(buf, frame.Data...), nil
+}
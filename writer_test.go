@@ -0,0 +1,132 @@
+//go:build !id3v2core
+
+package id3v2reader
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	for _, filname := range []string{"testdata/test-v23.mp3", "testdata/test-v24.mp3"} {
+		data, err := os.ReadFile(filname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id3tag, err := ReadID3Bytes(data)
+		if err != nil {
+			t.Fatalf("Error in reading tag: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteID3(&buf, id3tag); err != nil {
+			t.Fatalf("%v: error in writing tag: %v", filname, err)
+		}
+		//an unaltered tag without an extended header is written back byte for byte
+		if original := data[0 : 10+id3tag.Size]; !bytes.Equal(buf.Bytes(), original) {
+			t.Errorf("%v: written tag differs from the original", filname)
+		}
+	}
+
+	//reserved flag bits and the fields the format flags call for survive a round trip
+	v24 := append([]byte{0x05}, synchsafe(1000)...)
+	flagged := make_frame(4, "GEOB", 0x41, append(v24, "abc"...))
+	flagged[8] = 0x01
+	raw := make_tag(4, flagged)
+	id3tag, err := ReadID3Bytes(raw)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteID3(&buf, id3tag); err != nil {
+		t.Fatalf("Error in writing tag: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), raw) {
+		t.Errorf("Expected %v, wrote %v", raw, buf.Bytes())
+	}
+}
+
+func TestWritePreservationFlags(t *testing.T) {
+	for _, ver := range []byte{3, 4} {
+		tag_alter, file_alter := byte(0x40), byte(0x20)
+		if ver == 3 {
+			tag_alter, file_alter = 0x80, 0x40
+		}
+		frames := [][]byte{
+			make_frame(ver, "TIT2", 0, []byte("\x00Title")),
+			make_frame(ver, "PRIV", 0, []byte("tag alter\x00")),
+			make_frame(ver, "ETCO", 0, []byte("file alter")),
+		}
+		frames[1][8] = tag_alter
+		frames[2][8] = file_alter
+		id3tag, err := ReadID3Bytes(make_tag(ver, frames...))
+		if err != nil {
+			t.Fatalf("v2.%v: error in reading tag: %v", ver, err)
+		}
+		if !id3tag.Frames[1].DiscardOnTagAlter || !id3tag.Frames[2].DiscardOnFileAlter {
+			t.Fatalf("v2.%v: preservation flags not decoded: %+v", ver, id3tag.Frames)
+		}
+
+		cases := []struct {
+			opts []WriteOption
+			want []string
+		}{
+			{nil, []string{"TIT2", "PRIV", "ETCO"}},
+			{[]WriteOption{TagAltered()}, []string{"TIT2", "ETCO"}},
+			{[]WriteOption{FileAltered()}, []string{"TIT2", "PRIV"}},
+			{[]WriteOption{TagAltered(), FileAltered()}, []string{"TIT2"}},
+		}
+		for _, c := range cases {
+			var buf bytes.Buffer
+			if err := WriteID3(&buf, id3tag, c.opts...); err != nil {
+				t.Fatalf("v2.%v: error in writing tag: %v", ver, err)
+			}
+			written, err := ReadID3Bytes(buf.Bytes())
+			if err != nil {
+				t.Fatalf("v2.%v: error in reading written tag: %v", ver, err)
+			}
+			if got := written.Order(); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("v2.%v: expected frames %v, got %v", ver, c.want, got)
+			}
+		}
+	}
+}
+
+func TestWriteStream(t *testing.T) {
+	data, err := os.ReadFile("testdata/test-v24.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3tag, err := ReadID3Bytes(data)
+	if err != nil {
+		t.Fatalf("Error in reading tag: %v", err)
+	}
+	id3tag = id3tag.SetText("TIT2", "Streamed")
+	audio := data[10+id3tag.Size : len(data)]
+
+	for _, input := range [][]byte{data, audio, []byte("short")} {
+		var out bytes.Buffer
+		//hide everything but Read so nothing can seek
+		n, err := WriteStream(struct{ io.Writer }{&out}, id3tag, struct{ io.Reader }{bytes.NewReader(input)})
+		if err != nil || n != int64(out.Len()) {
+			t.Fatalf("Error in streaming: %v, %d bytes reported for %d written", err, n, out.Len())
+		}
+		written, err := ReadID3(&out)
+		if err != nil {
+			t.Fatalf("Error in reading streamed tag: %v", err)
+		}
+		if title, _ := written.GetTitle(); title != "Streamed" {
+			t.Errorf("Unexpected title %q", title)
+		}
+		want := audio
+		if len(input) < 10 {
+			want = input
+		}
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Errorf("Audio following the streamed tag differs from the input audio")
+		}
+	}
+}